@@ -0,0 +1,169 @@
+// Package account lists the databases an account key gives access to, through
+// the Fauna Account API, so that tools can let users pick a region group and
+// database without shelling out to the Fauna CLI:
+//
+//	client := account.NewClient(os.Getenv("FAUNA_ACCOUNT_KEY"))
+//	dbs, err := client.Databases(ctx, "")
+//	if err != nil {
+//		return err
+//	}
+//	for _, db := range dbs {
+//		fmt.Println(db.Path)
+//	}
+//
+// Account keys are created in the Fauna dashboard or with the CLI, and are
+// distinct from the keys and secrets of databases. The child databases of a
+// database are listed with its secret by the admin package instead.
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultURL is the URL of the Fauna Account API.
+const DefaultURL = "https://account.fauna.com"
+
+// pageSize is the number of databases asked for per request.
+const pageSize = 100
+
+// RegionGroup is a Fauna region group, where databases are replicated.
+type RegionGroup struct {
+	// Name is the region group's name in database paths, such as "us-std".
+	Name string
+	// Alias is the short name the Fauna CLI accepts for it, such as "us".
+	Alias       string
+	Description string
+}
+
+// RegionGroups are the region groups of Fauna. The Account API has no
+// endpoint listing them, so they are listed here.
+var RegionGroups = []RegionGroup{
+	{Name: "us-std", Alias: "us", Description: "United States"},
+	{Name: "eu-std", Alias: "eu", Description: "Europe"},
+	{Name: "global", Alias: "global", Description: "Global"},
+}
+
+// Database is a database of the account.
+type Database struct {
+	Name string `json:"name"`
+	// Path is the database's path, starting with its region group, such as
+	// "us-std/app/tenant_1".
+	Path        string `json:"path"`
+	RegionGroup string `json:"region_group"`
+	GlobalID    string `json:"global_id"`
+}
+
+// Error is an error returned by the Account API.
+type Error struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Reason     string `json:"reason"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("account API returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("account API returned status %d: %s: %s", e.StatusCode, e.Code, e.Reason)
+}
+
+// Client calls the Fauna Account API with an account key.
+type Client struct {
+	key  string
+	url  string
+	http *http.Client
+}
+
+// Option sets an option of a [Client].
+type Option func(*Client)
+
+// URL sets the URL of the Account API. Defaults to [DefaultURL].
+func URL(url string) Option {
+	return func(c *Client) { c.url = url }
+}
+
+// HTTPClient sets the [http.Client] requests are sent with. Defaults to
+// [http.DefaultClient].
+func HTTPClient(client *http.Client) Option {
+	return func(c *Client) { c.http = client }
+}
+
+// NewClient returns a client authenticating with key, an account key.
+func NewClient(key string, opts ...Option) *Client {
+	c := &Client{key: key, url: DefaultURL, http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Databases returns the databases in path, such as "us-std" or
+// "us-std/app", or the top-level databases of every region group if path is
+// empty. It follows the pages of results until it has all of them.
+func (c *Client) Databases(ctx context.Context, path string) ([]Database, error) {
+	databases := []Database{}
+	var next string
+	for {
+		params := url.Values{"max_results": {strconv.Itoa(pageSize)}}
+		if path != "" {
+			params.Set("path", path)
+		}
+		if next != "" {
+			params.Set("next_token", next)
+		}
+
+		var page struct {
+			Results   []Database `json:"results"`
+			NextToken string     `json:"next_token"`
+		}
+		if err := c.get(ctx, "/api/v1/databases/list", params, &page); err != nil {
+			return nil, fmt.Errorf("failed to list databases: %w", err)
+		}
+
+		databases = append(databases, page.Results...)
+		if page.NextToken == "" || page.NextToken == next {
+			return databases, nil
+		}
+		next = page.NextToken
+	}
+}
+
+// get sends a GET request to the Account API and decodes its response into
+// out.
+func (c *Client) get(ctx context.Context, path string, params url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.url, "/")+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.key)
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		apiErr := &Error{StatusCode: res.StatusCode}
+		_ = json.Unmarshal(body, apiErr)
+		return apiErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
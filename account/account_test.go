@@ -0,0 +1,57 @@
+package account_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3/account"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabases(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/databases/list", r.URL.Path)
+		require.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		queries = append(queries, r.URL.RawQuery)
+
+		if r.URL.Query().Get("next_token") == "" {
+			_, _ = w.Write([]byte(`{"results":[{"name":"app","path":"us-std/app","region_group":"us-std","global_id":"1"}],"next_token":"abc"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"name":"web","path":"eu-std/web","region_group":"eu-std","global_id":"2"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := account.NewClient("key", account.URL(server.URL))
+	dbs, err := client.Databases(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, []account.Database{
+		{Name: "app", Path: "us-std/app", RegionGroup: "us-std", GlobalID: "1"},
+		{Name: "web", Path: "eu-std/web", RegionGroup: "eu-std", GlobalID: "2"},
+	}, dbs)
+	require.Equal(t, []string{"max_results=100", "max_results=100&next_token=abc"}, queries)
+
+	queries = nil
+	_, err = client.Databases(context.Background(), "us-std/app")
+	require.NoError(t, err)
+	require.Equal(t, "max_results=100&path=us-std%2Fapp", queries[0])
+}
+
+func TestDatabasesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code":"unauthorized","reason":"Invalid account key"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := account.NewClient("bad", account.URL(server.URL)).Databases(context.Background(), "")
+
+	var apiErr *account.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	require.Equal(t, "unauthorized", apiErr.Code)
+	require.EqualError(t, err, "failed to list databases: account API returned status 401: unauthorized: Invalid account key")
+}
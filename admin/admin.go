@@ -0,0 +1,167 @@
+// Package admin manages child databases and keys with typed helpers, passing
+// names and settings to Fauna as query arguments rather than splicing them
+// into FQL:
+//
+//	db, err := admin.CreateDatabase(ctx, client, admin.DatabaseOptions{Name: "tenant_1"})
+//	if err != nil {
+//		return err
+//	}
+//
+//	key, err := admin.CreateKey(ctx, client, admin.KeyOptions{Role: "server", Database: db.Name})
+//
+// The client's secret must have the admin role in the parent database.
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// Database is a child database.
+type Database struct {
+	Name        string         `fauna:"name"`
+	TS          time.Time      `fauna:"ts"`
+	GlobalID    string         `fauna:"global_id"`
+	Typechecked bool           `fauna:"typechecked"`
+	Protected   bool           `fauna:"protected"`
+	Priority    *int           `fauna:"priority"`
+	Data        map[string]any `fauna:"data"`
+}
+
+// DatabaseOptions are the settings of a database created with
+// [CreateDatabase]. Unset fields take Fauna's defaults.
+type DatabaseOptions struct {
+	Name        string
+	Typechecked *bool
+	Protected   *bool
+	Priority    *int
+	Data        map[string]any
+}
+
+// Key is a key, granting access to a database with a role.
+type Key struct {
+	ID       string         `fauna:"id"`
+	TS       time.Time      `fauna:"ts"`
+	Role     string         `fauna:"role"`
+	Database string         `fauna:"database"`
+	TTL      *time.Time     `fauna:"ttl"`
+	Data     map[string]any `fauna:"data"`
+
+	// Secret is the key's secret, only returned by [CreateKey].
+	Secret string `fauna:"secret"`
+}
+
+// KeyOptions are the settings of a key created with [CreateKey].
+type KeyOptions struct {
+	// Role is the role of the key, such as "admin", "server",
+	// "server-readonly", or the name of a user-defined role.
+	Role string
+	// Database is the child database the key grants access to, or empty for
+	// the client's database.
+	Database string
+	// TTL is when the key expires, if set.
+	TTL  time.Time
+	Data map[string]any
+}
+
+// CreateDatabase creates a child database of the client's database.
+func CreateDatabase(ctx context.Context, client *fauna.Client, opts DatabaseOptions) (*Database, error) {
+	if opts.Name == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	fields := map[string]any{"name": opts.Name}
+	if opts.Typechecked != nil {
+		fields["typechecked"] = *opts.Typechecked
+	}
+	if opts.Protected != nil {
+		fields["protected"] = *opts.Protected
+	}
+	if opts.Priority != nil {
+		fields["priority"] = *opts.Priority
+	}
+	if opts.Data != nil {
+		fields["data"] = opts.Data
+	}
+
+	var db Database
+	if err := query(ctx, client, `Database.create(${fields})`, map[string]any{"fields": fields}, &db); err != nil {
+		return nil, err
+	}
+	return &db, nil
+}
+
+// DeleteDatabase deletes the child database named name, along with its
+// contents. It fails if the database doesn't exist.
+func DeleteDatabase(ctx context.Context, client *fauna.Client, name string) error {
+	return query(ctx, client, `Database.byName(${name})!.delete()
+null`, map[string]any{"name": name}, nil)
+}
+
+// ListDatabases returns the child databases of the client's database.
+func ListDatabases(ctx context.Context, client *fauna.Client) ([]Database, error) {
+	q, err := fauna.FQL(`Database.all()`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbs []Database
+	pages := client.PaginateWithContext(ctx, q)
+	for pages.HasNext() {
+		page, err := pages.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var pageDBs []Database
+		if err := page.Unmarshal(&pageDBs); err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, pageDBs...)
+	}
+	return dbs, nil
+}
+
+// CreateKey creates a key, returning its secret in [Key.Secret].
+func CreateKey(ctx context.Context, client *fauna.Client, opts KeyOptions) (*Key, error) {
+	if opts.Role == "" {
+		return nil, errors.New("key role is required")
+	}
+
+	fields := map[string]any{"role": opts.Role}
+	if opts.Database != "" {
+		fields["database"] = opts.Database
+	}
+	if !opts.TTL.IsZero() {
+		fields["ttl"] = opts.TTL
+	}
+	if opts.Data != nil {
+		fields["data"] = opts.Data
+	}
+
+	var key Key
+	if err := query(ctx, client, `Key.create(${fields})`, map[string]any{"fields": fields}, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// query runs fql with args, decoding its result into into, if not nil.
+func query(ctx context.Context, client *fauna.Client, fql string, args map[string]any, into any) error {
+	q, err := fauna.FQL(fql, args)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.QueryWithContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	if into == nil {
+		return nil
+	}
+	return res.Unmarshal(into)
+}
@@ -0,0 +1,116 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/admin"
+	"github.com/stretchr/testify/require"
+)
+
+// newAdminServer responds to each query with the result for the first of
+// results whose key the query contains, recording the queries' arguments.
+func newAdminServer(t *testing.T, results map[string]string) (*fauna.Client, *[]any) {
+	var args []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query struct {
+				FQL []json.RawMessage `json:"fql"`
+			} `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		var fql strings.Builder
+		for _, part := range body.Query.FQL {
+			var literal string
+			if json.Unmarshal(part, &literal) == nil {
+				fql.WriteString(literal)
+				continue
+			}
+
+			var arg struct {
+				Value any `json:"value"`
+			}
+			require.NoError(t, json.Unmarshal(part, &arg))
+			args = append(args, arg.Value)
+		}
+
+		for prefix, result := range results {
+			if strings.Contains(fql.String(), prefix) {
+				_, _ = w.Write([]byte(`{"data":` + result + `,"txn_ts":1,"stats":{}}`))
+				return
+			}
+		}
+		t.Fatalf("unexpected query %s", fql.String())
+	}))
+	t.Cleanup(server.Close)
+
+	return fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL)), &args
+}
+
+func TestDatabases(t *testing.T) {
+	client, args := newAdminServer(t, map[string]string{
+		"Database.create(": `{"@doc":{"name":"tenant_1","coll":{"@mod":"Database"},"ts":{"@time":"2024-01-02T03:04:05Z"},"global_id":"ysjowue14yyr1","typechecked":true,"priority":{"@int":"5"},"data":{"plan":"pro"}}}`,
+		"Database.all()":   `{"@set":{"data":[{"@doc":{"name":"tenant_1","coll":{"@mod":"Database"},"ts":{"@time":"2024-01-02T03:04:05Z"}}}],"after":"next"}}`,
+		"Set.paginate(":    `{"@set":{"data":[{"@doc":{"name":"tenant_2","coll":{"@mod":"Database"},"ts":{"@time":"2024-01-02T03:04:05Z"}}}]}}`,
+		"Database.byName(": `null`,
+	})
+	ctx := context.Background()
+
+	priority, typechecked := 5, true
+	db, err := admin.CreateDatabase(ctx, client, admin.DatabaseOptions{
+		Name:        `tenant_1") { drop }`,
+		Typechecked: &typechecked,
+		Priority:    &priority,
+		Data:        map[string]any{"plan": "pro"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &admin.Database{
+		Name:        "tenant_1",
+		TS:          time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		GlobalID:    "ysjowue14yyr1",
+		Typechecked: true,
+		Priority:    &priority,
+		Data:        map[string]any{"plan": "pro"},
+	}, db)
+	require.Equal(t, map[string]any{
+		"name":        `tenant_1") { drop }`,
+		"typechecked": true,
+		"priority":    map[string]any{"@int": "5"},
+		"data":        map[string]any{"plan": "pro"},
+	}, (*args)[0], "the name is passed as an argument")
+
+	dbs, err := admin.ListDatabases(ctx, client)
+	require.NoError(t, err)
+	require.Len(t, dbs, 2)
+	require.Equal(t, "tenant_2", dbs[1].Name)
+
+	require.NoError(t, admin.DeleteDatabase(ctx, client, "tenant_1"))
+	require.Equal(t, "tenant_1", (*args)[len(*args)-1])
+
+	_, err = admin.CreateDatabase(ctx, client, admin.DatabaseOptions{})
+	require.EqualError(t, err, "database name is required")
+}
+
+func TestCreateKey(t *testing.T) {
+	client, args := newAdminServer(t, map[string]string{
+		"Key.create(": `{"@doc":{"id":"383","coll":{"@mod":"Key"},"ts":{"@time":"2024-01-02T03:04:05Z"},"role":"server","database":"tenant_1","secret":"fnAFXX"}}`,
+	})
+	ctx := context.Background()
+
+	key, err := admin.CreateKey(ctx, client, admin.KeyOptions{Role: "server", Database: "tenant_1"})
+	require.NoError(t, err)
+	require.Equal(t, "383", key.ID)
+	require.Equal(t, "fnAFXX", key.Secret)
+	require.Equal(t, "tenant_1", key.Database)
+	require.Equal(t, map[string]any{"role": "server", "database": "tenant_1"}, (*args)[0])
+
+	_, err = admin.CreateKey(ctx, client, admin.KeyOptions{})
+	require.EqualError(t, err, "key role is required")
+}
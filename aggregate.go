@@ -0,0 +1,147 @@
+package fauna
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+)
+
+// AggregateResult is the result of aggregating a field over every item of a
+// set with helpers such as [SumField].
+type AggregateResult struct {
+	// Value is the aggregate of the field, or zero if no item had a numeric
+	// value for it.
+	Value float64
+
+	// Count is the number of items with a numeric value for the field.
+	Count int
+
+	// Items is the number of items read.
+	Items int
+
+	// Pages is the number of pages, and so queries, read.
+	Pages int
+
+	// Stats is the sum of the stats of every query made.
+	Stats Stats
+}
+
+// SumField pages through the set returned by fql and sums the numeric value of
+// field in each item. Use it when the set is too large to aggregate in a
+// single query. field may be a dotted path such as "totals.amount"; items
+// without a numeric value for it are skipped.
+func SumField(ctx context.Context, client *Client, fql *Query, field string, opts ...QueryOptFn) (*AggregateResult, error) {
+	return aggregateField(ctx, client, fql, field, opts, func(acc, v float64) float64 { return acc + v })
+}
+
+// AvgField is like [SumField], but returns the mean of the field's values.
+func AvgField(ctx context.Context, client *Client, fql *Query, field string, opts ...QueryOptFn) (*AggregateResult, error) {
+	res, err := SumField(ctx, client, fql, field, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Count > 0 {
+		res.Value /= float64(res.Count)
+	}
+	return res, nil
+}
+
+// MinField is like [SumField], but returns the smallest of the field's values.
+func MinField(ctx context.Context, client *Client, fql *Query, field string, opts ...QueryOptFn) (*AggregateResult, error) {
+	return aggregateField(ctx, client, fql, field, opts, math.Min)
+}
+
+// MaxField is like [SumField], but returns the largest of the field's values.
+func MaxField(ctx context.Context, client *Client, fql *Query, field string, opts ...QueryOptFn) (*AggregateResult, error) {
+	return aggregateField(ctx, client, fql, field, opts, math.Max)
+}
+
+func aggregateField(ctx context.Context, client *Client, fql *Query, field string, opts []QueryOptFn, combine func(acc, v float64) float64) (*AggregateResult, error) {
+	path := strings.Split(field, ".")
+	res := &AggregateResult{}
+
+	iter := client.Paginate(fql, append([]QueryOptFn{QueryContext(ctx)}, opts...)...)
+	for iter.HasNext() {
+		page, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		res.Pages++
+		if page.info != nil {
+			res.Stats.add(page.info.Stats)
+		}
+
+		for _, item := range page.Data {
+			res.Items++
+
+			v, ok := numericField(item, path)
+			if !ok {
+				continue
+			}
+
+			if res.Count == 0 {
+				res.Value = v
+			} else {
+				res.Value = combine(res.Value, v)
+			}
+			res.Count++
+		}
+	}
+
+	return res, nil
+}
+
+// numericField returns the value at path in a decoded item as a float64.
+func numericField(item any, path []string) (float64, bool) {
+	for _, key := range path {
+		var fields map[string]any
+		switch v := item.(type) {
+		case *Document:
+			fields = v.Data
+		case *NamedDocument:
+			fields = v.Data
+		case map[string]any:
+			fields = v
+		default:
+			return 0, false
+		}
+
+		var ok bool
+		if item, ok = fields[key]; !ok {
+			return 0, false
+		}
+	}
+
+	switch v := item.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// add adds the counters of other to s.
+func (s *Stats) add(other *Stats) {
+	if other == nil {
+		return
+	}
+
+	s.ComputeOps += other.ComputeOps
+	s.ReadOps += other.ReadOps
+	s.WriteOps += other.WriteOps
+	s.QueryTimeMs += other.QueryTimeMs
+	s.ContentionRetries += other.ContentionRetries
+	s.StorageBytesRead += other.StorageBytesRead
+	s.StorageBytesWrite += other.StorageBytesWrite
+	s.Attempts += other.Attempts
+}
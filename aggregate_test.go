@@ -0,0 +1,53 @@
+package fauna_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateField(t *testing.T) {
+	handler := func() http.HandlerFunc {
+		calls := 0
+		return func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				_, _ = w.Write([]byte(`{"data":{"@set":{"data":[
+					{"@doc":{"id":"1","coll":{"@mod":"Order"},"ts":{"@time":"2023-02-28T18:10:10Z"},"amount":{"@int":"10"}}},
+					{"@doc":{"id":"2","coll":{"@mod":"Order"},"ts":{"@time":"2023-02-28T18:10:10Z"},"amount":{"@double":"2.5"}}}
+				],"after":"next"}},"txn_ts":1,"stats":{"read_ops":2,"compute_ops":1}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"data":[
+				{"@doc":{"id":"3","coll":{"@mod":"Order"},"ts":{"@time":"2023-02-28T18:10:10Z"},"amount":{"@long":"30"}}},
+				{"@doc":{"id":"4","coll":{"@mod":"Order"},"ts":{"@time":"2023-02-28T18:10:10Z"}}}
+			]},"txn_ts":1,"stats":{"read_ops":3,"compute_ops":1}}`))
+		}
+	}
+
+	q, _ := fauna.FQL(`Order.all()`, nil)
+
+	sum, err := fauna.SumField(context.Background(), newTestClient(t, handler()), q, "amount")
+	require.NoError(t, err)
+	require.Equal(t, 42.5, sum.Value)
+	require.Equal(t, 3, sum.Count)
+	require.Equal(t, 4, sum.Items)
+	require.Equal(t, 2, sum.Pages)
+	require.Equal(t, 5, sum.Stats.ReadOps)
+	require.Equal(t, 2, sum.Stats.ComputeOps)
+
+	avg, err := fauna.AvgField(context.Background(), newTestClient(t, handler()), q, "amount")
+	require.NoError(t, err)
+	require.InDelta(t, 42.5/3, avg.Value, 1e-9)
+
+	least, err := fauna.MinField(context.Background(), newTestClient(t, handler()), q, "amount")
+	require.NoError(t, err)
+	require.Equal(t, 2.5, least.Value)
+
+	most, err := fauna.MaxField(context.Background(), newTestClient(t, handler()), q, "amount")
+	require.NoError(t, err)
+	require.Equal(t, 30.0, most.Value)
+}
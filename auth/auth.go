@@ -0,0 +1,97 @@
+// Package auth creates and revokes Fauna keys and tokens.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// Built-in roles for [CreateKey].
+const (
+	RoleAdmin          = "admin"
+	RoleServer         = "server"
+	RoleServerReadOnly = "server-readonly"
+)
+
+// Key is a Fauna key. Its Secret is only returned when the key is created.
+type Key struct {
+	ID       string     `fauna:"id"`
+	Role     string     `fauna:"role"`
+	Database string     `fauna:"database"`
+	Secret   string     `fauna:"secret"`
+	TS       *time.Time `fauna:"ts"`
+}
+
+// Token is a Fauna token, authenticating as a document. Its Secret is only
+// returned when the token is created.
+type Token struct {
+	ID string `fauna:"id"`
+	// Document is the document the token authenticates as.
+	Document any        `fauna:"document"`
+	Secret   string     `fauna:"secret"`
+	TS       *time.Time `fauna:"ts"`
+}
+
+// CreateKey creates a key with role, a built-in role such as [RoleServer] or
+// the name of a user-defined role. If database is not empty, the key is
+// scoped to that child database.
+func CreateKey(ctx context.Context, client *fauna.Client, role string, database string) (*Key, error) {
+	params := map[string]any{"role": role}
+	if database != "" {
+		params["database"] = database
+	}
+
+	q, err := fauna.FQL(`Key.create(${params}) { id, role, database, secret, ts }`, map[string]any{"params": params})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Query(q, fauna.QueryContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key: %w", err)
+	}
+
+	var key Key
+	if err := res.Unmarshal(&key); err != nil {
+		return nil, fmt.Errorf("failed to decode key: %w", err)
+	}
+	return &key, nil
+}
+
+// CreateToken creates a token authenticating as document, which is usually a
+// [fauna.Ref] or a document previously returned by a query.
+func CreateToken(ctx context.Context, client *fauna.Client, document any) (*Token, error) {
+	q, err := fauna.FQL(`Token.create({ document: ${document} }) { id, document, secret, ts }`, map[string]any{"document": document})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Query(q, fauna.QueryContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+
+	var token Token
+	if err := res.Unmarshal(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode token: %w", err)
+	}
+	return &token, nil
+}
+
+// Logout revokes the token the client authenticates with. It does nothing if
+// the client authenticates with a key.
+func Logout(ctx context.Context, client *fauna.Client) error {
+	q, err := fauna.FQL(`Query.token()?.delete()
+null`, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Query(q, fauna.QueryContext(ctx)); err != nil {
+		return fmt.Errorf("failed to log out: %w", err)
+	}
+	return nil
+}
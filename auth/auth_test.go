@@ -0,0 +1,56 @@
+package auth_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateKey(t *testing.T) {
+	var sent string
+	client := newTestClient(t, &sent, `{"id":"1","role":"server","database":"child","secret":"fnAAA","ts":{"@time":"2023-02-28T18:10:10Z"}}`)
+
+	key, err := auth.CreateKey(context.Background(), client, auth.RoleServer, "child")
+	require.NoError(t, err)
+	require.Equal(t, "fnAAA", key.Secret)
+	require.Equal(t, "child", key.Database)
+	require.Contains(t, sent, `{"value":{"database":"child","role":"server"}}`)
+}
+
+func TestCreateToken(t *testing.T) {
+	var sent string
+	client := newTestClient(t, &sent, `{"id":"2","document":{"@ref":{"id":"123","coll":{"@mod":"User"}}},"secret":"fnBBB"}`)
+
+	token, err := auth.CreateToken(context.Background(), client, &fauna.Ref{ID: "123", Coll: &fauna.Module{Name: "User"}})
+	require.NoError(t, err)
+	require.Equal(t, "fnBBB", token.Secret)
+	require.Equal(t, &fauna.Ref{ID: "123", Coll: &fauna.Module{Name: "User"}}, token.Document)
+	require.Contains(t, sent, `{"@ref":{"coll":{"@mod":"User"},"id":"123"}}`)
+}
+
+func TestLogout(t *testing.T) {
+	var sent string
+	client := newTestClient(t, &sent, `null`)
+
+	require.NoError(t, auth.Logout(context.Background(), client))
+	require.Contains(t, sent, `Query.token()?.delete()`)
+}
+
+func newTestClient(t *testing.T, sent *string, data string) *fauna.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*sent = string(body)
+		_, _ = w.Write([]byte(`{"data":` + data + `,"txn_ts":1,"stats":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL))
+}
@@ -0,0 +1,55 @@
+package fauna
+
+import (
+	"errors"
+	"strings"
+)
+
+// TypecheckResult is the outcome of [Client.Check].
+type TypecheckResult struct {
+	*QueryInfo
+
+	// StaticType is the type Fauna inferred for the query's result. It is
+	// empty if the query failed its checks.
+	StaticType string
+
+	// Error holds the check errors, as summarized by Fauna, if the query
+	// failed its checks, and is nil otherwise.
+	Error *ErrQueryCheck
+}
+
+// Valid reports whether the query passed its checks.
+func (r *TypecheckResult) Valid() bool {
+	return r.Error == nil
+}
+
+// Check typechecks query without running it, so that FQL can be validated,
+// e.g. in CI against a sandbox database. The query is typechecked even if
+// the database disables typechecking, and a query failing its checks isn't
+// an error: it is reported in the result's Error. Errors are returned for
+// requests that fail for other reasons, such as an invalid secret.
+//
+// Fauna has no check-only mode, so query is sent in a branch that is never
+// taken. It is still parsed, resolved against the database's schema and
+// typechecked, but none of it is executed.
+func (c *Client) Check(query *Query, opts ...QueryOptFn) (*TypecheckResult, error) {
+	q, err := FQL("if (false) {\n${query}\n} else null", map[string]any{"query": query})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.Query(q, append(opts, Typecheck(true))...)
+	if err != nil {
+		var checkErr *ErrQueryCheck
+		if errors.As(err, &checkErr) {
+			return &TypecheckResult{Error: checkErr, QueryInfo: checkErr.QueryInfo}, nil
+		}
+		return nil, err
+	}
+
+	return &TypecheckResult{
+		// The branch that isn't taken makes the result nullable.
+		StaticType: strings.TrimSuffix(res.StaticType, " | Null"),
+		QueryInfo:  res.QueryInfo,
+	}, nil
+}
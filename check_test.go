@@ -0,0 +1,58 @@
+package fauna_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("returns the static type without running the query", func(t *testing.T) {
+		var body struct {
+			Query map[string][]any `json:"query"`
+		}
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "true", r.Header.Get(fauna.HeaderTypecheck))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			_, _ = w.Write([]byte(`{"data":null,"static_type":"Product | Null","txn_ts":1,"stats":{}}`))
+		})
+
+		q, _ := fauna.FQL(`Product.create({ name: "cup" })`, nil)
+		res, err := client.Check(q)
+		require.NoError(t, err)
+		require.True(t, res.Valid())
+		require.Equal(t, "Product", res.StaticType)
+		require.Equal(t, int64(1), res.TxnTime)
+		require.Equal(t, "if (false) {\n", body.Query["fql"][0])
+	})
+
+	t.Run("reports check errors in the result", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"invalid_query","message":"The query failed 1 validation check"},"summary":"error: Type ` + "`Number`" + ` is not a subtype of ` + "`String`" + `","txn_ts":1,"stats":{}}`))
+		})
+
+		q, _ := fauna.FQL(`let x: String = 1; x`, nil)
+		res, err := client.Check(q)
+		require.NoError(t, err)
+		require.False(t, res.Valid())
+		require.Empty(t, res.StaticType)
+		require.Contains(t, res.Error.Error(), "is not a subtype of `String`")
+	})
+
+	t.Run("returns other errors", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":"unauthorized","message":"Access token required"},"txn_ts":1,"stats":{}}`))
+		})
+
+		q, _ := fauna.FQL(`1`, nil)
+		_, err := client.Check(q)
+
+		var authErr *fauna.ErrAuthentication
+		require.ErrorAs(t, err, &authErr)
+	})
+}
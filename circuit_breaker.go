@@ -0,0 +1,136 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	circuitFailureThresholdDefault = 5
+	circuitOpenTimeoutDefault      = 30 * time.Second
+	circuitHalfOpenProbesDefault   = 1
+)
+
+// CircuitBreakerConfig configures the circuit breaker set with
+// [fauna.WithCircuitBreaker]. Zero fields take their default.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of requests in a row that must fail for
+	// the circuit to open. Defaults to 5.
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before requests are let
+	// through to probe Fauna. Defaults to 30s.
+	OpenTimeout time.Duration
+
+	// HalfOpenProbes is the number of requests let through once the circuit
+	// is half-open, which must all succeed for it to close. Defaults to 1.
+	HalfOpenProbes int
+}
+
+// WithCircuitBreaker makes the [fauna.Client] fail requests right away with
+// [ErrCircuitOpen] once cfg.FailureThreshold requests in a row failed with a
+// network error or a 5xx status, after any retries, rather than waiting on
+// an endpoint that is down. After cfg.OpenTimeout, the circuit is half-open:
+// up to cfg.HalfOpenProbes requests are sent, closing the circuit if they
+// succeed, or opening it again as soon as one fails.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) ClientConfigFn {
+	return func(c *Client) {
+		if cfg.FailureThreshold < 0 || cfg.OpenTimeout < 0 || cfg.HalfOpenProbes < 0 {
+			c.setOptionErr(fmt.Errorf("circuit breaker settings must not be negative, got %+v", cfg))
+			return
+		}
+
+		if cfg.FailureThreshold == 0 {
+			cfg.FailureThreshold = circuitFailureThresholdDefault
+		}
+		if cfg.OpenTimeout == 0 {
+			cfg.OpenTimeout = circuitOpenTimeoutDefault
+		}
+		if cfg.HalfOpenProbes == 0 {
+			cfg.HalfOpenProbes = circuitHalfOpenProbesDefault
+		}
+		c.breaker = &circuitBreaker{cfg: cfg}
+	}
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     circuitState
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+}
+
+// allow reports whether a request may be sent, and whether it probes a
+// half-open circuit. The caller must pass its outcome to record.
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitOpen {
+		if wait := b.cfg.OpenTimeout - time.Since(b.openedAt); wait > 0 {
+			return false, &ErrCircuitOpen{RetryAfter: wait}
+		}
+		b.state, b.probes, b.successes = circuitHalfOpen, 0, 0
+	}
+
+	if b.state == circuitHalfOpen {
+		if b.probes+b.successes >= b.cfg.HalfOpenProbes {
+			return false, &ErrCircuitOpen{}
+		}
+		b.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+// record records the outcome of a request allowed by allow. Requests
+// canceled by their context count as neither failed nor successful.
+func (b *circuitBreaker) record(ctx context.Context, probe bool, res *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probes--
+	}
+
+	var failed bool
+	switch {
+	case err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled)):
+		return
+	case err != nil:
+		failed = true
+	default:
+		failed = res.StatusCode >= http.StatusInternalServerError
+	}
+
+	if failed {
+		b.failures++
+		if b.state == circuitHalfOpen || b.failures >= b.cfg.FailureThreshold {
+			b.state, b.openedAt, b.failures = circuitOpen, time.Now(), 0
+		}
+		return
+	}
+
+	b.failures = 0
+	if probe && b.state == circuitHalfOpen {
+		if b.successes++; b.successes >= b.cfg.HalfOpenProbes {
+			b.state = circuitClosed
+		}
+	}
+}
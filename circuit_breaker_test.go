@@ -0,0 +1,73 @@
+package fauna_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		requests int
+		status   = http.StatusServiceUnavailable
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		switch status {
+		case http.StatusOK:
+			_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+		case http.StatusBadRequest:
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error":{"code":"invalid_query","message":"bad"},"stats":{}}`))
+		default:
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error":{"code":"unavailable","message":"down"},"stats":{}}`))
+		}
+	}, fauna.MaxAttempts(1), fauna.WithCircuitBreaker(fauna.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      50 * time.Millisecond,
+	}))
+	q, _ := fauna.FQL(`null`, nil)
+
+	// query errors don't open the circuit
+	status = http.StatusBadRequest
+	for i := 0; i < 3; i++ {
+		_, err := client.Query(q)
+		require.ErrorAs(t, err, new(*fauna.ErrQueryCheck))
+	}
+
+	status = http.StatusServiceUnavailable
+	for i := 0; i < 2; i++ {
+		_, err := client.Query(q)
+		require.ErrorAs(t, err, new(*fauna.ErrServiceTimeout))
+	}
+	requests = 0
+	_, err := client.Query(q)
+	var openErr *fauna.ErrCircuitOpen
+	require.ErrorAs(t, err, &openErr)
+	require.Greater(t, openErr.RetryAfter, time.Duration(0))
+	require.Zero(t, requests, "requests fail fast while the circuit is open")
+
+	// a failed probe opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	_, err = client.Query(q)
+	require.ErrorAs(t, err, new(*fauna.ErrServiceTimeout))
+	_, err = client.Query(q)
+	require.ErrorAs(t, err, &openErr)
+	require.Equal(t, 1, requests)
+
+	// a successful probe closes it
+	status = http.StatusOK
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		_, err = client.Query(q)
+		require.NoError(t, err)
+	}
+
+	client = fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.WithCircuitBreaker(fauna.CircuitBreakerConfig{FailureThreshold: -1}))
+	_, err = client.Query(q)
+	require.ErrorContains(t, err, "circuit breaker settings must not be negative")
+}
@@ -4,7 +4,9 @@ package fauna
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -13,7 +15,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/fauna/fauna-go/v3/internal/fingerprinting"
@@ -34,6 +38,20 @@ const (
 	EnvFaunaSecret = "FAUNA_SECRET"
 	// EnvFaunaDebug environment variable for Fauna Client logging
 	EnvFaunaDebug = "FAUNA_DEBUG"
+	// EnvFaunaQueryTimeoutMs environment variable for the query timeout of
+	// [NewDefaultClient], in milliseconds
+	EnvFaunaQueryTimeoutMs = "FAUNA_QUERY_TIMEOUT_MS"
+	// EnvFaunaMaxAttempts environment variable for [MaxAttempts]
+	EnvFaunaMaxAttempts = "FAUNA_MAX_ATTEMPTS"
+	// EnvFaunaMaxBackoffMs environment variable for [MaxBackoff], in milliseconds
+	EnvFaunaMaxBackoffMs = "FAUNA_MAX_BACKOFF_MS"
+	// EnvFaunaLinearized environment variable for [DefaultConsistency]: true
+	// for [ConsistencyLinearized], false for [ConsistencySerialized]
+	EnvFaunaLinearized = "FAUNA_LINEARIZED"
+	// EnvFaunaTypecheck environment variable for [DefaultTypecheck]
+	EnvFaunaTypecheck = "FAUNA_TYPECHECK"
+	// EnvFaunaMaxContentionRetries environment variable for [MaxContentionRetries]
+	EnvFaunaMaxContentionRetries = "FAUNA_MAX_CONTENTION_RETRIES"
 
 	// Headers consumers might want to use
 
@@ -42,7 +60,9 @@ const (
 	HeaderMaxContentionRetries = "X-Max-Contention-Retries"
 	HeaderTags                 = "X-Query-Tags"
 	HeaderQueryTimeoutMs       = "X-Query-Timeout-Ms"
+	HeaderRequestID            = "X-Request-Id"
 	HeaderTraceparent          = "Traceparent"
+	HeaderTracestate           = "Tracestate"
 	HeaderTypecheck            = "X-Typecheck"
 
 	// Headers just used internally
@@ -53,31 +73,98 @@ const (
 	headerDriverEnv     = "X-Driver-Env"
 	headerFormat        = "X-Format"
 
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRetryAfter         = "Retry-After"
+
+	headerPrefixCustom = "X-"
+
 	retryMaxAttemptsDefault = 3
 	retryMaxBackoffDefault  = time.Second * 20
+
+	maxIdleConnsDefault = 20
 )
 
 // Client is the Fauna Client.
 type Client struct {
 	url                 string
 	secret              string
+	tokenProvider       AccessTokenProvider
 	headers             map[string]string
+	tags                map[string]string
+	fingerprintTag      bool
 	lastTxnTime         txnTime
+	lastSchemaVersion   txnTime
 	typeCheckingEnabled bool
 
-	http *http.Client
-	ctx  context.Context
+	http       *http.Client
+	middleware []Middleware
+	ctx        context.Context
 
 	maxAttempts int
 	maxBackoff  time.Duration
 
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	tlsConfig           *tls.Config
+	proxyURL            *url.URL
+	dialer              func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	encoder encoder
+	decoder decoder
+
 	// lazily cached URLs
 	queryURL, streamURL, feedURL *url.URL
 
-	logger Logger
+	capture    *trafficCapture
+	serverInfo lastServerInfo
+	stats      clientStats
+
+	allowUnknownHeaders bool
+	configErr           error
+	// optionErr is the first error reported by a ClientConfigFn.
+	optionErr error
+
+	previewHeaders map[string]bool
+	apiVersions    map[string]string
+
+	logger             Logger
+	logConfig          logConfig
+	componentLoggers   map[string]Logger
+	logRedaction       [][]string
+	slowQueryThreshold time.Duration
+	onRequest          func(id string, query string)
+	onRetry            RetryObserver
+	tracePropagators   []TracePropagator
+
+	onSchemaVersionChange func(old, new int64)
+
+	queryCache *queryCache
+
+	endpoints     *endpointSet
+	failoverAfter int
+	breaker       *circuitBreaker
+
+	// timeouts and configFns are those the client was created with, to derive
+	// clients from it
+	timeouts  Timeouts
+	configFns []ClientConfigFn
+
+	requestSlots        chan struct{}
+	requestQueueTimeout time.Duration
+	throttle            *adaptiveThrottle
+	probeInterval       time.Duration
 }
 
 // NewDefaultClient initialize a [fauna.Client] with recommended default settings
+//
+// The secret is read from [EnvFaunaSecret] and the endpoint from
+// [EnvFaunaEndpoint]. The query timeout, [MaxAttempts], [MaxBackoff],
+// [DefaultConsistency], [DefaultTypecheck] and [MaxContentionRetries] can be
+// set with [EnvFaunaQueryTimeoutMs], [EnvFaunaMaxAttempts],
+// [EnvFaunaMaxBackoffMs], [EnvFaunaLinearized], [EnvFaunaTypecheck] and
+// [EnvFaunaMaxContentionRetries].
 func NewDefaultClient() (*Client, error) {
 	var secret string
 	if val, found := os.LookupEnv(EnvFaunaSecret); !found {
@@ -91,13 +178,84 @@ func NewDefaultClient() (*Client, error) {
 		endpointURL = EndpointDefault
 	}
 
+	timeouts, opts, err := envConfig(DefaultTimeouts())
+	if err != nil {
+		return nil, err
+	}
+
 	return NewClient(
 		secret,
-		DefaultTimeouts(),
-		URL(endpointURL),
+		timeouts,
+		append([]ClientConfigFn{URL(endpointURL)}, opts...)...,
 	), nil
 }
 
+// envConfig returns timeouts and the options set by the environment
+// variables NewDefaultClient honors, which override timeouts.
+func envConfig(timeouts Timeouts) (Timeouts, []ClientConfigFn, error) {
+	var opts []ClientConfigFn
+
+	lookupInt := func(name string) (int, bool, error) {
+		val, found := os.LookupEnv(name)
+		if !found || val == "" {
+			return 0, false, nil
+		}
+		i, err := strconv.Atoi(val)
+		if err != nil || i < 0 {
+			return 0, false, fmt.Errorf("invalid value for environment variable '%s': %q is not a non-negative integer", name, val)
+		}
+		return i, true, nil
+	}
+	lookupBool := func(name string) (bool, bool, error) {
+		val, found := os.LookupEnv(name)
+		if !found || val == "" {
+			return false, false, nil
+		}
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return false, false, fmt.Errorf("invalid value for environment variable '%s': %q is not a boolean", name, val)
+		}
+		return b, true, nil
+	}
+
+	if ms, found, err := lookupInt(EnvFaunaQueryTimeoutMs); err != nil {
+		return timeouts, nil, err
+	} else if found {
+		timeouts.QueryTimeout = time.Duration(ms) * time.Millisecond
+	}
+	if attempts, found, err := lookupInt(EnvFaunaMaxAttempts); err != nil {
+		return timeouts, nil, err
+	} else if found {
+		opts = append(opts, MaxAttempts(attempts))
+	}
+	if ms, found, err := lookupInt(EnvFaunaMaxBackoffMs); err != nil {
+		return timeouts, nil, err
+	} else if found {
+		opts = append(opts, MaxBackoff(time.Duration(ms)*time.Millisecond))
+	}
+	if linearized, found, err := lookupBool(EnvFaunaLinearized); err != nil {
+		return timeouts, nil, err
+	} else if found {
+		consistency := ConsistencySerialized
+		if linearized {
+			consistency = ConsistencyLinearized
+		}
+		opts = append(opts, DefaultConsistency(consistency))
+	}
+	if typecheck, found, err := lookupBool(EnvFaunaTypecheck); err != nil {
+		return timeouts, nil, err
+	} else if found {
+		opts = append(opts, DefaultTypecheck(typecheck))
+	}
+	if retries, found, err := lookupInt(EnvFaunaMaxContentionRetries); err != nil {
+		return timeouts, nil, err
+	} else if found {
+		opts = append(opts, MaxContentionRetries(retries))
+	}
+
+	return timeouts, opts, nil
+}
+
 type Timeouts struct {
 	// The timeout of each query. This controls the maximum amount of time Fauna will
 	// execute your query before marking it failed.
@@ -115,6 +273,15 @@ type Timeouts struct {
 	// IdleConnectionTimeout is the maximum amount of time an idle (keep-alive) connection will
 	// remain idle before closing itself.
 	IdleConnectionTimeout time.Duration
+
+	// TLSHandshakeTimeout is the maximum amount of time to wait for a TLS handshake. Zero means no
+	// timeout.
+	TLSHandshakeTimeout time.Duration
+
+	// ExpectContinueTimeout is the amount of time to wait for the server's first response headers
+	// after sending request headers, if the request has an "Expect: 100-continue" header. Zero
+	// means the body is sent immediately.
+	ExpectContinueTimeout time.Duration
 }
 
 // DefaultTimeouts suggested timeouts for the default [fauna.Client]
@@ -139,16 +306,17 @@ func NewClient(secret string, timeouts Timeouts, configFns ...ClientConfigFn) *C
 	// message. On the streaming interface, HTTP chunks are sent on every event.
 	// Therefore, it's in the driver's best interest to continue reading the
 	// HTTP body once the headers appear.
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			Proxy:                 http.ProxyFromEnvironment,
-			DialContext:           dialer.DialContext,
-			ForceAttemptHTTP2:     true,
-			MaxIdleConns:          20,
-			IdleConnTimeout:       timeouts.IdleConnectionTimeout,
-			ResponseHeaderTimeout: timeouts.QueryTimeout + timeouts.ClientBufferTimeout,
-		},
-	}
+	conns := &atomic.Int64{}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           countingDialer(dialer.DialContext, conns),
+		ForceAttemptHTTP2:     true,
+		IdleConnTimeout:       timeouts.IdleConnectionTimeout,
+		ResponseHeaderTimeout: timeouts.QueryTimeout + timeouts.ClientBufferTimeout,
+		TLSHandshakeTimeout:   timeouts.TLSHandshakeTimeout,
+		ExpectContinueTimeout: timeouts.ExpectContinueTimeout,
+	}
+	httpClient := &http.Client{Transport: transport}
 
 	defaultHeaders := map[string]string{
 		headerContentType: "application/json; charset=utf-8",
@@ -182,23 +350,110 @@ func NewClient(secret string, timeouts Timeouts, configFns ...ClientConfigFn) *C
 		typeCheckingEnabled: false,
 		maxAttempts:         retryMaxAttemptsDefault,
 		maxBackoff:          retryMaxBackoffDefault,
+		maxIdleConns:        maxIdleConnsDefault,
+		failoverAfter:       failoverAfterDefault,
+		probeInterval:       probeIntervalDefault,
 		logger:              DefaultLogger(),
+		previewHeaders:      map[string]bool{},
+		apiVersions:         map[string]string{},
 	}
 
 	// set options to override defaults
 	for _, configFn := range configFns {
 		configFn(client)
 	}
+	client.timeouts, client.configFns = timeouts, configFns
+
+	// transport limits only apply to the transport the client built itself
+	if client.http == httpClient {
+		client.stats.conns = conns
+		transport.MaxIdleConns = client.maxIdleConns
+		transport.MaxIdleConnsPerHost = client.maxIdleConnsPerHost
+		transport.MaxConnsPerHost = client.maxConnsPerHost
+		if client.tlsConfig != nil {
+			transport.TLSClientConfig = client.tlsConfig.Clone()
+		}
+		if client.proxyURL != nil {
+			transport.Proxy = http.ProxyURL(client.proxyURL)
+		}
+		if client.dialer != nil {
+			transport.DialContext = countingDialer(client.dialer, conns)
+		}
+	}
+
+	client.scopeLoggers()
+
+	client.configErr = client.optionErr
+	if client.configErr == nil {
+		client.configErr = client.validateHeaders()
+	}
+	client.applyMiddleware()
 
 	return client
 }
 
+func (c *Client) applyMiddleware() {
+	if len(c.middleware) == 0 {
+		return
+	}
+
+	transport := c.http.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		transport = c.middleware[i](transport)
+	}
+
+	wrapped := *c.http
+	wrapped.Transport = transport
+	c.http = &wrapped
+}
+
+// knownHeaders are the headers understood by Fauna, in canonical form.
+var knownHeaders = map[string]bool{
+	http.CanonicalHeaderKey(HeaderLastTxnTs):            true,
+	http.CanonicalHeaderKey(HeaderLinearized):           true,
+	http.CanonicalHeaderKey(HeaderMaxContentionRetries): true,
+	http.CanonicalHeaderKey(HeaderTags):                 true,
+	http.CanonicalHeaderKey(HeaderQueryTimeoutMs):       true,
+	http.CanonicalHeaderKey(HeaderRequestID):            true,
+	http.CanonicalHeaderKey(HeaderTraceparent):          true,
+	http.CanonicalHeaderKey(HeaderTypecheck):            true,
+	http.CanonicalHeaderKey(headerDriver):               true,
+	http.CanonicalHeaderKey(headerDriverEnv):            true,
+	http.CanonicalHeaderKey(headerFormat):               true,
+}
+
+// setOptionErr records err unless an earlier option already failed.
+func (c *Client) setOptionErr(err error) {
+	if c.optionErr == nil {
+		c.optionErr = err
+	}
+}
+
+func (c *Client) validateHeaders() error {
+	if c.allowUnknownHeaders {
+		return nil
+	}
+
+	for k := range c.headers {
+		key := http.CanonicalHeaderKey(k)
+		if strings.HasPrefix(key, headerPrefixCustom) && !knownHeaders[key] && !c.previewHeaders[key] {
+			return fmt.Errorf("unknown Fauna header %q, use AllowUnknownHeaders to send it anyway", k)
+		}
+	}
+
+	return nil
+}
+
 func (c *Client) parseQueryURL() (*url.URL, error) {
 	if c.queryURL == nil {
 		if queryURL, err := url.Parse(c.url); err != nil {
 			return nil, err
 		} else {
-			c.queryURL = queryURL.JoinPath("query", "1")
+			c.queryURL = queryURL.JoinPath("query", c.apiVersion("query"))
 		}
 	}
 
@@ -210,7 +465,7 @@ func (c *Client) parseStreamURL() (*url.URL, error) {
 		if streamURL, err := url.Parse(c.url); err != nil {
 			return nil, err
 		} else {
-			c.streamURL = streamURL.JoinPath("stream", "1")
+			c.streamURL = streamURL.JoinPath("stream", c.apiVersion("stream"))
 		}
 	}
 
@@ -222,39 +477,68 @@ func (c *Client) parseFeedURL() (*url.URL, error) {
 		if feedURL, err := url.Parse(c.url); err != nil {
 			return nil, err
 		} else {
-			c.feedURL = feedURL.JoinPath("feed", "1")
+			c.feedURL = feedURL.JoinPath("feed", c.apiVersion("feed"))
 		}
 	}
 
 	return c.feedURL, nil
 }
 
-func (c *Client) doWithRetry(req *http.Request) (attempts int, r *http.Response, err error) {
+// doWithRetry sends req with body, retrying it when throttled, and after
+// temporary network errors if it is idempotent or wasn't sent. Each attempt
+// reads body afresh, rather than a copy of req's body.
+func (c *Client) doWithRetry(req *http.Request, body []byte, idempotent bool) (attempts int, r *http.Response, err error) {
 	req2 := req.Clone(req.Context())
-	body, rerr := io.ReadAll(req.Body)
-	if rerr != nil {
-		return attempts, r, rerr
-	}
 
-	cerr := req.Body.Close()
-	if cerr != nil {
-		return attempts, r, cerr
-	}
+	c.stats.queries.Add(1)
+	c.stats.inFlight.Add(1)
+	defer c.stats.inFlight.Add(-1)
 
 	for {
 		shouldRetry := false
 
+		endpoint, endpointIdx := c.url, 0
+		if c.endpoints != nil {
+			if c.endpoints.startProbe(c.probeInterval) {
+				go c.probePrimary()
+			}
+
+			endpointIdx = c.endpoints.current()
+			req2.URL = c.endpoints.rewrite(req.URL, endpointIdx)
+			endpoint = c.endpoints.bases[endpointIdx].String()
+		}
+
+		if c.throttle != nil {
+			if err = c.throttle.wait(req.Context()); err != nil {
+				return attempts, nil, err
+			}
+		}
+
 		// Ensure we have a fresh body for the request
 		req2.Body = io.NopCloser(bytes.NewReader(body))
+		start := time.Now()
 		r, err = c.http.Do(req2)
-		c.logger.LogResponse(c.ctx, body, r)
-
-		attempts++
-		if err != nil {
-			return
+		if c.throttle != nil && r != nil {
+			c.throttle.observe(r.StatusCode == http.StatusTooManyRequests)
+		}
+		if c.endpoints != nil {
+			c.endpoints.observe(endpointIdx, failedOver(req.Context(), r, err), c.failoverAfter)
+		}
+		if c.capture != nil {
+			c.capture.record(req2, body, r, err, start)
 		}
+		c.logResponse(logComponentOf(req.URL), body, r)
 
 		if r != nil {
+			c.serverInfo.observe(endpoint, r.Header)
+		}
+
+		attempts++
+		status := 0
+		if err != nil {
+			shouldRetry = req.Context().Err() == nil && newErrNetwork(err).retryable(idempotent)
+		} else {
+			status = r.StatusCode
 			switch r.StatusCode {
 			case http.StatusTooManyRequests:
 				shouldRetry = true
@@ -273,7 +557,18 @@ func (c *Client) doWithRetry(req *http.Request) (attempts int, r *http.Response,
 			}
 		}
 
-		timer := time.NewTimer(c.backoff(attempts))
+		c.stats.retries.Add(1)
+		delay := c.backoff(attempts)
+		if c.onRetry != nil {
+			c.onRetry(attempts, delay, status, err)
+		}
+		if err != nil {
+			c.loggerFor(LogComponentRetry).Info(fmt.Sprintf("retrying request after attempt %d failed with %s in %s", attempts, err, delay))
+		} else {
+			c.loggerFor(LogComponentRetry).Info(fmt.Sprintf("retrying request after attempt %d with status %d in %s", attempts, status, delay))
+		}
+
+		timer := time.NewTimer(delay)
 		select {
 		case <-req.Context().Done():
 			timer.Stop()
@@ -304,19 +599,46 @@ func (c *Client) backoff(attempt int) (sleep time.Duration) {
 
 // Query invoke fql optionally set multiple [QueryOptFn]
 func (c *Client) Query(fql *Query, opts ...QueryOptFn) (*QuerySuccess, error) {
-	req := &queryRequest{
-		apiRequest: apiRequest{
-			Context: c.ctx,
-			Headers: c.headers,
-		},
-		Query: fql,
-	}
+	return c.newQueryRequest(fql, opts).do(c)
+}
+
+// QueryWithContext invoke fql with ctx as its [context.Context], taking
+// precedence over any set with [QueryContext] in opts.
+func (c *Client) QueryWithContext(ctx context.Context, fql *Query, opts ...QueryOptFn) (*QuerySuccess, error) {
+	return c.Query(fql, withContext(ctx, opts)...)
+}
 
-	for _, queryOptionFn := range opts {
-		queryOptionFn(req)
+func withContext(ctx context.Context, opts []QueryOptFn) []QueryOptFn {
+	return append(append([]QueryOptFn{}, opts...), QueryContext(ctx))
+}
+
+// newQueryRequest applies opts to a request for fql, after any options added
+// to the request's context with WithOptions, so that opts take precedence.
+func (c *Client) newQueryRequest(fql *Query, opts []QueryOptFn) *queryRequest {
+	build := func(opts []QueryOptFn) *queryRequest {
+		req := &queryRequest{
+			apiRequest: apiRequest{
+				Context: c.ctx,
+				Headers: c.copyHeaders(),
+			},
+			Query: fql,
+			tags:  c.tags,
+		}
+
+		for _, queryOptionFn := range opts {
+			queryOptionFn(req)
+		}
+		if c.fingerprintTag && fql != nil {
+			req.tagFingerprint(fql)
+		}
+		return req
 	}
 
-	return req.do(c)
+	req := build(opts)
+	if ctxOpts := contextOptions(req.Context); len(ctxOpts) > 0 {
+		req = build(append(ctxOpts, opts...))
+	}
+	return req
 }
 
 // Paginate invoke fql with pagination optionally set multiple [QueryOptFn]
@@ -328,6 +650,13 @@ func (c *Client) Paginate(fql *Query, opts ...QueryOptFn) *QueryIterator {
 	}
 }
 
+// PaginateWithContext invoke fql with pagination, fetching every page with
+// ctx as its [context.Context] unless [fauna.QueryIterator.NextWithContext]
+// is given another.
+func (c *Client) PaginateWithContext(ctx context.Context, fql *Query, opts ...QueryOptFn) *QueryIterator {
+	return c.Paginate(fql, withContext(ctx, opts)...)
+}
+
 // StreamFromQuery initiates a stream subscription for the [fauna.Query].
 //
 // This is a syntax sugar for [fauna.Client.Query] and [fauna.Client.Subscribe].
@@ -335,21 +664,84 @@ func (c *Client) Paginate(fql *Query, opts ...QueryOptFn) *QueryIterator {
 // Note that the query provided MUST return [fauna.EventSource] value. Otherwise,
 // this method returns an error.
 func (c *Client) StreamFromQuery(fql *Query, streamOpts []StreamOptFn, opts ...QueryOptFn) (*EventStream, error) {
-	res, err := c.Query(fql, opts...)
+	return c.StreamFromQueryWithContext(c.ctx, fql, streamOpts, opts...)
+}
+
+// StreamFromQueryWithContext is [fauna.Client.StreamFromQuery] with ctx as the
+// [context.Context] of both the query and the stream.
+func (c *Client) StreamFromQueryWithContext(ctx context.Context, fql *Query, streamOpts []StreamOptFn, opts ...QueryOptFn) (*EventStream, error) {
+	var req streamRequest
+	for _, streamOptionFn := range streamOpts {
+		streamOptionFn(&req)
+	}
+
+	stream, err := c.projectedEventSource(ctx, fql, req.fields, opts...)
 	if err != nil {
 		return nil, err
 	}
 
+	return c.StreamWithContext(ctx, stream, streamOpts...)
+}
+
+// EventSource invoke fql and return the [fauna.EventSource] it produces.
+//
+// If the query returns any other value, an [ErrNotEventSource] is returned
+// describing what the query produced instead.
+func (c *Client) EventSource(fql *Query, opts ...QueryOptFn) (EventSource, error) {
+	return c.EventSourceWithContext(c.ctx, fql, opts...)
+}
+
+// EventSourceWithContext is [fauna.Client.EventSource] with ctx as the
+// [context.Context] of the query.
+func (c *Client) EventSourceWithContext(ctx context.Context, fql *Query, opts ...QueryOptFn) (EventSource, error) {
+	res, err := c.Query(fql, withContext(ctx, opts)...)
+	if err != nil {
+		return "", err
+	}
+
 	if stream, ok := res.Data.(EventSource); ok {
-		return c.Stream(stream, streamOpts...)
+		return stream, nil
+	}
+
+	return "", &ErrNotEventSource{
+		Value:      res.Data,
+		StaticType: res.StaticType,
 	}
+}
 
-	return nil, fmt.Errorf("query should return a fauna.EventSource but got %T", res.Data)
+// projectedEventSource runs fql like EventSourceWithContext, projecting the
+// event source onto fields so that Fauna only sends them in events. If Fauna
+// can't project the event source, fql is run as is and the events are
+// projected as they are received instead.
+func (c *Client) projectedEventSource(ctx context.Context, fql *Query, fields []string, opts ...QueryOptFn) (EventSource, error) {
+	if len(fields) == 0 {
+		return c.EventSourceWithContext(ctx, fql, opts...)
+	}
+
+	projected, err := project(fql, fields)
+	if err != nil {
+		return "", err
+	}
+
+	source, err := c.EventSourceWithContext(ctx, projected, opts...)
+	var checkErr *ErrQueryCheck
+	var runtimeErr *ErrQueryRuntime
+	if errors.As(err, &checkErr) || errors.As(err, &runtimeErr) {
+		return c.EventSourceWithContext(ctx, fql, opts...)
+	}
+	return source, err
 }
 
 // Stream initiates a stream subscription for the given stream value.
 func (c *Client) Stream(stream EventSource, opts ...StreamOptFn) (*EventStream, error) {
-	return subscribe(c, stream, opts...)
+	return subscribe(c.ctx, c, stream, opts...)
+}
+
+// StreamWithContext initiates a stream subscription for the given stream
+// value, with ctx as its [context.Context]. Canceling ctx closes the stream,
+// and reconnections use it as well.
+func (c *Client) StreamWithContext(ctx context.Context, stream EventSource, opts ...StreamOptFn) (*EventStream, error) {
+	return subscribe(ctx, c, stream, opts...)
 }
 
 // QueryIterator is a [fauna.Client] iterator for paginated queries
@@ -357,42 +749,68 @@ type QueryIterator struct {
 	client *Client
 	fql    *Query
 	opts   []QueryOptFn
+	after  string
 }
 
-// Next returns the next page of results
-func (q *QueryIterator) Next() (*Page, error) {
-	res, queryErr := q.client.Query(q.fql, q.opts...)
-	if queryErr != nil {
-		return nil, queryErr
-	}
+// NextWithContext returns the next page of results like
+// [fauna.QueryIterator.Next], fetching it with ctx as its [context.Context].
+func (q *QueryIterator) NextWithContext(ctx context.Context, opts ...QueryOptFn) (*Page, error) {
+	return q.Next(withContext(ctx, opts)...)
+}
 
-	if page, ok := res.Data.(*Page); ok { // First page
-		if pageErr := q.nextPage(page.After); pageErr != nil {
-			return nil, pageErr
-		}
+// Next returns the next page of results. Options given to Next, such as
+// [fauna.PageSize], apply to this page only and take precedence over those
+// given to [fauna.Client.Paginate].
+func (q *QueryIterator) Next(opts ...QueryOptFn) (*Page, error) {
+	opts = append(append([]QueryOptFn{}, q.opts...), opts...)
+	if q.after != "" {
+		// The cursor's set is already projected by any Fields.
+		opts = append(opts, func(req *queryRequest) { req.projection = nil })
+	}
 
-		return page, nil
+	fql, fqlErr := q.pageQuery(opts)
+	if fqlErr != nil {
+		return nil, fqlErr
 	}
 
-	var page Page
-	if results, isPage := res.Data.(map[string]any); isPage {
-		if after, hasAfter := results["after"].(string); hasAfter {
-			page = Page{After: after, Data: results["data"].([]any)}
-		} else {
-			page = Page{After: "", Data: results["data"].([]any)}
-		}
-	} else {
-		page = Page{After: "", Data: []any{res.Data}}
+	res, queryErr := q.client.Query(fql, opts...)
+	if queryErr != nil {
+		return nil, queryErr
 	}
 
+	page := pageOf(res)
 	if pageErr := q.nextPage(page.After); pageErr != nil {
 		return nil, pageErr
 	}
 
-	return &page, nil
+	return page, nil
+}
+
+// pageQuery returns the query for the next page, sized by any [PageSize] in
+// opts.
+func (q *QueryIterator) pageQuery(opts []QueryOptFn) (*Query, error) {
+	req := q.client.newQueryRequest(q.fql, opts)
+
+	switch {
+	case req.pageSize <= 0:
+		return q.fql, nil
+	case q.after != "":
+		return FQL(`Set.paginate(${after}, ${size})`, map[string]any{"after": q.after, "size": req.pageSize})
+	default:
+		return FQL(`(${fql}).pageSize(${size})`, map[string]any{"fql": q.fql, "size": req.pageSize})
+	}
+}
+
+// Cursor returns the after cursor of the last page returned by
+// [fauna.QueryIterator.Next], or an empty string before the first page and
+// after the last one. Save it to resume the iteration later with
+// Set.paginate.
+func (q *QueryIterator) Cursor() string {
+	return q.after
 }
 
 func (q *QueryIterator) nextPage(after string) error {
+	q.after = after
 	if after == "" {
 		q.fql = nil
 		return nil
@@ -409,6 +827,41 @@ func (q *QueryIterator) HasNext() bool {
 	return q.fql != nil
 }
 
+// QueryIfModifiedSince runs fql only if the data it reads changed after since.
+// Fauna decides whether it changed, in the same transaction as fql, by
+// running watch, which must return the time the data last changed or null,
+// e.g. `Product.byTs().first()?.ts` for an index of products ordered by
+// descending ts. It should be much cheaper than fql, such as a single index
+// read. If the returned time isn't after since, fql is skipped and
+// QueryIfModifiedSince returns false along with a result whose Data is nil.
+//
+// Deleted documents don't leave a ts behind, so watch can't see deletions
+// unless the application records them.
+func (c *Client) QueryIfModifiedSince(watch *Query, since time.Time, fql *Query, opts ...QueryOptFn) (*QuerySuccess, bool, error) {
+	q, err := FQL(`let changed = ${watch}
+if (changed != null && changed > ${since}) {
+  { modified: true, data: ${fql} }
+} else {
+  { modified: false, data: null }
+}`, map[string]any{"watch": watch, "since": since, "fql": fql})
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := c.Query(q, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	outcome, _ := res.Data.(map[string]any)
+	modified, _ := outcome["modified"].(bool)
+	res.Data = outcome["data"]
+	res.StaticType = ""
+	res.raw = nil
+
+	return res, modified, nil
+}
+
 // SetLastTxnTime update the last txn time for the [fauna.Client]
 // This has no effect if earlier than stored timestamp.
 //
@@ -429,38 +882,72 @@ func (c *Client) String() string {
 	return c.url
 }
 
+func (c *Client) token(ctx context.Context) (string, error) {
+	if c.tokenProvider == nil {
+		return c.secret, nil
+	}
+
+	return c.tokenProvider.Token(ctx)
+}
+
 func (c *Client) setHeader(key, val string) {
 	c.headers[key] = val
 }
 
+// copyHeaders returns the client headers so that per-request options don't
+// leak into the client defaults.
+func (c *Client) copyHeaders() map[string]string {
+	headers := make(map[string]string, len(c.headers))
+	for k, v := range c.headers {
+		headers[k] = v
+	}
+	return headers
+}
+
 // Feed opens an event feed from the event source
 func (c *Client) Feed(stream EventSource, opts ...FeedOptFn) (*EventFeed, error) {
+	return c.FeedWithContext(c.ctx, stream, opts...)
+}
+
+// FeedWithContext opens an event feed from the event source, fetching its
+// pages with ctx as their [context.Context].
+func (c *Client) FeedWithContext(ctx context.Context, stream EventSource, opts ...FeedOptFn) (*EventFeed, error) {
 	feedOpts, err := parseFeedOptions(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return newEventFeed(c, stream, feedOpts)
+	if feedOpts.consistency != nil {
+		return nil, fmt.Errorf("EventFeedConsistency can only be used with FeedFromQuery")
+	}
+
+	return newEventFeed(ctx, c, stream, feedOpts)
 }
 
 // FeedFromQuery opens an event feed from a query
 func (c *Client) FeedFromQuery(query *Query, opts ...FeedOptFn) (*EventFeed, error) {
+	return c.FeedFromQueryWithContext(c.ctx, query, opts...)
+}
+
+// FeedFromQueryWithContext opens an event feed from a query, with ctx as the
+// [context.Context] of the query and the feed's pages.
+func (c *Client) FeedFromQueryWithContext(ctx context.Context, query *Query, opts ...FeedOptFn) (*EventFeed, error) {
 	feedOpts, err := parseFeedOptions(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.Query(query)
-	if err != nil {
-		return nil, err
+	var queryOpts []QueryOptFn
+	if feedOpts.consistency != nil {
+		queryOpts = append(queryOpts, QueryConsistency(*feedOpts.consistency))
 	}
 
-	eventSource, ok := res.Data.(EventSource)
-	if !ok {
-		return nil, fmt.Errorf("query should return a fauna.EventSource but got %T", res.Data)
+	eventSource, err := c.projectedEventSource(ctx, query, feedOpts.fields, queryOpts...)
+	if err != nil {
+		return nil, err
 	}
 
-	return newEventFeed(c, eventSource, feedOpts)
+	return newEventFeed(ctx, c, eventSource, feedOpts)
 }
 
 func parseFeedOptions(opts ...FeedOptFn) (*feedOptions, error) {
@@ -473,5 +960,19 @@ func parseFeedOptions(opts ...FeedOptFn) (*feedOptions, error) {
 		return nil, fmt.Errorf("cannot use EventFeedStartTime and EventFeedCursor together")
 	}
 
+	if feedOpts.consistency != nil {
+		if _, err := feedOpts.consistency.linearized(); err != nil {
+			return nil, err
+		}
+	}
+
+	if feedOpts.fields != nil {
+		projection, err := newFieldTree(feedOpts.fields)
+		if err != nil {
+			return nil, err
+		}
+		feedOpts.projection = projection
+	}
+
 	return &feedOpts, nil
 }
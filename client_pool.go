@@ -0,0 +1,66 @@
+package fauna
+
+import "sync"
+
+// ClientPool derives a [fauna.Client] per tenant secret from a single
+// configuration, for services querying many databases. The clients share
+// their connections, as well as the state describing Fauna rather than a
+// database: the endpoint in use with [Endpoints], and the limits of
+// [WithCircuitBreaker], [WithMaxConcurrentRequests] and
+// [WithAdaptiveThrottling]. Each keeps its own secret, last transaction
+// time, stats and [WithQueryCache] results.
+type ClientPool struct {
+	base *Client
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewClientPool returns a [ClientPool] whose clients are configured like
+// [NewClient] with timeouts and configFns. Clients authenticate with the
+// secret of their tenant, even if configFns include [WithTokenProvider].
+func NewClientPool(timeouts Timeouts, configFns ...ClientConfigFn) *ClientPool {
+	return &ClientPool{
+		base:    NewClient("", timeouts, configFns...),
+		clients: map[string]*Client{},
+	}
+}
+
+// For returns the client authenticating with secret, creating it the first
+// time.
+func (p *ClientPool) For(secret string) *Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, found := p.clients[secret]; found {
+		return client
+	}
+
+	client := p.base.derive(secret)
+	p.clients[secret] = client
+	return client
+}
+
+// Remove drops the client for secret from the pool, e.g. once its tenant is
+// gone, so that it can be garbage collected when no longer in use.
+func (p *ClientPool) Remove(secret string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.clients, secret)
+}
+
+// derive returns a client configured like c, authenticating with secret
+// rather than the [AccessTokenProvider] of c if any, that shares the
+// connections and endpoint state of c.
+func (c *Client) derive(secret string) *Client {
+	client := NewClient(secret, c.timeouts, c.configFns...)
+	client.tokenProvider = nil
+	client.http = c.http
+	client.stats.conns = c.stats.conns
+	client.endpoints = c.endpoints
+	client.breaker = c.breaker
+	client.requestSlots = c.requestSlots
+	client.throttle = c.throttle
+	return client
+}
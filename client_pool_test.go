@@ -0,0 +1,61 @@
+package fauna_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPool(t *testing.T) {
+	var secrets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		secrets = append(secrets, secret)
+		_, _ = fmt.Fprintf(w, `{"data":null,"txn_ts":%d,"stats":{}}`, len(secret))
+	}))
+	t.Cleanup(server.Close)
+
+	pool := fauna.NewClientPool(fauna.DefaultTimeouts(), fauna.URL(server.URL))
+	acme, globex := pool.For("acme"), pool.For("globex-inc")
+	require.Same(t, acme, pool.For("acme"))
+
+	q, _ := fauna.FQL(`null`, nil)
+	for _, client := range []*fauna.Client{acme, globex, acme} {
+		_, err := client.Query(q)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"acme", "globex-inc", "acme"}, secrets)
+	require.Equal(t, int64(4), acme.GetLastTxnTime())
+	require.Equal(t, int64(10), globex.GetLastTxnTime())
+	require.Equal(t, int64(2), acme.Stats().Queries)
+	require.Equal(t, int64(1), globex.Stats().OpenConnections, "connections are shared")
+
+	pool.Remove("acme")
+	require.NotSame(t, acme, pool.For("acme"))
+}
+
+func TestClientPoolWithTokenProvider(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := fauna.AccessTokenProviderFunc(func(context.Context) (string, error) {
+		return "provided", nil
+	})
+	pool := fauna.NewClientPool(fauna.DefaultTimeouts(), fauna.URL(server.URL), fauna.WithTokenProvider(provider))
+
+	q, _ := fauna.FQL(`null`, nil)
+	_, err := pool.For("acme").Query(q)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Bearer acme"}, auth, "tenant secrets take precedence over the provider")
+}
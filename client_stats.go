@@ -0,0 +1,97 @@
+package fauna
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+)
+
+// ClientStats is a snapshot of the connection and request counters of a
+// [fauna.Client], returned by [fauna.Client.Stats].
+type ClientStats struct {
+	// OpenConnections is the number of connections to Fauna currently open, or
+	// -1 if unknown because the client uses an [http.Client] set with
+	// [fauna.HTTPClient].
+	OpenConnections int64
+
+	// IdleConnections is the number of open connections not serving a request,
+	// or -1 if OpenConnections is unknown. As HTTP/2 connections serve several
+	// requests at once, it is an estimate: the open connections less the
+	// requests in flight.
+	IdleConnections int64
+
+	// InFlight is the number of requests waiting for a response.
+	InFlight int64
+
+	// Queries is the number of requests the client has made, not counting
+	// retries.
+	Queries int64
+
+	// Retries is the number of times requests were retried.
+	Retries int64
+
+	// ThrottledRate is the rate, in requests per second, that the client sends
+	// requests at with [fauna.WithAdaptiveThrottling], or zero if unlimited.
+	ThrottledRate float64
+}
+
+// Stats returns the client's connection and request counters, e.g. to check
+// whether the connection pool is a bottleneck.
+func (c *Client) Stats() ClientStats {
+	stats := ClientStats{
+		OpenConnections: -1,
+		IdleConnections: -1,
+		InFlight:        c.stats.inFlight.Load(),
+		Queries:         c.stats.queries.Load(),
+		Retries:         c.stats.retries.Load(),
+	}
+
+	if c.throttle != nil {
+		stats.ThrottledRate = c.throttle.currentRate()
+	}
+
+	if c.stats.conns != nil {
+		stats.OpenConnections = c.stats.conns.Load()
+		stats.IdleConnections = stats.OpenConnections - stats.InFlight
+		if stats.IdleConnections < 0 {
+			stats.IdleConnections = 0
+		}
+	}
+
+	return stats
+}
+
+type clientStats struct {
+	// conns is nil if connections aren't dialed by the client's own transport.
+	conns    *atomic.Int64
+	inFlight atomic.Int64
+	queries  atomic.Int64
+	retries  atomic.Int64
+}
+
+// countingDialer wraps dial so that conns tracks the connections it opens
+// until they are closed.
+func countingDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error), conns *atomic.Int64) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		conns.Add(1)
+		return &countedConn{Conn: conn, conns: conns}, nil
+	}
+}
+
+type countedConn struct {
+	net.Conn
+	conns  *atomic.Int64
+	closed atomic.Bool
+}
+
+func (c *countedConn) Close() error {
+	if c.closed.CompareAndSwap(false, true) {
+		c.conns.Add(-1)
+	}
+	return c.Conn.Close()
+}
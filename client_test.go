@@ -1,11 +1,23 @@
 package fauna_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -191,6 +203,46 @@ func TestDefaultClient(t *testing.T) {
 	})
 }
 
+func TestDefaultClientEnv(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header)
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"code":"limit_exceeded","message":"Rate limit exceeded"},"stats":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv(fauna.EnvFaunaSecret, "secret")
+	t.Setenv(fauna.EnvFaunaEndpoint, server.URL)
+	t.Setenv(fauna.EnvFaunaQueryTimeoutMs, "1500")
+	t.Setenv(fauna.EnvFaunaMaxAttempts, "2")
+	t.Setenv(fauna.EnvFaunaMaxBackoffMs, "1")
+	t.Setenv(fauna.EnvFaunaLinearized, "true")
+	t.Setenv(fauna.EnvFaunaTypecheck, "false")
+	t.Setenv(fauna.EnvFaunaMaxContentionRetries, "4")
+
+	client, err := fauna.NewDefaultClient()
+	require.NoError(t, err)
+
+	q, _ := fauna.FQL(`null`, nil)
+	_, err = client.Query(q)
+	require.ErrorAs(t, err, new(*fauna.ErrThrottling))
+	require.Len(t, headers, 2)
+	require.Equal(t, "1500", headers[0].Get(fauna.HeaderQueryTimeoutMs))
+	require.Equal(t, "true", headers[0].Get(fauna.HeaderLinearized))
+	require.Equal(t, "false", headers[0].Get(fauna.HeaderTypecheck))
+	require.Equal(t, "4", headers[0].Get(fauna.HeaderMaxContentionRetries))
+
+	t.Setenv(fauna.EnvFaunaMaxAttempts, "many")
+	_, err = fauna.NewDefaultClient()
+	require.EqualError(t, err, `invalid value for environment variable 'FAUNA_MAX_ATTEMPTS': "many" is not a non-negative integer`)
+
+	t.Setenv(fauna.EnvFaunaMaxAttempts, "")
+	t.Setenv(fauna.EnvFaunaLinearized, "sometimes")
+	_, err = fauna.NewDefaultClient()
+	require.EqualError(t, err, `invalid value for environment variable 'FAUNA_LINEARIZED': "sometimes" is not a boolean`)
+}
+
 func TestNewClient(t *testing.T) {
 	t.Run("default client", func(t *testing.T) {
 		t.Setenv(fauna.EnvFaunaSecret, "secret")
@@ -600,3 +652,1175 @@ func randomString(n int) string {
 	}
 	return string(s)
 }
+
+func TestEventSource(t *testing.T) {
+	t.Run("returns the event source", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"data":{"@stream":"token"},"txn_ts":1,"stats":{}}`))
+		})
+
+		q, _ := fauna.FQL(`Product.all().eventSource()`, nil)
+		source, err := client.EventSource(q)
+		require.NoError(t, err)
+		require.Equal(t, fauna.EventSource("token"), source)
+	})
+
+	t.Run("reports the static type of other values", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"data":{"@int":"42"},"static_type":"Number","txn_ts":1,"stats":{}}`))
+		})
+
+		q, _ := fauna.FQL(`42`, nil)
+		_, err := client.EventSource(q)
+
+		var notSource *fauna.ErrNotEventSource
+		require.ErrorAs(t, err, &notSource)
+		require.Equal(t, "Number", notSource.StaticType)
+		require.Equal(t, int64(42), notSource.Value)
+		require.EqualError(t, err, "query should return a fauna.EventSource but got int64 (static type: Number)")
+	})
+}
+
+func TestHeaderValidation(t *testing.T) {
+	ok := func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"@int":"1"},"txn_ts":1,"stats":{}}`))
+	}
+	q, _ := fauna.FQL(`1`, nil)
+
+	t.Run("rejects unknown fauna headers", func(t *testing.T) {
+		client := newTestClient(t, ok, fauna.AdditionalHeaders(map[string]string{"X-Linearised": "true"}))
+
+		_, err := client.Query(q)
+		require.ErrorContains(t, err, `unknown Fauna header "X-Linearised"`)
+	})
+
+	t.Run("allows unknown headers when asked to", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "true", r.Header.Get("X-Preview-Feature"))
+			ok(w, r)
+		}, fauna.AllowUnknownHeaders(), fauna.AdditionalHeaders(map[string]string{"X-Preview-Feature": "true"}))
+
+		_, err := client.Query(q)
+		require.NoError(t, err)
+	})
+
+	t.Run("typed query options don't leak into the client", func(t *testing.T) {
+		var linearized, retries []string
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			linearized = append(linearized, r.Header.Get(fauna.HeaderLinearized))
+			retries = append(retries, r.Header.Get(fauna.HeaderMaxContentionRetries))
+			ok(w, r)
+		}, fauna.MaxContentionRetries(3))
+
+		_, err := client.Query(q, fauna.QueryConsistency(fauna.ConsistencyLinearized), fauna.QueryMaxContentionRetries(5))
+		require.NoError(t, err)
+
+		_, err = client.Query(q)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"true", ""}, linearized)
+		require.Equal(t, []string{"5", "3"}, retries)
+	})
+}
+
+func TestTokenProvider(t *testing.T) {
+	q, _ := fauna.FQL(`1`, nil)
+
+	t.Run("asks for a token on every request", func(t *testing.T) {
+		var seen []string
+		rotations := 0
+		provider := fauna.AccessTokenProviderFunc(func(ctx context.Context) (string, error) {
+			rotations++
+			return fmt.Sprintf("secret-%d", rotations), nil
+		})
+
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			seen = append(seen, r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"data":{"@int":"1"},"txn_ts":1,"stats":{}}`))
+		}, fauna.WithTokenProvider(provider))
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Query(q)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, []string{"Bearer secret-1", "Bearer secret-2"}, seen)
+	})
+
+	t.Run("returns provider errors", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			t.Fatal("request should not be sent")
+		}, fauna.WithTokenProvider(fauna.AccessTokenProviderFunc(func(ctx context.Context) (string, error) {
+			return "", fmt.Errorf("vault sealed")
+		})))
+
+		_, err := client.Query(q)
+		require.ErrorContains(t, err, "failed to get access token: vault sealed")
+	})
+}
+
+func TestStreamResponse(t *testing.T) {
+	body := `{"data":{"@set":{"data":[{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10.00001Z"},"name":"limes"}}],"after":"next"}},"txn_ts":1,"stats":{"read_ops":1}}`
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	})
+
+	q, _ := fauna.FQL(`Product.all()`, nil)
+	buffered, err := client.Query(q)
+	require.NoError(t, err)
+
+	streamed, err := client.Query(q, fauna.StreamResponse())
+	require.NoError(t, err)
+
+	require.Equal(t, buffered.Data, streamed.Data)
+	require.Equal(t, buffered.Stats, streamed.Stats)
+	require.Equal(t, "next", streamed.Data.(*fauna.Page).After)
+
+	body = `{"data":{"@object":{"@int":{"@int":"1"},"list":[{},[],{"a":null,"b":true,"c":1.5}]}},"txn_ts":1,"stats":{}}`
+	buffered, err = client.Query(q)
+	require.NoError(t, err)
+	streamed, err = client.Query(q, fauna.StreamResponse())
+	require.NoError(t, err)
+	require.Equal(t, buffered.Data, streamed.Data)
+}
+
+func TestStreamResponseConvertsIncrementally(t *testing.T) {
+	const items = 2000
+	padding := strings.Repeat("x", 1024)
+
+	var body bytes.Buffer
+	var ends []int
+	body.WriteString(`{"data":[`)
+	for i := 0; i < items; i++ {
+		if i > 0 {
+			body.WriteString(",")
+		}
+		fmt.Fprintf(&body, `{"pad":"%s","probe":{"@streamprobe":"%d"}}`, padding, i)
+		ends = append(ends, body.Len())
+	}
+	body.WriteString(`],"txn_ts":1,"stats":{}}`)
+
+	// record how much of the body had been read when each item was converted
+	var read atomic.Int64
+	readAt := make([]int64, items)
+	fauna.RegisterDecodeHook("@streamprobe", func(v any) (any, error) {
+		i, err := strconv.Atoi(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		readAt[i] = read.Load()
+		return i, nil
+	})
+
+	countBody := func(next http.RoundTripper) http.RoundTripper {
+		return fauna.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			res, err := next.RoundTrip(req)
+			if err == nil {
+				res.Body = &countingReader{ReadCloser: res.Body, n: &read}
+			}
+			return res, err
+		})
+	}
+
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(body.Bytes())
+	}, fauna.WithMiddleware(countBody))
+
+	q, _ := fauna.FQL(`Product.all().toArray()`, nil)
+	res, err := client.Query(q, fauna.StreamResponse())
+	require.NoError(t, err)
+	require.Len(t, res.Data, items)
+
+	// each item is converted once little more than it has been read, rather
+	// than after the whole body is buffered
+	for i, at := range readAt {
+		require.LessOrEqual(t, at, int64(ends[i]+64*1024), "item %d was converted after reading %d bytes", i, at)
+	}
+	require.Less(t, readAt[0], int64(body.Len()/2))
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n *atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
+
+func TestDecodeOptions(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"user_name":"foo","extra":1},"txn_ts":1,"stats":{}}`))
+	}, fauna.DefaultDecodeOptions(fauna.DecodeOptions{NamingConvention: fauna.SnakeCase}))
+
+	var user struct{ UserName string }
+
+	q, _ := fauna.FQL(`{ user_name: "foo", extra: 1 }`, nil)
+	res, err := client.Query(q)
+	require.NoError(t, err)
+	require.NoError(t, res.Unmarshal(&user))
+	require.Equal(t, "foo", user.UserName)
+
+	strict := fauna.DecodeOptions{Strict: true, NamingConvention: fauna.SnakeCase}
+	res, err = client.Query(q, fauna.WithDecodeOptions(strict))
+	require.NoError(t, err)
+	require.ErrorContains(t, res.Unmarshal(&user), "extra")
+}
+
+func TestStreamAndFeedDecodeOptions(t *testing.T) {
+	strict := fauna.DecodeOptions{Strict: true}
+	var into struct{ Name string }
+
+	t.Run("stream", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"type":"add","txn_ts":1,"cursor":"a","data":{"name":"foo","extra":1}}` + "\n"))
+		})
+
+		events, err := client.Stream("token", fauna.StreamDecodeOptions(strict))
+		require.NoError(t, err)
+		defer func() { _ = events.Close() }()
+
+		var event fauna.Event
+		require.NoError(t, events.Next(&event))
+		require.ErrorContains(t, event.Unmarshal(&into), "extra")
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"events":[{"type":"add","txn_ts":1,"cursor":"a","data":{"name":"foo","extra":1}}],"cursor":"a","has_next":false,"stats":{}}`))
+		})
+
+		feed, err := client.Feed("token", fauna.EventFeedDecodeOptions(strict))
+		require.NoError(t, err)
+
+		var page fauna.FeedPage
+		require.NoError(t, feed.Next(&page))
+		require.Len(t, page.Events, 1)
+		require.ErrorContains(t, page.Events[0].Unmarshal(&into), "extra")
+	})
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	var calls int
+	var bodies []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls == 1 {
+			// drop the connection before responding
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxBackoff(time.Millisecond))
+
+	q, _ := fauna.FQL(`Product.byKey(idempotency_key).first() ?? Product.create({ key: idempotency_key })`, nil)
+
+	// the query checks its key, so it is re-sent on a new connection
+	res, err := client.Query(q, fauna.IdempotencyKey("create-limes"))
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, 2, res.Stats.Attempts)
+	require.Equal(t, "create-limes", res.IdempotencyKey)
+	require.Contains(t, bodies[1], `"arguments":{"idempotency_key":"create-limes"}`)
+}
+
+func TestUnkeyedWritesAreNotRetried(t *testing.T) {
+	var calls int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		_ = conn.Close()
+	}, fauna.MaxBackoff(time.Millisecond))
+
+	q, _ := fauna.FQL(`Product.create({ name: "limes" })`, nil)
+
+	// the query may have been applied, so it must not be re-sent
+	_, err := client.Query(q)
+	var netErr *fauna.ErrNetwork
+	require.ErrorAs(t, err, &netErr)
+	require.Equal(t, 1, calls)
+}
+
+func TestConsistency(t *testing.T) {
+	var linearized []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		linearized = append(linearized, r.Header.Get(fauna.HeaderLinearized))
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.DefaultConsistency(fauna.ConsistencyLinearized))
+
+	q, _ := fauna.FQL(`Product.all().count()`, nil)
+	_, err := client.Query(q)
+	require.NoError(t, err)
+	_, err = client.Query(q, fauna.QueryConsistency(fauna.ConsistencySerialized))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"true", "false"}, linearized)
+
+	_, err = client.Query(q, fauna.QueryConsistency("eventual"))
+	require.ErrorContains(t, err, `unknown consistency "eventual"`)
+	require.Len(t, linearized, 2)
+
+	t.Run("rejects unknown client defaults", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}, fauna.DefaultConsistency("Linearized"))
+
+		_, err := client.Query(q)
+		require.ErrorContains(t, err, `unknown consistency "Linearized"`)
+	})
+
+	t.Run("applies to the source query of feeds", func(t *testing.T) {
+		var paths, linearized []string
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			linearized = append(linearized, r.Header.Get(fauna.HeaderLinearized))
+			if r.URL.Path == "/query/1" {
+				_, _ = w.Write([]byte(`{"data":{"@stream":"token"},"txn_ts":1,"stats":{}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"events":[],"cursor":"a","has_next":false,"stats":{}}`))
+		})
+
+		feed, err := client.FeedFromQuery(q, fauna.EventFeedConsistency(fauna.ConsistencyLinearized))
+		require.NoError(t, err)
+
+		var page fauna.FeedPage
+		require.NoError(t, feed.Next(&page))
+		require.Equal(t, []string{"/query/1", "/feed/1"}, paths)
+		require.Equal(t, []string{"true", ""}, linearized)
+
+		_, err = client.Feed("token", fauna.EventFeedConsistency(fauna.ConsistencyLinearized))
+		require.ErrorContains(t, err, "only be used with FeedFromQuery")
+
+		_, err = client.FeedFromQuery(q, fauna.EventFeedConsistency("eventual"))
+		require.ErrorContains(t, err, `unknown consistency "eventual"`)
+	})
+}
+
+func TestTags(t *testing.T) {
+	var tags []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		tags = append(tags, r.Header.Get(fauna.HeaderTags))
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.QueryTags(map[string]string{"team": "X_Men", "hero": "Cyclops"}))
+
+	q, _ := fauna.FQL(`null`, nil)
+	for _, opts := range [][]fauna.QueryOptFn{
+		nil,
+		{fauna.Tags(map[string]string{"hero": "Wolverine", "a": "1"})},
+		{fauna.ReplaceTags(map[string]string{"only": "one"})},
+		{fauna.Tags(map[string]string{"a": "1"}), fauna.ReplaceTags(nil)},
+		{fauna.ReplaceTags(nil), fauna.Tags(map[string]string{"a": "1"})},
+	} {
+		_, err := client.Query(q, opts...)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{
+		"hero=Cyclops,team=X_Men",
+		"a=1,hero=Wolverine,team=X_Men",
+		"only=one",
+		"",
+		"a=1",
+	}, tags)
+
+	_, err := client.Query(q, fauna.Tags(map[string]string{"route": "/orders"}))
+	require.ErrorContains(t, err, `invalid query option: invalid value "/orders" of query tag "route"`)
+
+	many := map[string]string{}
+	for i := 0; i < 24; i++ {
+		many[fmt.Sprintf("tag%d", i)] = "x"
+	}
+	_, err = client.Query(q, fauna.Tags(many))
+	require.ErrorContains(t, err, "too many query tags: 26")
+	require.Len(t, tags, 5)
+
+	invalid := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.QueryTags(map[string]string{"a=b": "c"}))
+	_, err = invalid.Query(q)
+	require.ErrorContains(t, err, `invalid query tag key "a=b"`)
+}
+
+func TestQueryFingerprintTag(t *testing.T) {
+	var tags []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		tags = append(tags, r.Header.Get(fauna.HeaderTags))
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.QueryTags(map[string]string{"team": "X_Men"}), fauna.WithQueryFingerprintTag())
+
+	byID := func(id string) *fauna.Query {
+		q, err := fauna.FQLf(`Product.byId(${0})`, id)
+		require.NoError(t, err)
+		return q
+	}
+	all, err := fauna.FQL(`Product.all()`, nil)
+	require.NoError(t, err)
+
+	fingerprint := byID("1").Fingerprint()
+	require.Regexp(t, `^[0-9a-f]{16}$`, fingerprint)
+	require.Equal(t, fingerprint, byID("2").Fingerprint())
+	require.NotEqual(t, fingerprint, all.Fingerprint())
+
+	for _, q := range []*fauna.Query{byID("1"), byID("2")} {
+		_, err := client.Query(q)
+		require.NoError(t, err)
+	}
+	_, err = client.Query(all, fauna.Tags(map[string]string{fauna.QueryFingerprintTag: "all_products"}))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"fql_hash=" + fingerprint + ",team=X_Men",
+		"fql_hash=" + fingerprint + ",team=X_Men",
+		"fql_hash=all_products,team=X_Men",
+	}, tags)
+}
+
+func TestWithOptions(t *testing.T) {
+	var tags, linearized []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		tags = append(tags, r.Header.Get(fauna.HeaderTags))
+		linearized = append(linearized, r.Header.Get(fauna.HeaderLinearized))
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	})
+
+	ctx := fauna.WithOptions(context.Background(), fauna.Tags(map[string]string{"tenant": "acme"}))
+	ctx = fauna.WithOptions(ctx, fauna.QueryConsistency(fauna.ConsistencyLinearized))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	q, _ := fauna.FQL(`null`, nil)
+	_, err := client.Query(q, fauna.QueryContext(ctx))
+	require.NoError(t, err)
+	_, err = client.Query(q, fauna.QueryContext(ctx),
+		fauna.Tags(map[string]string{"route": "orders"}),
+		fauna.QueryConsistency(fauna.ConsistencySerialized))
+	require.NoError(t, err)
+	_, err = client.Query(q)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"tenant=acme", "route=orders,tenant=acme", ""}, tags)
+	require.Equal(t, []string{"true", "false", ""}, linearized)
+}
+
+func TestWithContextMethods(t *testing.T) {
+	var tags []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		tags = append(tags, r.Header.Get(fauna.HeaderTags))
+		switch r.URL.Path {
+		case "/stream/1":
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "/feed/1":
+			_, _ = w.Write([]byte(`{"events":[],"cursor":"abc","has_next":false,"stats":{}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{"@stream":"token"},"txn_ts":1,"stats":{}}`))
+		}
+	})
+
+	q, _ := fauna.FQL(`Product.all().eventSource()`, nil)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("queries", func(t *testing.T) {
+		tags = nil
+		ctx := fauna.WithOptions(context.Background(), fauna.Tags(map[string]string{"tenant": "acme"}))
+		_, err := client.QueryWithContext(ctx, q)
+		require.NoError(t, err)
+		require.Equal(t, []string{"tenant=acme"}, tags)
+
+		_, err = client.QueryWithContext(canceled, q, fauna.QueryContext(context.Background()))
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = client.EventSourceWithContext(canceled, q)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		_, err := client.PaginateWithContext(canceled, q).Next()
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = client.Paginate(q).NextWithContext(canceled)
+		require.ErrorIs(t, err, context.Canceled)
+
+		page, err := client.PaginateWithContext(canceled, q).NextWithContext(context.Background())
+		require.NoError(t, err)
+		require.Len(t, page.Data, 1)
+	})
+
+	t.Run("streams", func(t *testing.T) {
+		_, err := client.StreamWithContext(canceled, "token")
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = client.StreamFromQueryWithContext(canceled, q, nil)
+		require.ErrorIs(t, err, context.Canceled)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := client.StreamWithContext(ctx, "token")
+		require.NoError(t, err)
+		defer func() { _ = stream.Close() }()
+
+		cancel()
+		var event fauna.Event
+		require.Error(t, stream.Next(&event))
+	})
+
+	t.Run("feeds", func(t *testing.T) {
+		feed, err := client.FeedWithContext(canceled, "token")
+		require.NoError(t, err)
+		var page fauna.FeedPage
+		require.ErrorIs(t, feed.Next(&page), context.Canceled)
+
+		_, err = client.FeedFromQueryWithContext(canceled, q)
+		require.ErrorIs(t, err, context.Canceled)
+
+		feed, err = client.FeedFromQueryWithContext(context.Background(), q)
+		require.NoError(t, err)
+		require.NoError(t, feed.Next(&page))
+		require.Equal(t, "abc", page.Cursor)
+	})
+}
+
+func TestFields(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query json.RawMessage `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, string(body.Query))
+
+		if len(queries) == 1 {
+			_, _ = w.Write([]byte(`{"data":{"@set":{"data":[{"name":"cup","address":{"city":"Paris"}}],"after":"next"}},"txn_ts":1,"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":[{"name":"mug","address":null}]},"txn_ts":1,"stats":{}}`))
+	})
+
+	q, _ := fauna.FQL(`Product.all()`, nil)
+	iter := client.Paginate(q, fauna.Fields("name", "address.city", "address.zip"))
+
+	page, err := iter.Next()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"fql":["let result = {\n",{"fql":["Product.all()"]},"\n}\nresult { name, address { city, zip } }"]}`, queries[0])
+
+	type address struct {
+		City string `fauna:"city"`
+		Zip  string `fauna:"zip"`
+	}
+	var products []struct {
+		fauna.Document
+		Name    string   `fauna:"name"`
+		Price   float64  `fauna:"price"`
+		Address *address `fauna:"address"`
+	}
+	require.NoError(t, page.Unmarshal(&products))
+	require.Equal(t, "cup", products[0].Name)
+	require.Equal(t, &address{City: "Paris"}, products[0].Address)
+
+	page, err = iter.Next()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"fql":["Set.paginate(",{"value":"next"},")"]}`, queries[1])
+	products = nil
+	require.NoError(t, page.Unmarshal(&products))
+	require.Equal(t, "mug", products[0].Name)
+	require.Nil(t, products[0].Address)
+
+	_, err = client.Query(q, fauna.Fields("name", "address..city"))
+	require.ErrorContains(t, err, `invalid query option: invalid field "address..city"`)
+	require.Len(t, queries, 2)
+}
+
+func TestRequestID(t *testing.T) {
+	var sent []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get(fauna.HeaderRequestID))
+		if len(sent) == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"invalid_query","message":"bad"},"summary":"","stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.OnRequest(func(id string, query string) {
+		require.Equal(t, `{"query":{"fql":["null"]}}`, query)
+		require.Regexp(t, `^[0-9A-HJKMNP-TV-Z]{26}$`, id)
+	}))
+
+	q, _ := fauna.FQL(`null`, nil)
+	res, err := client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, sent[0], res.RequestID)
+
+	_, err = client.Query(q)
+	var checkErr *fauna.ErrQueryCheck
+	require.ErrorAs(t, err, &checkErr)
+	require.Equal(t, sent[1], checkErr.RequestID)
+	require.NotEqual(t, sent[0], sent[1])
+
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	})
+	res, err = client.Query(q, fauna.RequestID("my-id"))
+	require.NoError(t, err)
+	require.Equal(t, "my-id", res.RequestID)
+}
+
+func TestContextPropagation(t *testing.T) {
+	var sent []http.Header
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header)
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}
+	q, _ := fauna.FQL(`null`, nil)
+
+	const (
+		parent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+		state  = "congo=t61rcWkgMzE"
+	)
+	ctx := fauna.WithTraceContext(context.Background(), parent, state)
+
+	client := newTestClient(t, handler)
+	_, err := client.QueryWithContext(ctx, q)
+	require.NoError(t, err)
+	require.Empty(t, sent[0].Get(fauna.HeaderTraceparent), "propagation is off by default")
+
+	client = newTestClient(t, handler, fauna.WithContextPropagation())
+	_, err = client.QueryWithContext(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, parent, sent[1].Get(fauna.HeaderTraceparent))
+	require.Equal(t, state, sent[1].Get(fauna.HeaderTracestate))
+
+	_, err = client.QueryWithContext(context.Background(), q)
+	require.NoError(t, err)
+	require.Empty(t, sent[2].Get(fauna.HeaderTraceparent))
+
+	_, err = client.QueryWithContext(ctx, q, fauna.Traceparent("explicit"))
+	require.NoError(t, err)
+	require.Equal(t, "explicit", sent[3].Get(fauna.HeaderTraceparent))
+	require.Empty(t, sent[3].Get(fauna.HeaderTracestate))
+
+	type spanKey struct{}
+	client = newTestClient(t, handler, fauna.WithContextPropagation(func(ctx context.Context, header http.Header) {
+		if span, ok := ctx.Value(spanKey{}).(string); ok {
+			header.Set("traceparent", span)
+			header.Set("baggage", "ignored")
+		}
+	}))
+	_, err = client.QueryWithContext(context.WithValue(ctx, spanKey{}, "from-span"), q)
+	require.NoError(t, err)
+	require.Equal(t, "from-span", sent[4].Get(fauna.HeaderTraceparent))
+	require.Empty(t, sent[4].Get("Baggage"))
+}
+
+func TestOnSchemaVersionChange(t *testing.T) {
+	versions := []int{5, 5, 7, 6}
+	var requests int
+	var changes [][2]int64
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"data":null,"txn_ts":1,"schema_version":%d,"stats":{}}`, versions[requests])
+		requests++
+	}, fauna.OnSchemaVersionChange(func(old, new int64) {
+		changes = append(changes, [2]int64{old, new})
+	}))
+
+	q, _ := fauna.FQL(`null`, nil)
+	for range versions {
+		res, err := client.Query(q)
+		require.NoError(t, err)
+		require.NotZero(t, res.SchemaVersion)
+	}
+
+	require.Equal(t, [][2]int64{{5, 7}}, changes)
+}
+
+func TestRetryObserver(t *testing.T) {
+	type retry struct {
+		attempt int
+		delay   time.Duration
+		status  int
+		err     error
+	}
+
+	var (
+		requests int
+		retries  []retry
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		if requests++; requests < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":"limit_exceeded","message":"Rate limit exceeded"},"txn_ts":1,"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxBackoff(time.Millisecond), fauna.WithRetryObserver(func(attempt int, delay time.Duration, status int, err error) {
+		retries = append(retries, retry{attempt, delay, status, err})
+	}))
+
+	q, _ := fauna.FQL(`null`, nil)
+	res, err := client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Stats.Attempts)
+
+	require.Len(t, retries, 2)
+	for i, r := range retries {
+		require.Equal(t, i+1, r.attempt)
+		require.LessOrEqual(t, r.delay, time.Millisecond)
+		require.Equal(t, http.StatusTooManyRequests, r.status)
+		require.NoError(t, r.err)
+	}
+}
+
+func TestRetryBodies(t *testing.T) {
+	var bodies []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if bodies = append(bodies, string(body)); len(bodies) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":"limit_exceeded","message":"Rate limit exceeded"},"txn_ts":1,"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":"` + strings.Repeat("a", len(bodies)*100) + `","txn_ts":1,"stats":{}}`))
+	}, fauna.MaxBackoff(time.Millisecond))
+
+	q, _ := fauna.FQL(`${s}.length`, map[string]any{"s": strings.Repeat("b", 1000)})
+	res, err := client.Query(q)
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 3)
+	require.Contains(t, bodies[0], strings.Repeat("b", 1000))
+	require.Equal(t, bodies[0], bodies[1])
+	require.Equal(t, bodies[0], bodies[2])
+
+	// the response buffer is reused by the next query, so its data must
+	// have been copied
+	q, _ = fauna.FQL(`null`, nil)
+	_, err = client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("a", 300), res.Data)
+}
+
+func TestNetworkErrors(t *testing.T) {
+	t.Run("retries requests that were never sent", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		var statuses []int
+		client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL),
+			fauna.MaxBackoff(time.Millisecond),
+			fauna.WithRetryObserver(func(_ int, _ time.Duration, status int, err error) {
+				require.Error(t, err)
+				statuses = append(statuses, status)
+			}),
+		)
+
+		q, _ := fauna.FQL(`null`, nil)
+		_, err := client.Query(q)
+
+		var netErr *fauna.ErrNetwork
+		require.ErrorAs(t, err, &netErr)
+		require.Equal(t, "Post", netErr.Op)
+		require.Equal(t, server.URL+"/query/1", netErr.URL)
+		require.True(t, netErr.Temporary())
+		require.False(t, netErr.Timeout())
+		require.ErrorIs(t, err, syscall.ECONNREFUSED)
+		require.Equal(t, []int{0, 0}, statuses)
+	})
+
+	t.Run("retries idempotent requests", func(t *testing.T) {
+		var calls int
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			if calls++; calls == 1 {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				_ = conn.Close()
+				return
+			}
+			_, _ = w.Write([]byte(`{"events":[],"cursor":"a","has_next":false,"stats":{}}`))
+		}, fauna.MaxBackoff(time.Millisecond))
+
+		feed, err := client.Feed("token")
+		require.NoError(t, err)
+
+		var page fauna.FeedPage
+		require.NoError(t, feed.Next(&page))
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("reports timeouts", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+		}, fauna.HTTPClient(&http.Client{Timeout: 10 * time.Millisecond}), fauna.MaxAttempts(1))
+
+		q, _ := fauna.FQL(`null`, nil)
+		_, err := client.Query(q)
+
+		var netErr *fauna.ErrNetwork
+		require.ErrorAs(t, err, &netErr)
+		require.True(t, netErr.Timeout())
+		require.True(t, netErr.Temporary())
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	throttled := true
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		if throttled {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":"limit_exceeded","message":"Rate limit exceeded"},"txn_ts":1,"stats":{"rate_limits_hit":["read"]}}`))
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxAttempts(1))
+
+	q, _ := fauna.FQL(`Product.all()`, nil)
+
+	_, err := client.Query(q)
+	var throttling *fauna.ErrThrottling
+	require.ErrorAs(t, err, &throttling)
+	require.Equal(t, &fauna.RateLimit{
+		LimitsHit:  []string{"read"},
+		RetryAfter: 3 * time.Second,
+		Limit:      -1,
+		Remaining:  -1,
+	}, throttling.RateLimit)
+
+	throttled = false
+	res, err := client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, 100, res.RateLimit.Limit)
+	require.Equal(t, 99, res.RateLimit.Remaining)
+	require.Nil(t, res.RateLimit.LimitsHit)
+}
+
+func TestResponseHeader(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Region", "us-std")
+		if strings.Contains(r.Header.Get(fauna.HeaderTags), "fail") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"invalid_query","message":"bad"},"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	})
+
+	q, _ := fauna.FQL(`null`, nil)
+	res, err := client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, "us-std", res.Header.Get("X-Region"))
+
+	_, err = client.Query(q, fauna.Tags(map[string]string{"case": "fail"}))
+	var checkErr *fauna.ErrQueryCheck
+	require.ErrorAs(t, err, &checkErr)
+	require.Equal(t, "us-std", checkErr.QueryInfo.Header.Get("X-Region"))
+}
+
+func TestResultsAreComparable(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{"read_ops":1,"rate_limits_hit":["read","compute"]}}`))
+	})
+
+	q, _ := fauna.FQL(`null`, nil)
+	res, err := client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, []string{"read", "compute"}, res.Stats.RateLimitsHit())
+	require.True(t, *res.Stats == *res.Stats)
+	require.True(t, *res == *res)
+	require.True(t, fauna.Event{Stats: *res.Stats} == fauna.Event{Stats: *res.Stats})
+}
+
+func TestPageSize(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, string(body))
+
+		if len(queries) == 1 {
+			_, _ = w.Write([]byte(`{"data":{"@set":{"data":[{"@int":"1"}],"after":"next"}},"txn_ts":1,"stats":{}}`))
+		} else {
+			_, _ = w.Write([]byte(`{"data":{"data":[{"@int":"2"}]},"txn_ts":1,"stats":{}}`))
+		}
+	})
+
+	q, _ := fauna.FQL(`Product.all()`, nil)
+	iter := client.Paginate(q, fauna.PageSize(1))
+	require.Empty(t, iter.Cursor())
+
+	_, err := iter.Next()
+	require.NoError(t, err)
+	require.Equal(t, "next", iter.Cursor())
+	require.Contains(t, queries[0], `").pageSize(",{"value":{"@int":"1"}}`)
+
+	_, err = iter.Next(fauna.PageSize(50))
+	require.NoError(t, err)
+	require.Empty(t, iter.Cursor())
+	require.False(t, iter.HasNext())
+	require.Contains(t, queries[1], `Set.paginate(",{"value":"next"},", ",{"value":{"@int":"50"}}`)
+}
+
+func TestClientStats(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":"limit_exceeded","message":"slow down"},"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxBackoff(time.Millisecond))
+
+	stats := client.Stats()
+	require.Zero(t, stats.OpenConnections)
+	require.Zero(t, stats.Queries)
+
+	q, _ := fauna.FQL(`null`, nil)
+	_, err := client.Query(q)
+	require.NoError(t, err)
+
+	stats = client.Stats()
+	require.Equal(t, int64(1), stats.OpenConnections)
+	require.Equal(t, int64(1), stats.IdleConnections)
+	require.Zero(t, stats.InFlight)
+	require.Equal(t, int64(1), stats.Queries)
+	require.Equal(t, int64(1), stats.Retries)
+
+	custom := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {}, fauna.HTTPClient(http.DefaultClient))
+	require.Equal(t, int64(-1), custom.Stats().OpenConnections)
+	require.Equal(t, int64(-1), custom.Stats().IdleConnections)
+}
+
+func TestTransportLimits(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxConnsPerHost(1), fauna.MaxIdleConnsPerHost(1))
+
+	// The first query resolves the client's query URL, which isn't safe to do
+	// concurrently, so run it before the parallel queries.
+	q, _ := fauna.FQL(`null`, nil)
+	_, err := client.Query(q)
+	require.NoError(t, err)
+
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := client.Query(q)
+			errs <- err
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	require.Equal(t, int64(1), client.Stats().OpenConnections)
+}
+
+func TestTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	q, _ := fauna.FQL(`null`, nil)
+
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL), fauna.MaxAttempts(1))
+	_, err := client.Query(q)
+	require.ErrorContains(t, err, "certificate")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	cfg := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+
+	client = fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL), fauna.WithTLSConfig(cfg))
+	_, err = client.Query(q)
+	require.NoError(t, err)
+	require.Nil(t, cfg.NextProtos, "the config is copied")
+}
+
+func TestProxy(t *testing.T) {
+	var proxied []*http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r)
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}))
+	t.Cleanup(proxy.Close)
+
+	q, _ := fauna.FQL(`null`, nil)
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("user", "pass")
+
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL("http://fauna.invalid"), fauna.WithProxy(proxyURL.String()))
+	_, err := client.Query(q)
+	require.NoError(t, err)
+	require.Len(t, proxied, 1)
+	require.Equal(t, "http://fauna.invalid/query/1", proxied[0].RequestURI)
+	require.Equal(t, "Basic dXNlcjpwYXNz", proxied[0].Header.Get("Proxy-Authorization"))
+
+	for proxyURL, want := range map[string]string{
+		"ftp://proxy:21": `unsupported proxy scheme "ftp"`,
+		"socks5://":      `proxy URL "socks5://" has no host`,
+		"http://[::1":    "invalid proxy URL: ",
+	} {
+		client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.WithProxy(proxyURL))
+		_, err := client.Query(q)
+		require.ErrorContains(t, err, want)
+	}
+}
+
+func TestDialer(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "fauna.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	var dialed []string
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL("http://fauna.invalid"),
+		fauna.WithDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed = append(dialed, addr)
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}))
+
+	q, _ := fauna.FQL(`null`, nil)
+	_, err = client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fauna.invalid:80"}, dialed)
+	require.Equal(t, int64(1), client.Stats().OpenConnections)
+}
+
+func TestExperimental(t *testing.T) {
+	var paths, previews []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		previews = append(previews, r.Header.Get("X-Preview-Feature"))
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	},
+		fauna.Experimental("query_version", "2"),
+		fauna.Experimental("header", "X-Preview-Feature: on"),
+	)
+
+	q, _ := fauna.FQL(`null`, nil)
+	_, err := client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/query/2"}, paths)
+	require.Equal(t, []string{"on"}, previews)
+
+	for _, opt := range []fauna.ClientConfigFn{
+		fauna.Experimental("warp_drive", "on"),
+		fauna.Experimental("header", "no colon"),
+		fauna.Experimental("query_version", "2/../3"),
+		fauna.Experimental("header", "Authorization: Bearer other"),
+		fauna.Experimental("header", "x-linearized: true"),
+	} {
+		client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("no request should be sent")
+		}, opt)
+
+		_, err = client.Query(q)
+		require.ErrorContains(t, err, "invalid client configuration")
+	}
+	require.Contains(t, fauna.Experiments(), "header")
+
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request should be sent")
+	},
+		fauna.Experimental("header", "no colon"),
+		fauna.Experimental("warp_drive", "on"),
+	)
+	_, err = client.Query(q)
+	require.ErrorContains(t, err, "experiment header")
+	require.NotContains(t, err.Error(), "warp_drive")
+}
+
+func TestMiddleware(t *testing.T) {
+	var order []string
+	tag := func(name string) fauna.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return fauna.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				req.Header.Set("X-Request-Signature", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	httpClient := &http.Client{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "inner", r.Header.Get("X-Request-Signature"))
+		_, _ = w.Write([]byte(`{"data":{"@int":"1"},"txn_ts":1,"stats":{}}`))
+	}, fauna.WithMiddleware(tag("outer"), tag("inner")), fauna.HTTPClient(httpClient))
+
+	q, _ := fauna.FQL(`1`, nil)
+	_, err := client.Query(q)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"outer", "inner"}, order)
+	require.Nil(t, httpClient.Transport, "should not modify the provided http.Client")
+}
+
+func TestQueryIfModifiedSince(t *testing.T) {
+	var (
+		sent     string
+		modified bool
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		if modified {
+			_, _ = w.Write([]byte(`{"data":{"modified":true,"data":[{"@int":"1"}]},"txn_ts":1,"stats":{}}`))
+		} else {
+			_, _ = w.Write([]byte(`{"data":{"modified":false,"data":null},"txn_ts":1,"stats":{}}`))
+		}
+	})
+
+	watch, _ := fauna.FQL(`Product.byTs().first()?.ts`, nil)
+	read, _ := fauna.FQL(`Product.all().toArray()`, nil)
+	since := time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	res, changed, err := client.QueryIfModifiedSince(watch, since, read)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Nil(t, res.Data)
+	require.Contains(t, sent, `"Product.byTs().first()?.ts"`)
+	require.Contains(t, sent, `{"@time":"2023-02-28T00:00:00Z"}`)
+
+	modified = true
+	res, changed, err = client.QueryIfModifiedSince(watch, since, read)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	var ids []int
+	require.NoError(t, res.Unmarshal(&ids))
+	require.Equal(t, []int{1}, ids)
+}
+
+// newTestClient returns a [fauna.Client] pointed at an in-process server that
+// answers every request with handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...fauna.ClientConfigFn) *fauna.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return fauna.NewClient(
+		"secret",
+		fauna.DefaultTimeouts(),
+		append([]fauna.ClientConfigFn{fauna.URL(server.URL)}, opts...)...,
+	)
+}
@@ -0,0 +1,64 @@
+// Command fauna-gen generates Go structs and repositories for the
+// collections of a Fauna database. It connects with the FAUNA_SECRET and
+// FAUNA_ENDPOINT environment variables.
+//
+//	fauna-gen -package models -collections Product,Order -out models/fauna.go
+//
+// See package [github.com/fauna/fauna-go/v3/codegen] for what is generated.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/codegen"
+)
+
+func main() {
+	var (
+		pkg         = flag.String("package", "models", "name of the generated package")
+		collections = flag.String("collections", "", "comma separated collections to generate, all if empty")
+		sample      = flag.Int("sample", codegen.DefaultSampleSize, "documents sampled from collections without field definitions")
+		out         = flag.String("out", "", "file to write, standard output if empty")
+		timeout     = flag.Duration("timeout", time.Minute, "timeout for introspecting the database")
+	)
+	flag.Parse()
+
+	if err := run(*pkg, *collections, *sample, *out, *timeout); err != nil {
+		fmt.Fprintln(os.Stderr, "fauna-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, collections string, sample int, out string, timeout time.Duration) error {
+	client, err := fauna.NewDefaultClient()
+	if err != nil {
+		return err
+	}
+
+	opts := codegen.Options{Package: pkg, SampleSize: sample}
+	for _, name := range strings.Split(collections, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.Collections = append(opts.Collections, name)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	src, err := codegen.Generate(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,522 @@
+// Package codegen generates Go structs and repositories for Fauna
+// collections, so that hand-written structs don't drift from the documents
+// they hold. The cmd/fauna-gen command wraps it.
+//
+// The fields of a collection are taken from its field definitions when it has
+// any, and are otherwise inferred from a sample of its documents. For each
+// collection, Generate emits a struct of the document's fields with fauna
+// tags, a struct embedding [fauna.Document] along with it, and a repository
+// with Get, Create, Replace and Delete methods:
+//
+//	type ProductData struct {
+//		Name  string   `fauna:"name"`
+//		Price *float64 `fauna:"price"`
+//	}
+//
+//	type Product struct {
+//		fauna.Document
+//		ProductData
+//	}
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// DefaultSampleSize is the number of documents sampled from collections
+// without field definitions when [Options.SampleSize] is zero.
+const DefaultSampleSize = 20
+
+// Options configures [Generate].
+type Options struct {
+	// Package is the name of the generated package. Defaults to "models".
+	Package string
+
+	// Collections limits generation to the named collections. All
+	// collections are generated if it is empty.
+	Collections []string
+
+	// SampleSize is the number of documents sampled from collections without
+	// field definitions. Defaults to [DefaultSampleSize].
+	SampleSize int
+}
+
+// Collection describes the documents of a collection, as found by
+// [Introspect].
+type Collection struct {
+	Name   string
+	Fields []Field
+}
+
+// Field is a field of a collection's documents.
+type Field struct {
+	// Name is the name of the field in Fauna.
+	Name string
+	// GoName is the name of the struct field. It is derived from Name if
+	// empty.
+	GoName string
+	// GoType is the Go type of the struct field.
+	GoType string
+	// Hint is the fauna tag hint of the struct field, such as "date".
+	Hint string
+}
+
+// Generate introspects the collections selected by opts and returns the
+// formatted Go source of their structs and repositories.
+func Generate(ctx context.Context, client *fauna.Client, opts Options) ([]byte, error) {
+	collections, err := Introspect(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+	return Render(opts.Package, collections)
+}
+
+type introspected struct {
+	Name   string                    `fauna:"name"`
+	Fields map[string]fieldSignature `fauna:"fields"`
+	Sample []map[string]any          `fauna:"sample"`
+}
+
+type fieldSignature struct {
+	Signature string `fauna:"signature"`
+}
+
+// Introspect describes the collections selected by opts.
+func Introspect(ctx context.Context, client *fauna.Client, opts Options) ([]Collection, error) {
+	sampleSize := opts.SampleSize
+	if sampleSize <= 0 {
+		sampleSize = DefaultSampleSize
+	}
+
+	var names []string
+	if len(opts.Collections) > 0 {
+		names = opts.Collections
+	}
+
+	q, err := fauna.FQL(`Collection.all()
+  .where(c => ${names} == null || ${names}.includes(c.name))
+  .toArray()
+  .map(c => {
+    name: c.name,
+    fields: c.fields,
+    sample: if (c.fields == null) Collection(c.name).all().take(${size}).toArray() else []
+  })`, map[string]any{"names": names, "size": sampleSize})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Query(q, fauna.QueryContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect collections: %w", err)
+	}
+
+	var found []introspected
+	if err := res.Unmarshal(&found); err != nil {
+		return nil, fmt.Errorf("failed to decode collections: %w", err)
+	}
+
+	collections := make([]Collection, 0, len(found))
+	for _, c := range found {
+		collection := Collection{Name: c.Name}
+		if c.Fields != nil {
+			collection.Fields, err = defined(c.Fields)
+			if err != nil {
+				return nil, fmt.Errorf("collection %s: %w", c.Name, err)
+			}
+		} else {
+			collection.Fields = sampled(c.Sample)
+		}
+		collections = append(collections, collection)
+	}
+
+	sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+	return collections, nil
+}
+
+// metaFields are read into the embedded [fauna.Document] instead.
+var metaFields = map[string]bool{"id": true, "coll": true, "ts": true}
+
+func defined(fields map[string]fieldSignature) ([]Field, error) {
+	var out []Field
+	for name, def := range fields {
+		if metaFields[name] {
+			continue
+		}
+
+		t, err := fauna.ParseStaticType(def.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+
+		goType, hint := typeOf(t)
+		out = append(out, Field{Name: name, GoType: goType, Hint: hint})
+	}
+	return out, nil
+}
+
+// typeOf maps an FQL type to a Go type, and the fauna tag hint it needs.
+func typeOf(t *fauna.TypeDescriptor) (string, string) {
+	switch t.Kind {
+	case fauna.TypeNamed:
+		switch t.Name {
+		case "String", "ID":
+			return "string", ""
+		case "Int":
+			return "int", ""
+		case "Long":
+			return "int64", ""
+		case "Double", "Number":
+			return "float64", ""
+		case "Boolean":
+			return "bool", ""
+		case "Time":
+			return "time.Time", ""
+		case "Date":
+			return "time.Time", "date"
+		case "Bytes":
+			return "[]byte", ""
+		case "Array":
+			if len(t.Args) == 1 {
+				elem, _ := typeOf(t.Args[0])
+				return "[]" + elem, ""
+			}
+			return "[]any", ""
+		case "Ref":
+			return "*fauna.Ref", ""
+		case "Object":
+			return "map[string]any", ""
+		}
+		if len(t.Args) == 0 && unicode.IsUpper([]rune(t.Name)[0]) && t.Name != "Any" && t.Name != "Null" {
+			// Other capitalized names are taken to be collections, whose
+			// documents are stored as references.
+			return "*fauna.Ref", ""
+		}
+
+	case fauna.TypeUnion:
+		var members []*fauna.TypeDescriptor
+		for _, arg := range t.Args {
+			if !arg.IsNullable() {
+				members = append(members, arg)
+			}
+		}
+		if len(members) == 1 {
+			goType, hint := typeOf(members[0])
+			return nullable(goType), hint
+		}
+
+	case fauna.TypeObject:
+		if len(t.Fields) == 0 && t.Wildcard != nil {
+			elem, _ := typeOf(t.Wildcard)
+			return "map[string]" + elem, ""
+		}
+		return "map[string]any", ""
+
+	case fauna.TypeTuple:
+		return "[]any", ""
+
+	case fauna.TypeLiteral:
+		switch {
+		case strings.HasPrefix(t.Literal, `"`) || strings.HasPrefix(t.Literal, `'`):
+			return "string", ""
+		case t.Literal == "true" || t.Literal == "false":
+			return "bool", ""
+		case t.Literal != "null":
+			return "float64", ""
+		}
+	}
+	return "any", ""
+}
+
+// nullable returns the type of an optional field of type goType.
+func nullable(goType string) string {
+	if goType == "any" || strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") {
+		return goType
+	}
+	return "*" + goType
+}
+
+// sampled infers fields from sample documents. Fields missing from some
+// documents, or null in them, are optional, and fields of several types are
+// typed any, except integers and floats which are typed float64.
+func sampled(docs []map[string]any) []Field {
+	types := map[string]map[string]bool{}
+	optional := map[string]bool{}
+	for _, doc := range docs {
+		for name, v := range doc {
+			if metaFields[name] {
+				continue
+			}
+			if types[name] == nil {
+				types[name] = map[string]bool{}
+			}
+			if v == nil {
+				optional[name] = true
+			} else {
+				types[name][sampleType(v)] = true
+			}
+		}
+	}
+	for name := range types {
+		for _, doc := range docs {
+			if _, ok := doc[name]; !ok {
+				optional[name] = true
+			}
+		}
+	}
+
+	var out []Field
+	for name, seen := range types {
+		goType := "any"
+		switch {
+		case len(seen) == 1:
+			for t := range seen {
+				goType = t
+			}
+		case len(seen) == 2 && seen["int64"] && seen["float64"]:
+			goType = "float64"
+		}
+		if optional[name] {
+			goType = nullable(goType)
+		}
+		out = append(out, Field{Name: name, GoType: goType})
+	}
+	return out
+}
+
+func sampleType(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case int64:
+		return "int64"
+	case float64:
+		return "float64"
+	case bool:
+		return "bool"
+	case time.Time, *time.Time:
+		return "time.Time"
+	case []byte:
+		return "[]byte"
+	case *fauna.Module:
+		return "*fauna.Module"
+	case *fauna.Ref, *fauna.Document, *fauna.NullDocument:
+		return "*fauna.Ref"
+	case *fauna.NamedRef, *fauna.NamedDocument, *fauna.NullNamedDocument:
+		return "*fauna.NamedRef"
+	case []any:
+		return "[]any"
+	case map[string]any:
+		return "map[string]any"
+	}
+	return "any"
+}
+
+// named returns fields sorted, giving unique exported Go names to those
+// without one.
+func named(fields []Field) []Field {
+	fields = append([]Field(nil), fields...)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+
+	used := map[string]bool{"Document": true}
+	for _, f := range fields {
+		used[f.GoName] = true
+	}
+	for i := range fields {
+		if fields[i].GoName != "" {
+			continue
+		}
+		name := goName(fields[i].Name)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", goName(fields[i].Name), n)
+		}
+		used[name] = true
+		fields[i].GoName = name
+	}
+	return fields
+}
+
+var initialisms = map[string]string{"id": "ID", "url": "URL", "api": "API", "http": "HTTP", "json": "JSON", "sku": "SKU", "uuid": "UUID"}
+
+// goName converts a Fauna name, such as first_name or firstName, into an
+// exported Go name, such as FirstName.
+func goName(name string) string {
+	var words []string
+	word := []rune{}
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	for i, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0:
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var sb strings.Builder
+	for _, w := range words {
+		if initialism, ok := initialisms[strings.ToLower(w)]; ok {
+			sb.WriteString(initialism)
+			continue
+		}
+		r := []rune(w)
+		sb.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+
+	out := sb.String()
+	if out == "" || !unicode.IsLetter([]rune(out)[0]) {
+		out = "F" + out
+	}
+	return out
+}
+
+// Render returns the formatted Go source of the structs and repositories of
+// collections, in package pkg.
+func Render(pkg string, collections []Collection) ([]byte, error) {
+	if pkg == "" {
+		pkg = "models"
+	}
+
+	data := struct {
+		Package     string
+		Time        bool
+		Collections []renderedCollection
+	}{Package: pkg}
+
+	used := map[string]bool{}
+	for _, c := range collections {
+		name := goName(c.Name)
+		if used[name] {
+			return nil, fmt.Errorf("collections map to the same Go name %s", name)
+		}
+		used[name] = true
+
+		c.Fields = named(c.Fields)
+		data.Collections = append(data.Collections, renderedCollection{Collection: c, GoName: name})
+		for _, f := range c.Fields {
+			if strings.Contains(f.GoType, "time.Time") {
+				data.Time = true
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := sourceTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+type renderedCollection struct {
+	Collection
+	GoName string
+}
+
+// Tag returns the struct tag of the field.
+func (f Field) Tag() string {
+	if f.Hint != "" {
+		return fmt.Sprintf("`fauna:%q`", f.Name+","+f.Hint)
+	}
+	return fmt.Sprintf("`fauna:%q`", f.Name)
+}
+
+var sourceTemplate = template.Must(template.New("source").Parse(`// Code generated by fauna-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+{{- if .Time}}
+	"time"
+{{- end}}
+
+	"github.com/fauna/fauna-go/v3"
+)
+{{range .Collections}}
+// {{.GoName}}Data holds the fields of a document in the {{.Name}} collection.
+type {{.GoName}}Data struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} {{.Tag}}
+{{- end}}
+}
+
+// {{.GoName}} is a document in the {{.Name}} collection.
+type {{.GoName}} struct {
+	fauna.Document
+	{{.GoName}}Data
+}
+
+// {{.GoName}}Repository reads and writes documents in the {{.Name}} collection.
+type {{.GoName}}Repository struct {
+	client *fauna.Client
+}
+
+// New{{.GoName}}Repository returns a repository using client.
+func New{{.GoName}}Repository(client *fauna.Client) *{{.GoName}}Repository {
+	return &{{.GoName}}Repository{client: client}
+}
+
+// Get returns the document with the given id.
+func (r *{{.GoName}}Repository) Get(ctx context.Context, id string) (*{{.GoName}}, error) {
+	var doc {{.GoName}}
+	err := r.query(ctx, ` + "`" + `${coll}.byId(${id})!` + "`" + `, map[string]any{"id": id}, &doc)
+	return &doc, err
+}
+
+// Create creates a document holding data.
+func (r *{{.GoName}}Repository) Create(ctx context.Context, data {{.GoName}}Data) (*{{.GoName}}, error) {
+	var doc {{.GoName}}
+	err := r.query(ctx, ` + "`" + `${coll}.create(${data})` + "`" + `, map[string]any{"data": data}, &doc)
+	return &doc, err
+}
+
+// Replace replaces the fields of the document with the given id with data.
+func (r *{{.GoName}}Repository) Replace(ctx context.Context, id string, data {{.GoName}}Data) (*{{.GoName}}, error) {
+	var doc {{.GoName}}
+	err := r.query(ctx, ` + "`" + `${coll}.byId(${id})!.replace(${data})` + "`" + `, map[string]any{"id": id, "data": data}, &doc)
+	return &doc, err
+}
+
+// Delete deletes the document with the given id.
+func (r *{{.GoName}}Repository) Delete(ctx context.Context, id string) error {
+	return r.query(ctx, ` + "`" + `${coll}.byId(${id})!.delete()
+null` + "`" + `, map[string]any{"id": id}, nil)
+}
+
+func (r *{{.GoName}}Repository) query(ctx context.Context, fql string, args map[string]any, into any) error {
+	args["coll"] = &fauna.Module{Name: {{printf "%q" .Name}}}
+	q, err := fauna.FQL(fql, args)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.client.Query(q, fauna.QueryContext(ctx))
+	if err != nil || into == nil {
+		return err
+	}
+	return res.Unmarshal(into)
+}
+{{end}}`))
@@ -0,0 +1,84 @@
+package codegen_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/codegen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[
+  {"name":"Product","fields":{
+    "name":{"signature":"String"},
+    "price":{"signature":"Double?"},
+    "tags":{"signature":"Array<String>"},
+    "released":{"signature":"Date"},
+    "vendor":{"signature":"Vendor | Null"}
+  },"sample":[]},
+  {"name":"order_line","fields":null,"sample":[
+    {"@doc":{"id":"1","coll":{"@mod":"order_line"},"ts":{"@time":"2024-01-01T00:00:00Z"},"sku":"a","qty":{"@int":"1"},"note":"x"}},
+    {"@doc":{"id":"2","coll":{"@mod":"order_line"},"ts":{"@time":"2024-01-01T00:00:00Z"},"sku":"b","qty":{"@double":"1.5"},"product":{"@ref":{"id":"3","coll":{"@mod":"Product"}}}}}
+  ]}
+],"txn_ts":1,"stats":{}}`))
+	}))
+	defer server.Close()
+
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL))
+	src, err := codegen.Generate(context.Background(), client, codegen.Options{Package: "store"})
+	require.NoError(t, err)
+
+	code := string(src)
+	for _, want := range []string{
+		"package store",
+		`"time"`,
+		"type ProductData struct {",
+		"Name     string     `fauna:\"name\"`",
+		"Price    *float64   `fauna:\"price\"`",
+		"Released time.Time  `fauna:\"released,date\"`",
+		"Tags     []string   `fauna:\"tags\"`",
+		"Vendor   *fauna.Ref `fauna:\"vendor\"`",
+		"type Product struct {\n\tfauna.Document\n\tProductData\n}",
+		"func NewProductRepository(client *fauna.Client) *ProductRepository",
+		"type OrderLineData struct {",
+		"Note    *string    `fauna:\"note\"`",
+		"Product *fauna.Ref `fauna:\"product\"`",
+		"Qty     float64    `fauna:\"qty\"`",
+		"SKU     string     `fauna:\"sku\"`",
+		`args["coll"] = &fauna.Module{Name: "order_line"}`,
+	} {
+		require.Contains(t, code, want)
+	}
+	require.NotContains(t, code, "`fauna:\"id\"`")
+}
+
+func TestRender(t *testing.T) {
+	t.Run("names fields", func(t *testing.T) {
+		src, err := codegen.Render("", []codegen.Collection{{Name: "User", Fields: []codegen.Field{
+			{Name: "first_name", GoType: "string"},
+			{Name: "firstName", GoType: "string"},
+			{Name: "homeURL", GoType: "string"},
+			{Name: "2fa", GoType: "bool"},
+		}}})
+		require.NoError(t, err)
+
+		code := string(src)
+		require.True(t, strings.HasPrefix(code, "// Code generated by fauna-gen. DO NOT EDIT.\n\npackage models\n"))
+		require.NotContains(t, code, `"time"`)
+		require.Contains(t, code, "F2fa       bool   `fauna:\"2fa\"`")
+		require.Contains(t, code, "FirstName  string `fauna:\"firstName\"`")
+		require.Contains(t, code, "FirstName2 string `fauna:\"first_name\"`")
+		require.Contains(t, code, "HomeURL    string `fauna:\"homeURL\"`")
+	})
+
+	t.Run("rejects clashing collections", func(t *testing.T) {
+		_, err := codegen.Render("", []codegen.Collection{{Name: "order_line"}, {Name: "OrderLine"}})
+		require.ErrorContains(t, err, "same Go name OrderLine")
+	})
+}
@@ -0,0 +1,63 @@
+// Package compat adapts the call signatures of earlier major versions of the
+// driver onto the v3 [fauna.Client], so that large codebases can migrate to
+// v3 incrementally. New code should use the fauna package directly.
+package compat
+
+import (
+	"github.com/fauna/fauna-go/v3"
+)
+
+// Client wraps a v3 [fauna.Client], adding the methods of earlier versions.
+// The v3 methods remain available through the embedded client.
+type Client struct {
+	*fauna.Client
+}
+
+// Wrap returns a Client for an existing v3 client.
+func Wrap(client *fauna.Client) *Client {
+	return &Client{Client: client}
+}
+
+// DefaultClient returns a client configured from the FAUNA_SECRET and
+// FAUNA_ENDPOINT environment variables. It replaces fauna.DefaultClient.
+func DefaultClient() (*Client, error) {
+	client, err := fauna.NewDefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(client), nil
+}
+
+// NewClient returns a client using the default timeouts. It replaces
+// fauna.NewClient from before timeouts were a required argument.
+func NewClient(secret string, configFns ...fauna.ClientConfigFn) *Client {
+	return Wrap(fauna.NewClient(secret, fauna.DefaultTimeouts(), configFns...))
+}
+
+// Query runs the FQL template fql with args, and unmarshals the result into
+// into, if it isn't nil. It replaces the Query(string, args, into) signature.
+func (c *Client) Query(fql string, args map[string]any, into any, opts ...fauna.QueryOptFn) error {
+	_, err := c.QueryWithInfo(fql, args, into, opts...)
+	return err
+}
+
+// QueryWithInfo is like [Client.Query], but also returns the query's stats
+// and other information.
+func (c *Client) QueryWithInfo(fql string, args map[string]any, into any, opts ...fauna.QueryOptFn) (*fauna.QueryInfo, error) {
+	q, err := fauna.FQL(fql, args)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.Client.Query(q, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if into != nil {
+		if err := res.Unmarshal(into); err != nil {
+			return res.QueryInfo, err
+		}
+	}
+	return res.QueryInfo, nil
+}
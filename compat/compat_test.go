@@ -0,0 +1,45 @@
+package compat_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/compat"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.True(t, strings.Contains(string(body), `{"value":"limes"}`), string(body))
+		_, _ = w.Write([]byte(`{"data":{"name":"limes","quantity":{"@int":"3"}},"txn_ts":1,"stats":{"read_ops":1}}`))
+	}))
+	defer server.Close()
+
+	client := compat.NewClient("secret", fauna.URL(server.URL))
+
+	var product struct {
+		Name     string `fauna:"name"`
+		Quantity int    `fauna:"quantity"`
+	}
+	require.NoError(t, client.Query(`Product.byName(${name}).first()`, map[string]any{"name": "limes"}, &product))
+	require.Equal(t, "limes", product.Name)
+	require.Equal(t, 3, product.Quantity)
+
+	info, err := client.QueryWithInfo(`Product.byName(${name}).first()`, map[string]any{"name": "limes"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, info.Stats.ReadOps)
+}
+
+func TestDefaultClientFromEnv(t *testing.T) {
+	t.Setenv(fauna.EnvFaunaSecret, "secret")
+	t.Setenv(fauna.EnvFaunaEndpoint, fauna.EndpointLocal)
+
+	client, err := compat.DefaultClient()
+	require.NoError(t, err)
+	require.Equal(t, fauna.EndpointLocal, client.String())
+}
@@ -0,0 +1,168 @@
+package fauna
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the configuration read by NewClientFromConfig.
+type fileConfig struct {
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	Secret   string `yaml:"secret" toml:"secret"`
+
+	Timeouts struct {
+		Query          time.Duration `yaml:"query" toml:"query"`
+		ClientBuffer   time.Duration `yaml:"client_buffer" toml:"client_buffer"`
+		Connection     time.Duration `yaml:"connection" toml:"connection"`
+		IdleConnection time.Duration `yaml:"idle_connection" toml:"idle_connection"`
+		TLSHandshake   time.Duration `yaml:"tls_handshake" toml:"tls_handshake"`
+	} `yaml:"timeouts" toml:"timeouts"`
+
+	Retry struct {
+		MaxAttempts int           `yaml:"max_attempts" toml:"max_attempts"`
+		MaxBackoff  time.Duration `yaml:"max_backoff" toml:"max_backoff"`
+	} `yaml:"retry" toml:"retry"`
+
+	Headers  map[string]string `yaml:"headers" toml:"headers"`
+	LogLevel string            `yaml:"log_level" toml:"log_level"`
+}
+
+// logLevels are the log levels of config files, as the levels of
+// [EnvFaunaDebug].
+var logLevels = map[string]int{
+	"debug": -4,
+	"info":  0,
+	"warn":  4,
+	"error": 8,
+}
+
+// NewClientFromConfig initializes a [fauna.Client] from the JSON, YAML or
+// TOML file at path, such as a mounted Kubernetes ConfigMap:
+//
+//	endpoint: https://db.fauna.com
+//	timeouts:
+//	  query: 5s
+//	  client_buffer: 5s
+//	  connection: 10s
+//	  idle_connection: 5s
+//	  tls_handshake: 10s
+//	retry:
+//	  max_attempts: 5
+//	  max_backoff: 10s
+//	headers:
+//	  X-Query-Tags: service=orders
+//	log_level: info
+//
+// Settings missing from the file keep the defaults of [NewDefaultClient].
+// The file may hold the secret too, but it is better kept out of it and set
+// with [EnvFaunaSecret]. The environment variables honored by
+// NewDefaultClient and [EnvFaunaDebug] override the file, and opts override
+// both.
+func NewClientFromConfig(path string, opts ...ClientConfigFn) (*Client, error) {
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := cfg.Secret
+	if val, found := os.LookupEnv(EnvFaunaSecret); found {
+		secret = val
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("no secret in %s or environment variable '%s'", path, EnvFaunaSecret)
+	}
+
+	endpoint := cfg.Endpoint
+	if val, found := os.LookupEnv(EnvFaunaEndpoint); found {
+		endpoint = val
+	}
+	if endpoint == "" {
+		endpoint = EndpointDefault
+	}
+
+	timeouts := DefaultTimeouts()
+	for _, timeout := range []struct {
+		value time.Duration
+		field *time.Duration
+	}{
+		{cfg.Timeouts.Query, &timeouts.QueryTimeout},
+		{cfg.Timeouts.ClientBuffer, &timeouts.ClientBufferTimeout},
+		{cfg.Timeouts.Connection, &timeouts.ConnectionTimeout},
+		{cfg.Timeouts.IdleConnection, &timeouts.IdleConnectionTimeout},
+		{cfg.Timeouts.TLSHandshake, &timeouts.TLSHandshakeTimeout},
+	} {
+		if timeout.value != 0 {
+			*timeout.field = timeout.value
+		}
+	}
+
+	configFns := []ClientConfigFn{URL(endpoint)}
+	if cfg.Retry.MaxAttempts != 0 {
+		configFns = append(configFns, MaxAttempts(cfg.Retry.MaxAttempts))
+	}
+	if cfg.Retry.MaxBackoff != 0 {
+		configFns = append(configFns, MaxBackoff(cfg.Retry.MaxBackoff))
+	}
+	if len(cfg.Headers) > 0 {
+		configFns = append(configFns, AdditionalHeaders(cfg.Headers))
+	}
+	if _, found := os.LookupEnv(EnvFaunaDebug); !found && cfg.LogLevel != "" {
+		configFns = append(configFns, WithLogger(newLevelLogger(logLevels[cfg.LogLevel])))
+	}
+
+	timeouts, envFns, err := envConfig(timeouts)
+	if err != nil {
+		return nil, err
+	}
+	configFns = append(append(configFns, envFns...), opts...)
+
+	return NewClient(secret, timeouts, configFns...), nil
+}
+
+// readConfigFile reads and validates the config file at path.
+func readConfigFile(path string) (*fileConfig, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".json", ".yaml", ".yml", ".toml":
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q, use .json, .yaml, .yml or .toml", ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg fileConfig
+	if ext == ".toml" {
+		md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("invalid config file %s: field %s not found", path, undecoded[0])
+		}
+	} else {
+		// JSON is valid YAML
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+
+	if cfg.Retry.MaxAttempts < 0 {
+		return nil, fmt.Errorf("invalid config file %s: retry.max_attempts must not be negative", path)
+	}
+	if _, ok := logLevels[cfg.LogLevel]; !ok && cfg.LogLevel != "" {
+		return nil, fmt.Errorf("invalid config file %s: unknown log_level %q, use debug, info, warn or error", path, cfg.LogLevel)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,120 @@
+package fauna_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientFromConfig(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header)
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"code":"limit_exceeded","message":"Rate limit exceeded"},"stats":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	writeConfig := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	q, _ := fauna.FQL(`null`, nil)
+
+	t.Run("reads YAML", func(t *testing.T) {
+		headers = nil
+		path := writeConfig("fauna.yaml", `
+endpoint: `+server.URL+`
+secret: from-file
+timeouts:
+  query: 2500ms
+retry:
+  max_attempts: 3
+  max_backoff: 1ms
+headers:
+  X-Query-Tags: service=orders
+log_level: warn
+`)
+		client, err := fauna.NewClientFromConfig(path)
+		require.NoError(t, err)
+
+		_, err = client.Query(q)
+		require.ErrorAs(t, err, new(*fauna.ErrThrottling))
+		require.Len(t, headers, 3)
+		require.Equal(t, "Bearer from-file", headers[0].Get("Authorization"))
+		require.Equal(t, "2500", headers[0].Get(fauna.HeaderQueryTimeoutMs))
+		require.Equal(t, "service=orders", headers[0].Get(fauna.HeaderTags))
+	})
+
+	t.Run("reads TOML", func(t *testing.T) {
+		headers = nil
+		path := writeConfig("fauna.toml", `
+endpoint = "`+server.URL+`"
+secret = "from-file"
+log_level = "warn"
+
+[timeouts]
+query = "2500ms"
+
+[retry]
+max_attempts = 3
+max_backoff = "1ms"
+
+[headers]
+X-Query-Tags = "service=orders"
+`)
+		client, err := fauna.NewClientFromConfig(path)
+		require.NoError(t, err)
+
+		_, err = client.Query(q)
+		require.ErrorAs(t, err, new(*fauna.ErrThrottling))
+		require.Len(t, headers, 3)
+		require.Equal(t, "Bearer from-file", headers[0].Get("Authorization"))
+		require.Equal(t, "2500", headers[0].Get(fauna.HeaderQueryTimeoutMs))
+		require.Equal(t, "service=orders", headers[0].Get(fauna.HeaderTags))
+	})
+
+	t.Run("reads JSON with overrides", func(t *testing.T) {
+		headers = nil
+		path := writeConfig("fauna.json", `{"endpoint":"`+server.URL+`","retry":{"max_attempts":3,"max_backoff":"1ms"}}`)
+		t.Setenv(fauna.EnvFaunaSecret, "from-env")
+		t.Setenv(fauna.EnvFaunaMaxAttempts, "2")
+
+		client, err := fauna.NewClientFromConfig(path, fauna.DefaultTypecheck(true))
+		require.NoError(t, err)
+
+		_, err = client.Query(q)
+		require.ErrorAs(t, err, new(*fauna.ErrThrottling))
+		require.Len(t, headers, 2)
+		require.Equal(t, "Bearer from-env", headers[0].Get("Authorization"))
+		require.Equal(t, "true", headers[0].Get(fauna.HeaderTypecheck))
+	})
+
+	t.Run("validates the file", func(t *testing.T) {
+		for content, want := range map[string]string{
+			"secret: s\nretries: 3\n":            "field retries not found",
+			"secret: s\nlog_level: verbose\n":    `unknown log_level "verbose"`,
+			"secret: s\ntimeouts:\n  query: 5\n": "invalid config file",
+			"endpoint: http://localhost\n":       "no secret in ",
+		} {
+			_, err := fauna.NewClientFromConfig(writeConfig("fauna.yml", content))
+			require.ErrorContains(t, err, want)
+		}
+
+		_, err := fauna.NewClientFromConfig(writeConfig("fauna.toml", "secret = \"s\"\nretries = 3\n"))
+		require.ErrorContains(t, err, "field retries not found")
+
+		_, err = fauna.NewClientFromConfig(writeConfig("fauna.ini", "secret=s\n"))
+		require.EqualError(t, err, `unsupported config file format ".ini", use .json, .yaml, .yml or .toml`)
+
+		_, err = fauna.NewClientFromConfig(filepath.Join(dir, "missing.yaml"))
+		require.ErrorContains(t, err, "failed to read config file")
+	})
+}
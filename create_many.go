@@ -0,0 +1,78 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CreateMany creates a document in coll for each of docs, chunkSize documents
+// per query so that large loads stay within Fauna's transaction limits, and
+// returns refs to them in the order of docs.
+//
+// A chunk that fails because of contention or throttling is retried as a
+// whole, as no document from it was created, with the client's backoff and
+// up to its [MaxAttempts]. If a chunk still fails, CreateMany returns the
+// refs of the documents created by the chunks before it along with the
+// error, so that the load can resume from docs[len(refs):].
+func (c *Client) CreateMany(ctx context.Context, coll Module, docs []any, chunkSize int) ([]*Ref, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+
+	refs := make([]*Ref, 0, len(docs))
+	for start := 0; start < len(docs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		ids, err := c.createChunk(ctx, &coll, docs[start:end])
+		if err != nil {
+			return refs, fmt.Errorf("failed to create documents %d to %d: %w", start, end-1, err)
+		}
+
+		for _, id := range ids {
+			refs = append(refs, &Ref{ID: id, Coll: &coll})
+		}
+	}
+	return refs, nil
+}
+
+// createChunk creates docs in a single query, retrying it if it failed
+// without creating any of them, and returns their ids.
+func (c *Client) createChunk(ctx context.Context, coll *Module, docs []any) ([]string, error) {
+	q, err := FQL(`${docs}.map(doc => ${coll}.create(doc).id)`, map[string]any{"docs": docs, "coll": coll})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := c.QueryWithContext(ctx, q)
+		if err == nil {
+			var ids []string
+			if err := res.Unmarshal(&ids); err != nil {
+				return nil, err
+			}
+			if len(ids) != len(docs) {
+				return nil, fmt.Errorf("expected %d ids, got %d", len(docs), len(ids))
+			}
+			return ids, nil
+		}
+
+		var contended *ErrContendedTransaction
+		var throttled *ErrThrottling
+		if attempt >= c.maxAttempts || !(errors.As(err, &contended) || errors.As(err, &throttled)) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
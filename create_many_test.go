@@ -0,0 +1,92 @@
+package fauna_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMany(t *testing.T) {
+	var (
+		chunks   [][]any
+		requests int
+		nextID   int
+		failing  = map[int]int{}
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		var body struct {
+			Query struct {
+				FQL []json.RawMessage `json:"fql"`
+			} `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Len(t, body.Query.FQL, 4)
+
+		var docs struct {
+			Value []any `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal(body.Query.FQL[0], &docs))
+
+		chunk := len(chunks)
+		if failing[chunk] > 0 {
+			failing[chunk]--
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"code":"contended_transaction","message":"contended"},"stats":{}}`))
+			return
+		}
+		if failing[chunk] < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"constraint_failure","message":"unique"},"stats":{}}`))
+			return
+		}
+		chunks = append(chunks, docs.Value)
+
+		ids := make([]string, len(docs.Value))
+		for i := range ids {
+			nextID++
+			ids[i] = fmt.Sprintf("%q", fmt.Sprint(nextID))
+		}
+		_, _ = fmt.Fprintf(w, `{"data":[%s],"stats":{}}`, strings.Join(ids, ","))
+	}, fauna.MaxBackoff(time.Millisecond))
+
+	docs := []any{
+		map[string]any{"name": "apples"},
+		map[string]any{"name": "pears"},
+		map[string]any{"name": "limes"},
+		map[string]any{"name": "lemons"},
+		map[string]any{"name": "figs"},
+	}
+
+	failing[1] = 2 // the second chunk is contended twice
+	refs, err := client.CreateMany(context.Background(), fauna.Module{Name: "Product"}, docs, 2)
+	require.NoError(t, err)
+	require.Equal(t, 5, requests)
+	require.Len(t, chunks, 3)
+	require.Equal(t, []int{2, 2, 1}, []int{len(chunks[0]), len(chunks[1]), len(chunks[2])})
+
+	require.Len(t, refs, 5)
+	require.Equal(t, "1", refs[0].ID)
+	require.Equal(t, "5", refs[4].ID)
+	require.Equal(t, "Product", refs[4].Coll.Name)
+
+	// a chunk failing otherwise stops the load
+	chunks, requests, nextID = nil, 0, 0
+	failing[1] = -1
+	refs, err = client.CreateMany(context.Background(), fauna.Module{Name: "Product"}, docs, 2)
+	require.ErrorContains(t, err, "failed to create documents 2 to 3: ")
+	require.ErrorAs(t, err, new(*fauna.ErrQueryRuntime))
+	require.Len(t, refs, 2)
+	require.Equal(t, 2, requests)
+
+	_, err = client.CreateMany(context.Background(), fauna.Module{Name: "Product"}, docs, 0)
+	require.EqualError(t, err, "chunk size must be positive, got 0")
+}
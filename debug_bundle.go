@@ -0,0 +1,299 @@
+package fauna
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	captureBodyLimit = 64 * 1024
+	redactedValue    = "hidden"
+
+	// omittedLine replaces body lines that can't be parsed, and so can't be
+	// redacted, such as lines cut short by captureBodyLimit.
+	omittedLine = `"[omitted: not JSON or too large]"`
+)
+
+// CapturedExchange is a sanitized request/response pair recorded when traffic
+// capture is enabled with [fauna.WithTrafficCapture].
+type CapturedExchange struct {
+	Time            time.Time     `json:"time"`
+	Duration        time.Duration `json:"duration"`
+	Method          string        `json:"method"`
+	URL             string        `json:"url"`
+	RequestHeaders  http.Header   `json:"request_headers"`
+	RequestBody     string        `json:"request_body"`
+	StatusCode      int           `json:"status_code,omitempty"`
+	ResponseHeaders http.Header   `json:"response_headers,omitempty"`
+	ResponseBody    string        `json:"response_body,omitempty"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// DebugBundle holds the recent traffic of a [fauna.Client], suitable for
+// attaching to a support ticket.
+type DebugBundle struct {
+	DriverVersion string             `json:"driver_version"`
+	Endpoint      string             `json:"endpoint"`
+	Server        *ServerInfo        `json:"server,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	Exchanges     []CapturedExchange `json:"exchanges"`
+}
+
+// DebugBundle returns the traffic recorded by [fauna.WithTrafficCapture],
+// oldest first, along with what is known of the backend the client last
+// talked to. No traffic is included if traffic capture isn't enabled.
+func (c *Client) DebugBundle() *DebugBundle {
+	bundle := &DebugBundle{
+		DriverVersion: strings.TrimSpace(driverVersion),
+		Endpoint:      c.url,
+		Server:        c.LastServerInfo(),
+		CreatedAt:     time.Now().UTC(),
+		Exchanges:     []CapturedExchange{},
+	}
+
+	if c.capture != nil {
+		bundle.Exchanges = c.capture.exchanges()
+	}
+
+	return bundle
+}
+
+type trafficCapture struct {
+	mu      sync.Mutex
+	entries []CapturedExchange
+	next    int
+	full    bool
+
+	redactFields map[string]bool
+}
+
+func newTrafficCapture(size int, redactFields []string) *trafficCapture {
+	if size < 1 {
+		size = 1
+	}
+
+	fields := map[string]bool{"secret": true}
+	for _, f := range redactFields {
+		fields[f] = true
+	}
+
+	return &trafficCapture{
+		entries:      make([]CapturedExchange, size),
+		redactFields: fields,
+	}
+}
+
+func (tc *trafficCapture) add(exchange CapturedExchange) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.entries[tc.next] = exchange
+	tc.next = (tc.next + 1) % len(tc.entries)
+	if tc.next == 0 {
+		tc.full = true
+	}
+}
+
+func (tc *trafficCapture) exchanges() []CapturedExchange {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if !tc.full {
+		return append([]CapturedExchange{}, tc.entries[:tc.next]...)
+	}
+
+	out := make([]CapturedExchange, 0, len(tc.entries))
+	out = append(out, tc.entries[tc.next:]...)
+	return append(out, tc.entries[:tc.next]...)
+}
+
+// record captures a single attempt made by the client. The response body is
+// recorded as it is read, so that streaming responses aren't held up.
+func (tc *trafficCapture) record(req *http.Request, body []byte, res *http.Response, err error, start time.Time) {
+	exchange := CapturedExchange{
+		Time:           start.UTC(),
+		Duration:       time.Since(start),
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: tc.redactHeaders(req.Header),
+		RequestBody:    string(tc.redactBody(body)),
+	}
+
+	if err != nil {
+		exchange.Error = err.Error()
+	}
+
+	if res == nil {
+		tc.add(exchange)
+		return
+	}
+
+	exchange.StatusCode = res.StatusCode
+	exchange.ResponseHeaders = tc.redactHeaders(res.Header)
+	res.Body = &captureBody{
+		ReadCloser: res.Body,
+		redactLine: tc.redactLine,
+		done: func(resBody []byte) {
+			exchange.ResponseBody = string(resBody)
+			tc.add(exchange)
+		},
+	}
+}
+
+func (tc *trafficCapture) redactHeaders(headers http.Header) http.Header {
+	out := headers.Clone()
+	if _, found := out[headerAuthorization]; found {
+		out[headerAuthorization] = []string{redactedValue}
+	}
+	return out
+}
+
+// redactBody redacts each line of body, which holds a single JSON value or,
+// for streams and feeds, one per line, and bounds the result to
+// captureBodyLimit. Lines that can't be parsed are omitted rather than
+// recorded unredacted.
+func (tc *trafficCapture) redactBody(body []byte) []byte {
+	var out bytes.Buffer
+	for _, line := range bytes.SplitAfter(body, []byte("\n")) {
+		if appendBounded(&out, tc.redactLine(line)) {
+			break
+		}
+	}
+	return out.Bytes()
+}
+
+func (tc *trafficCapture) redactLine(line []byte) []byte {
+	trimmed := bytes.TrimRight(line, "\r\n")
+	newline := line[len(trimmed):]
+	if len(bytes.TrimSpace(trimmed)) == 0 {
+		return line
+	}
+
+	var value any
+	if err := json.Unmarshal(trimmed, &value); err != nil {
+		return append([]byte(omittedLine), newline...)
+	}
+
+	redacted, err := json.Marshal(redactFields(value, tc.redactFields))
+	if err != nil {
+		return append([]byte(omittedLine), newline...)
+	}
+	return append(redacted, newline...)
+}
+
+// appendBounded appends b to out, up to captureBodyLimit, and reports whether
+// the limit was reached.
+func appendBounded(out *bytes.Buffer, b []byte) bool {
+	remaining := captureBodyLimit - out.Len()
+	if len(b) > remaining {
+		out.Write(b[:remaining])
+		return true
+	}
+	out.Write(b)
+	return false
+}
+
+// redactFields replaces the value of any object key found in fields.
+func redactFields(value any, fields map[string]bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, field := range v {
+			if fields[k] {
+				v[k] = redactedValue
+			} else {
+				v[k] = redactFields(field, fields)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactFields(item, fields)
+		}
+	}
+	return value
+}
+
+// captureBody redacts the response body line by line as it is read, keeping
+// at most captureBodyLimit bytes of redacted output.
+type captureBody struct {
+	io.ReadCloser
+
+	redactLine func([]byte) []byte
+	line       bytes.Buffer
+	overflow   bool
+	out        bytes.Buffer
+	full       bool
+
+	once sync.Once
+	done func([]byte)
+}
+
+func (b *captureBody) Read(p []byte) (n int, err error) {
+	n, err = b.ReadCloser.Read(p)
+
+	data := p[:n]
+	for len(data) > 0 && !b.full {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			b.buffer(data)
+			break
+		}
+
+		b.buffer(data[:i+1])
+		b.flushLine(true)
+		data = data[i+1:]
+	}
+
+	if err == io.EOF {
+		b.finish()
+	}
+	return
+}
+
+// buffer adds data to the current line, dropping the line if it outgrows
+// captureBodyLimit, as it couldn't be redacted in full.
+func (b *captureBody) buffer(data []byte) {
+	if b.overflow {
+		return
+	}
+	if b.line.Len()+len(data) > captureBodyLimit {
+		b.overflow = true
+		b.line.Reset()
+		return
+	}
+	b.line.Write(data)
+}
+
+func (b *captureBody) flushLine(newline bool) {
+	var redacted []byte
+	if b.overflow {
+		redacted = []byte(omittedLine)
+		if newline {
+			redacted = append(redacted, '\n')
+		}
+	} else if b.line.Len() > 0 {
+		redacted = b.redactLine(b.line.Bytes())
+	}
+
+	b.full = appendBounded(&b.out, redacted)
+	b.line.Reset()
+	b.overflow = false
+}
+
+func (b *captureBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *captureBody) finish() {
+	b.once.Do(func() {
+		if !b.full && (b.line.Len() > 0 || b.overflow) {
+			b.flushLine(false)
+		}
+		b.done(b.out.Bytes())
+	})
+}
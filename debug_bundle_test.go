@@ -0,0 +1,86 @@
+package fauna_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugBundle(t *testing.T) {
+	t.Run("is empty without traffic capture", func(t *testing.T) {
+		client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(fauna.EndpointLocal))
+		bundle := client.DebugBundle()
+		require.Equal(t, fauna.EndpointLocal, bundle.Endpoint)
+		require.Empty(t, bundle.Exchanges)
+	})
+
+	t.Run("keeps the most recent redacted exchanges", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"data":{"secret":"fnAAA","email":"x@y.z"},"txn_ts":1,"stats":{}}`))
+		}, fauna.WithTrafficCapture(2, "email"))
+
+		for i := 0; i < 3; i++ {
+			q, _ := fauna.FQL(`Key.create(${n}) { secret }`, map[string]any{"n": i})
+			_, err := client.Query(q)
+			require.NoError(t, err)
+		}
+
+		bundle := client.DebugBundle()
+		require.Len(t, bundle.Exchanges, 2)
+
+		for i, exchange := range bundle.Exchanges {
+			require.Equal(t, http.MethodPost, exchange.Method)
+			require.Equal(t, http.StatusOK, exchange.StatusCode)
+			require.Equal(t, "hidden", exchange.RequestHeaders.Get("Authorization"))
+			require.Contains(t, exchange.RequestBody, fmt.Sprintf(`{"@int":"%d"}`, i+1))
+			require.JSONEq(t, `{"data":{"secret":"hidden","email":"hidden"},"txn_ts":1,"stats":{}}`, exchange.ResponseBody)
+		}
+	})
+
+	t.Run("redacts stream lines and omits what it can't parse", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"type":"add","data":{"email":"x@y.z"}}` + "\n" +
+				`{"type":"add","data":{"email":"a@b.c"` + "\n" +
+				`{"type":"add","data":{"email":"` + strings.Repeat("x", 70*1024) + `"}}` + "\n"))
+		}, fauna.WithTrafficCapture(1, "email"))
+
+		events, err := client.Stream("token")
+		require.NoError(t, err)
+
+		var event fauna.Event
+		require.NoError(t, events.Next(&event))
+		_ = events.Next(&event)
+		require.NoError(t, events.Close())
+
+		body := client.DebugBundle().Exchanges[0].ResponseBody
+		require.NotContains(t, body, "@")
+		require.NotContains(t, body, "xxx")
+
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		require.Len(t, lines, 3)
+		require.JSONEq(t, `{"type":"add","data":{"email":"hidden"}}`, lines[0])
+		require.Contains(t, lines[1], "omitted")
+		require.Contains(t, lines[2], "omitted")
+	})
+
+	t.Run("bounds and redacts large request bodies", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+		}, fauna.WithTrafficCapture(1))
+
+		q, _ := fauna.FQL(`Key.create(${params})`, map[string]any{"params": map[string]any{
+			"secret": "fnSECRET",
+			"note":   strings.Repeat("x", 100*1024),
+		}})
+		_, err := client.Query(q)
+		require.NoError(t, err)
+
+		body := client.DebugBundle().Exchanges[0].RequestBody
+		require.LessOrEqual(t, len(body), 64*1024)
+		require.NotContains(t, body, "fnSECRET")
+	})
+}
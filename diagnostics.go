@@ -0,0 +1,111 @@
+package fauna
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity is the kind of a [Diagnostic].
+type Severity string
+
+const (
+	// SeverityError is a query error.
+	SeverityError Severity = "error"
+	// SeverityWarning is a problem that didn't stop the query, such as the use
+	// of a deprecated function.
+	SeverityWarning Severity = "warning"
+	// SeverityHint is a suggestion attached to another diagnostic.
+	SeverityHint Severity = "hint"
+	// SeverityPerformanceHint is a suggestion for making the query cheaper.
+	SeverityPerformanceHint Severity = "performance_hint"
+	// SeverityInfo is a message logged by the query.
+	SeverityInfo Severity = "info"
+)
+
+// Location is where in a query a [Diagnostic] was reported.
+type Location struct {
+	// Source is the name of the source, such as "*query*" or the name of a
+	// user-defined function.
+	Source string
+	Line   int
+	// Column is the 1-based column, or zero if unknown.
+	Column int
+}
+
+// Diagnostic is a single entry of a query's summary.
+type Diagnostic struct {
+	Severity Severity
+	// Code identifies the kind of diagnostic, such as "full_set_read", if
+	// Fauna reported one.
+	Code    string
+	Message string
+	// Location is nil if Fauna didn't report where the diagnostic applies.
+	Location *Location
+}
+
+var (
+	// e.g. "performance_hint: full_set_read - Using sort() causes ..." or
+	// "error[invalid_query]: ..."
+	diagnosticHeader = regexp.MustCompile(`^(error|warning|hint|performance_hint)(?:\[([\w-]+)\])?: (.*)$`)
+	// e.g. "at *query*:1:23"
+	diagnosticLocation = regexp.MustCompile(`^at (\S+?):(\d+)(?::(\d+))?$`)
+	// e.g. "info at *query*:1: hello"
+	diagnosticLog = regexp.MustCompile(`^info at (\S+?):(\d+): (.*)$`)
+	// e.g. "full_set_read - Using sort() causes ..."
+	diagnosticCode = regexp.MustCompile(`^([a-z][a-z0-9_]*) - (.*)$`)
+)
+
+// Diagnostics parses the errors, warnings, hints and logs in the query's
+// [fauna.QueryInfo.Summary]. Lines that aren't recognized are ignored.
+func (q *QueryInfo) Diagnostics() []Diagnostic {
+	if q == nil {
+		return nil
+	}
+	return parseDiagnostics(q.Summary)
+}
+
+func parseDiagnostics(summary string) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	current := -1
+
+	for _, line := range strings.Split(summary, "\n") {
+		line = strings.TrimRight(line, " \r")
+
+		if m := diagnosticHeader.FindStringSubmatch(line); m != nil {
+			d := Diagnostic{Severity: Severity(m[1]), Code: m[2], Message: m[3]}
+			if d.Code == "" && d.Severity == SeverityPerformanceHint {
+				if c := diagnosticCode.FindStringSubmatch(d.Message); c != nil {
+					d.Code, d.Message = c[1], c[2]
+				}
+			}
+
+			diagnostics = append(diagnostics, d)
+			current = len(diagnostics) - 1
+			continue
+		}
+
+		if m := diagnosticLog.FindStringSubmatch(line); m != nil {
+			lineNo, _ := strconv.Atoi(m[2])
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityInfo,
+				Message:  m[3],
+				Location: &Location{Source: m[1], Line: lineNo},
+			})
+			current = -1
+			continue
+		}
+
+		if current < 0 {
+			continue
+		}
+
+		if m := diagnosticLocation.FindStringSubmatch(line); m != nil && diagnostics[current].Location == nil {
+			lineNo, _ := strconv.Atoi(m[2])
+			col, _ := strconv.Atoi(m[3])
+			diagnostics[current].Location = &Location{Source: m[1], Line: lineNo, Column: col}
+		}
+	}
+
+	return diagnostics
+}
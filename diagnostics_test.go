@@ -0,0 +1,52 @@
+package fauna_test
+
+import (
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	info := &fauna.QueryInfo{Summary: `info at *query*:1: hello world
+
+performance_hint: full_set_read - Using sort() causes the full set to be read. See https://docs.faunadb.org/performance_hint/full_set_read.
+at *query*:1:23
+  |
+1 | Product.all().order(.price)
+  |                       ^^^^^^
+  |
+
+warning: Function ` + "`all`" + ` is deprecated
+at *query*:2:9
+  |
+2 | Product.all()
+  |         ^^^
+  |
+hint: Use ` + "`where`" + ` instead`}
+
+	require.Equal(t, []fauna.Diagnostic{
+		{
+			Severity: fauna.SeverityInfo,
+			Message:  "hello world",
+			Location: &fauna.Location{Source: "*query*", Line: 1},
+		},
+		{
+			Severity: fauna.SeverityPerformanceHint,
+			Code:     "full_set_read",
+			Message:  "Using sort() causes the full set to be read. See https://docs.faunadb.org/performance_hint/full_set_read.",
+			Location: &fauna.Location{Source: "*query*", Line: 1, Column: 23},
+		},
+		{
+			Severity: fauna.SeverityWarning,
+			Message:  "Function `all` is deprecated",
+			Location: &fauna.Location{Source: "*query*", Line: 2, Column: 9},
+		},
+		{
+			Severity: fauna.SeverityHint,
+			Message:  "Use `where` instead",
+		},
+	}, info.Diagnostics())
+
+	require.Empty(t, (&fauna.QueryInfo{}).Diagnostics())
+}
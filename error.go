@@ -1,7 +1,16 @@
 package fauna
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
 )
 
 const httpStatusQueryTimeout = 440
@@ -39,6 +48,37 @@ func (e *ErrAbort) Unmarshal(into any) error {
 	return decodeInto(e.Abort, into)
 }
 
+// AbortAs decodes the data passed to abort() into a T, if err is or wraps an
+// [fauna.ErrAbort], or an [fauna.ErrEvent] with the "abort" code. It returns
+// false if err isn't an abort or its data doesn't decode into a T.
+//
+//	if reason, ok := fauna.AbortAs[OutOfStock](err); ok {
+//		// handle reason
+//	}
+func AbortAs[T any](err error) (T, bool) {
+	var (
+		value T
+		data  any
+	)
+
+	var abortErr *ErrAbort
+	var eventErr *ErrEvent
+	switch {
+	case errors.As(err, &abortErr) && abortErr.ErrFauna != nil:
+		data = abortErr.Abort
+	case errors.As(err, &eventErr) && eventErr.Code == "abort":
+		data = eventErr.Abort
+	default:
+		return value, false
+	}
+
+	if decodeErr := decodeInto(data, &value); decodeErr != nil {
+		var zero T
+		return zero, false
+	}
+	return value, true
+}
+
 // An ErrAuthentication is returned when Fauna is unable to authenticate
 // the request due to an invalid or missing authentication token.
 type ErrAuthentication struct {
@@ -51,6 +91,23 @@ type ErrAuthorization struct {
 	*ErrFauna
 }
 
+// An ErrCircuitOpen is returned without sending a request while the circuit
+// breaker set with [fauna.WithCircuitBreaker] is open, as requests to Fauna
+// kept failing.
+type ErrCircuitOpen struct {
+	// RetryAfter is how long until requests are let through again, or zero
+	// if the circuit is half-open and its probes are already in flight.
+	RetryAfter time.Duration
+}
+
+// Error provides the underlying error message.
+func (e *ErrCircuitOpen) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("circuit breaker is open, retry after %s", e.RetryAfter.Round(time.Millisecond))
+	}
+	return "circuit breaker is open, waiting for probes"
+}
+
 // ErrContendedTransaction is returned when a transaction is aborted due
 // to concurrent modification.
 type ErrContendedTransaction struct {
@@ -63,9 +120,108 @@ type ErrInvalidRequest struct {
 	*ErrFauna
 }
 
-// An ErrNetwork is returned when an unknown error is encountered when attempting
-// to send a request to Fauna.
-type ErrNetwork error
+// An ErrNetwork is returned when a request to Fauna fails without a response,
+// such as when the endpoint can't be resolved or reached, or the connection
+// is reset or times out. The underlying error, such as a [*net.DNSError] or
+// [syscall.ECONNRESET], can be inspected with [errors.As] and [errors.Is].
+type ErrNetwork struct {
+	// Op is the HTTP method of the failed request, such as "Post".
+	Op string
+	// URL is the URL of the failed request.
+	URL string
+	// Err is the underlying error.
+	Err error
+}
+
+func newErrNetwork(err error) *ErrNetwork {
+	netErr := &ErrNetwork{Err: err}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		netErr.Op, netErr.URL = urlErr.Op, urlErr.URL
+	}
+	return netErr
+}
+
+// Error provides the underlying error message.
+func (e *ErrNetwork) Error() string {
+	return "network error: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ErrNetwork) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports whether the request timed out.
+func (e *ErrNetwork) Timeout() bool {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+	return errors.As(e.Err, &timeout) && timeout.Timeout()
+}
+
+// Temporary reports whether the request may succeed if retried, as when it
+// timed out, the connection was refused or reset, or name resolution failed
+// temporarily. Requests canceled with their context aren't temporary.
+func (e *ErrNetwork) Temporary() bool {
+	if errors.Is(e.Err, context.Canceled) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(e.Err, &dnsErr) {
+		return dnsErr.Temporary() || dnsErr.Timeout()
+	}
+
+	return e.Timeout() ||
+		errors.Is(e.Err, syscall.ECONNREFUSED) ||
+		errors.Is(e.Err, syscall.ECONNRESET) ||
+		errors.Is(e.Err, syscall.ECONNABORTED) ||
+		errors.Is(e.Err, syscall.EPIPE) ||
+		errors.Is(e.Err, io.EOF) ||
+		errors.Is(e.Err, io.ErrUnexpectedEOF)
+}
+
+// sent reports whether the request may have reached Fauna, unlike failures
+// to resolve or dial the endpoint.
+func (e *ErrNetwork) sent() bool {
+	var dnsErr *net.DNSError
+	if errors.As(e.Err, &dnsErr) {
+		return false
+	}
+
+	var opErr *net.OpError
+	return !errors.As(e.Err, &opErr) || opErr.Op != "dial"
+}
+
+// retryable reports whether the request can be sent again: if it is
+// temporary, and either idempotent or never sent.
+func (e *ErrNetwork) retryable(idempotent bool) bool {
+	return e.Temporary() && (idempotent || !e.sent())
+}
+
+// An ErrNotEventSource is returned when a query expected to produce a
+// [fauna.EventSource] returns some other value.
+type ErrNotEventSource struct {
+	// Value is the decoded value the query returned.
+	Value any
+
+	// StaticType is the query's inferred static result type, if the query was
+	// typechecked.
+	StaticType string
+}
+
+// Error provides the underlying error message.
+func (e *ErrNotEventSource) Error() string {
+	if e.StaticType != "" {
+		return fmt.Sprintf("query should return a fauna.EventSource but got %T (static type: %s)", e.Value, e.StaticType)
+	}
+
+	return fmt.Sprintf("query should return a fauna.EventSource but got %T", e.Value)
+}
 
 // An ErrQueryCheck is returned when the query fails one or more validation checks.
 type ErrQueryCheck struct {
@@ -87,6 +243,21 @@ type ErrQueryTimeout struct {
 	*ErrFauna
 }
 
+// An ErrRequestQueueTimeout is returned without sending a request that waited
+// longer than the timeout of [fauna.WithRequestQueueTimeout] for a slot, as
+// the limit of [fauna.WithMaxConcurrentRequests] was reached.
+type ErrRequestQueueTimeout struct {
+	// Limit is the maximum number of concurrent requests.
+	Limit int
+	// Timeout is how long the request waited.
+	Timeout time.Duration
+}
+
+// Error provides the underlying error message.
+func (e *ErrRequestQueueTimeout) Error() string {
+	return fmt.Sprintf("request waited %s for one of %d request slots", e.Timeout, e.Limit)
+}
+
 // An ErrServiceInternal is returned when an unexpected error occurs.
 type ErrServiceInternal struct {
 	*ErrFauna
@@ -97,7 +268,44 @@ type ErrServiceTimeout struct {
 	*ErrFauna
 }
 
+// An ErrTemplate is returned when an FQL template, such as one passed to
+// [fauna.FQL], can't be parsed.
+type ErrTemplate struct {
+	// Message describes the problem.
+	Message string
+	// Position is where the problem is in the template.
+	Position TemplatePosition
+	// Line is the template's line at Position, without its line break.
+	Line string
+}
+
+// TemplatePosition is a location in an FQL template.
+type TemplatePosition struct {
+	// Offset is the byte offset, starting at 0.
+	Offset int
+	// Line is the line number, starting at 1.
+	Line int
+	// Column is the character number in the line, starting at 1.
+	Column int
+}
+
+// Error provides the message and position, followed by the offending line
+// with a caret under the position.
+func (e *ErrTemplate) Error() string {
+	// keep tabs so that the caret lines up
+	indent := strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, string([]rune(e.Line)[:e.Position.Column-1]))
+
+	return fmt.Sprintf("%s at line %d, column %d:\n%s\n%s^", e.Message, e.Position.Line, e.Position.Column, e.Line, indent)
+}
+
 // An ErrThrottling is returned when the query exceeded some capacity limit.
+// Its [fauna.QueryInfo.RateLimit] holds the limits that were hit and how long
+// to back off, when Fauna provided them.
 type ErrThrottling struct {
 	*ErrFauna
 }
@@ -160,6 +368,13 @@ func getErrFauna(httpStatus int, res *queryResponse, attempts int) error {
 	case http.StatusGone:
 		return &ErrAuthorization{res.Error}
 	case http.StatusTooManyRequests:
+		if res.Error == nil {
+			return &ErrThrottling{&ErrFauna{
+				QueryInfo:  newQueryInfo(res),
+				Message:    http.StatusText(httpStatus),
+				StatusCode: httpStatus,
+			}}
+		}
 		return &ErrThrottling{res.Error}
 	case httpStatusQueryTimeout:
 		return &ErrQueryTimeout{res.Error}
@@ -1,6 +1,7 @@
 package fauna
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 	"time"
@@ -227,3 +228,30 @@ func TestErrConstraint(t *testing.T) {
 		}
 	})
 }
+
+func TestAbortAs(t *testing.T) {
+	type outOfStock struct {
+		Code string `fauna:"code"`
+		Qty  int    `fauna:"qty"`
+	}
+
+	abort := &ErrAbort{&ErrFauna{Code: "abort", Abort: map[string]any{"code": "out_of_stock", "qty": int64(3)}}}
+	reason, ok := AbortAs[outOfStock](fmt.Errorf("checkout: %w", abort))
+	assert.True(t, ok)
+	assert.Equal(t, outOfStock{Code: "out_of_stock", Qty: 3}, reason)
+
+	_, ok = AbortAs[int](abort)
+	assert.False(t, ok)
+
+	message, ok := AbortAs[string](&ErrEvent{Code: "abort", Message: "Query aborted.", Abort: "sold out"})
+	assert.True(t, ok)
+	assert.Equal(t, "sold out", message)
+
+	_, ok = AbortAs[string](&ErrEvent{Code: "invalid_stream_start_time"})
+	assert.False(t, ok)
+
+	_, ok = AbortAs[string](&ErrQueryRuntime{&ErrFauna{Code: "invalid_argument"}})
+	assert.False(t, ok)
+	_, ok = AbortAs[string](nil)
+	assert.False(t, ok)
+}
@@ -1,12 +1,19 @@
 package fauna
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // EventFeed represents an event feed subscription.
 type EventFeed struct {
 	client *Client
+	ctx    context.Context
 
 	source EventSource
 
@@ -14,29 +21,63 @@ type EventFeed struct {
 
 	opts       *feedOptions
 	lastCursor string
+
+	pollInterval time.Duration
+
+	// projection selects the fields of event data, if set with
+	// EventFeedFields.
+	projection *fieldTree
+
+	// values decodes event data, with the client's or the feed's own
+	// DecodeOptions.
+	values decoder
+
+	// decodeWorkers is the number of goroutines decoding the events of a
+	// page, set with EventFeedDecodeWorkers.
+	decodeWorkers int
 }
 
 type feedOptions struct {
 	PageSize *int
 	Cursor   *string
 	StartTS  *int64
+
+	decoder      *decoder
+	consistency  *Consistency
+	pollInterval time.Duration
+	fields       []string
+	projection   *fieldTree
+	workers      int
 }
 
-func newEventFeed(client *Client, source EventSource, opts *feedOptions) (*EventFeed, error) {
+func newEventFeed(ctx context.Context, client *Client, source EventSource, opts *feedOptions) (*EventFeed, error) {
 	feed := &EventFeed{
 		client: client,
+		ctx:    ctx,
 		source: source,
 		opts:   opts,
+		values: client.decoder,
+
+		pollInterval:  DefaultFeedPollInterval,
+		projection:    opts.projection,
+		decodeWorkers: opts.workers,
+	}
+	if opts.pollInterval > 0 {
+		feed.pollInterval = opts.pollInterval
+	}
+	if opts.decoder != nil {
+		feed.values = *opts.decoder
 	}
 
 	return feed, nil
 }
 
-func (ef *EventFeed) newFeedRequest() (*feedRequest, error) {
+func (ef *EventFeed) newFeedRequest(ctx context.Context) (*feedRequest, error) {
 	req := feedRequest{
 		apiRequest: apiRequest{
-			ef.client.ctx,
-			ef.client.headers,
+			Context:    ctx,
+			Headers:    ef.client.headers,
+			idempotent: true,
 		},
 		Source: ef.source,
 		Cursor: ef.lastCursor,
@@ -54,20 +95,20 @@ func (ef *EventFeed) newFeedRequest() (*feedRequest, error) {
 	return &req, nil
 }
 
-func (ef *EventFeed) open() error {
-	req, err := ef.newFeedRequest()
+func (ef *EventFeed) open(ctx context.Context) (io.Closer, error) {
+	req, err := ef.newFeedRequest(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	byteStream, err := req.do(ef.client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	ef.decoder = json.NewDecoder(byteStream)
+	ef.decoder = ef.values.jsonDecoder(byteStream)
 
-	return nil
+	return byteStream, nil
 }
 
 // FeedPage represents the response from [fauna.EventFeed.Next]
@@ -78,18 +119,200 @@ type FeedPage struct {
 	Stats   Stats   `json:"stats"`
 }
 
-// Next retrieves the next FeedPage from the [fauna.EventFeed]
+// Next retrieves the next FeedPage from the [fauna.EventFeed]. Error events
+// are returned in the page with their [fauna.Event.Error] set.
 func (ef *EventFeed) Next(page *FeedPage) error {
-	if err := ef.open(); err != nil {
+	return ef.next(ef.ctx, page)
+}
+
+func (ef *EventFeed) next(ctx context.Context, page *FeedPage) error {
+	body, err := ef.open(ctx)
+	if err != nil {
 		return err
 	}
+	defer func() { _ = body.Close() }()
 
-	if err := ef.decoder.Decode(&page); err != nil {
-		return err
+	var meta rawFeedPage
+	if ef.decodeWorkers > 1 {
+		// events are kept raw, to be decoded by the workers
+		var raw struct {
+			rawFeedPage
+			Events []json.RawMessage `json:"events"`
+		}
+		if err := ef.decoder.Decode(&raw); err != nil {
+			return err
+		}
+		if page.Events, err = ef.decodeEvents(raw.Events); err != nil {
+			return err
+		}
+		meta = raw.rawFeedPage
+	} else {
+		var raw struct {
+			rawFeedPage
+			Events []rawEvent `json:"events"`
+		}
+		if err := ef.decoder.Decode(&raw); err != nil {
+			return err
+		}
+		page.Events = make([]Event, len(raw.Events))
+		for i := range raw.Events {
+			if err := ef.convertEvent(&raw.Events[i], &page.Events[i]); err != nil {
+				return err
+			}
+		}
+		meta = raw.rawFeedPage
 	}
+	page.Cursor = meta.Cursor
+	page.HasNext = meta.HasNext
+	page.Stats = meta.Stats
 
 	ef.lastCursor = page.Cursor
 	ef.opts = &feedOptions{}
 
 	return nil
 }
+
+// rawFeedPage holds the fields of a feed page other than its events.
+type rawFeedPage struct {
+	Cursor  string `json:"cursor"`
+	HasNext bool   `json:"has_next"`
+	Stats   Stats  `json:"stats"`
+}
+
+// convertEvent converts raw into event, and projects its data if the feed
+// has fields set.
+func (ef *EventFeed) convertEvent(raw *rawEvent, event *Event) error {
+	if err := ef.values.convertFeedEvent(raw, event); err != nil {
+		return err
+	}
+	if ef.projection != nil && event.Data != nil {
+		event.Data = ef.projection.apply(event.Data)
+	}
+	return nil
+}
+
+// minParallelEvents is the number of events below which a page's events are
+// decoded sequentially, as it is faster than starting workers for them.
+const minParallelEvents = 32
+
+// decodeEvents decodes the raw events of a page with up to decodeWorkers
+// goroutines, keeping their order. If several events fail to decode, the
+// error of the first one is returned.
+func (ef *EventFeed) decodeEvents(raws []json.RawMessage) ([]Event, error) {
+	events := make([]Event, len(raws))
+	errs := make([]error, len(raws))
+	decode := func(i int) {
+		var raw rawEvent
+		if errs[i] = ef.values.jsonDecoder(bytes.NewReader(raws[i])).Decode(&raw); errs[i] == nil {
+			errs[i] = ef.convertEvent(&raw, &events[i])
+		}
+	}
+
+	workers := ef.decodeWorkers
+	if len(raws) < minParallelEvents {
+		workers = 1
+	} else if workers > len(raws) {
+		workers = len(raws)
+	}
+
+	var (
+		next int64
+		wg   sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := int(atomic.AddInt64(&next, 1) - 1); i < len(raws); i = int(atomic.AddInt64(&next, 1) - 1) {
+				decode(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// DefaultFeedPollInterval is how long [fauna.EventFeed.Subscribe] waits for
+// new events once it has read all those available, unless set with
+// [EventFeedPollInterval].
+const DefaultFeedPollInterval = time.Second
+
+// Subscribe reads the feed's pages in a goroutine and sends their events on
+// the returned channel, until ctx is done or a page can't be read. When the
+// feed has no more events, it waits for the poll interval before asking for
+// new ones. Error events are sent like other events, with their
+// [fauna.Event.Error] set. An error reading a page is sent on the error
+// channel. Both channels are closed when Subscribe stops, without an error if
+// ctx is done.
+//
+// Don't call Next on the feed while it is subscribed.
+func (ef *EventFeed) Subscribe(ctx context.Context) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+
+		for {
+			var page FeedPage
+			if err := ef.next(ctx, &page); err != nil {
+				if ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+
+			for _, event := range page.Events {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if page.HasNext {
+				continue
+			}
+
+			timer := time.NewTimer(ef.pollInterval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// convertFeedEvent converts raw like convertRawEvent, but keeps error events
+// in the page rather than failing it.
+func (d decoder) convertFeedEvent(raw *rawEvent, event *Event) error {
+	errEvent := raw.Error
+	raw.Error = nil
+
+	if err := d.convertRawEvent(raw, event); err != nil {
+		return err
+	}
+
+	if errEvent != nil {
+		if errEvent.Abort != nil {
+			abort, err := d.convert(errEvent.Abort)
+			if err != nil {
+				return err
+			}
+			errEvent.Abort = abort
+		}
+		event.Error = errEvent
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+//go:build go1.23
+
+package fauna
+
+import (
+	"context"
+	"iter"
+)
+
+// Pages returns an iterator over the feed's pages, fetched with ctx, that
+// follows the feed's cursor from page to page and stops after the last page
+// with events available, whose [fauna.FeedPage.HasNext] is false. An error
+// reading a page is yielded with an empty page, and stops the iteration.
+//
+//	for page, err := range feed.Pages(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+//
+// Use [fauna.EventFeed.Subscribe] to keep polling for new events instead.
+func (ef *EventFeed) Pages(ctx context.Context) iter.Seq2[FeedPage, error] {
+	return func(yield func(FeedPage, error) bool) {
+		for {
+			var page FeedPage
+			if err := ef.next(ctx, &page); err != nil {
+				yield(FeedPage{}, err)
+				return
+			}
+
+			if !yield(page, nil) || !page.HasNext {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the events of the feed's pages, as read by
+// [fauna.EventFeed.Pages]. Error events are yielded along with their
+// [fauna.Event.Error], and the iteration goes on unless the loop breaks.
+func (ef *EventFeed) All(ctx context.Context) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		for page, err := range ef.Pages(ctx) {
+			if err != nil {
+				yield(Event{}, err)
+				return
+			}
+
+			for _, event := range page.Events {
+				var err error
+				if event.Error != nil {
+					err = event.Error
+				}
+				if !yield(event, err) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+//go:build go1.23
+
+package fauna_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFeedIterators(t *testing.T) {
+	pages := []string{
+		`{"events":[{"type":"add","txn_ts":1,"cursor":"a","data":{"@int":"1"}},{"type":"error","txn_ts":2,"cursor":"b","error":{"code":"abort","message":"oops"}}],"cursor":"b","has_next":true,"stats":{}}`,
+		`{"events":[{"type":"update","txn_ts":3,"cursor":"c","data":{"@int":"3"}}],"cursor":"c","has_next":false,"stats":{}}`,
+	}
+
+	newFeed := func(t *testing.T) (*fauna.EventFeed, *[]string) {
+		var cursors []string
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			var req map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			cursor, _ := req["cursor"].(string)
+			cursors = append(cursors, cursor)
+
+			if len(cursors) > len(pages) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error":{"code":"internal_error","message":"no more pages"},"stats":{}}`))
+				return
+			}
+			_, _ = w.Write([]byte(pages[len(cursors)-1]))
+		})
+
+		feed, err := client.Feed("token")
+		require.NoError(t, err)
+		return feed, &cursors
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		feed, cursors := newFeed(t)
+
+		var got []string
+		for page, err := range feed.Pages(context.Background()) {
+			require.NoError(t, err)
+			got = append(got, page.Cursor)
+		}
+		require.Equal(t, []string{"b", "c"}, got)
+		require.Equal(t, []string{"", "b"}, *cursors)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		feed, _ := newFeed(t)
+
+		var data []any
+		var errs []error
+		for event, err := range feed.All(context.Background()) {
+			data = append(data, event.Data)
+			errs = append(errs, err)
+		}
+		require.Equal(t, []any{int64(1), nil, int64(3)}, data)
+		require.NoError(t, errs[0])
+		require.EqualError(t, errs[1], "oops")
+		require.NoError(t, errs[2])
+	})
+
+	t.Run("break", func(t *testing.T) {
+		feed, cursors := newFeed(t)
+
+		for range feed.All(context.Background()) {
+			break
+		}
+		require.Len(t, *cursors, 1)
+	})
+
+	t.Run("page error", func(t *testing.T) {
+		feed, _ := newFeed(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var errs []error
+		for _, err := range feed.All(ctx) {
+			errs = append(errs, err)
+		}
+		require.Len(t, errs, 1)
+		require.True(t, errors.Is(errs[0], context.Canceled))
+	})
+}
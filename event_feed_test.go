@@ -1,6 +1,12 @@
 package fauna_test
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -236,3 +242,221 @@ func createMultipleDocs(t *testing.T, client *fauna.Client, start int, end int)
 	_, err := client.Query(query)
 	require.NoError(t, err)
 }
+
+func TestEventFeedErrorEvents(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"events":[
+{"type":"add","txn_ts":1,"cursor":"a","data":{"@int":"1"}},
+{"type":"error","txn_ts":2,"cursor":"b","error":{"code":"abort","message":"oops","abort":{"@int":"2"}}},
+{"type":"add","txn_ts":3,"cursor":"c","data":{"@int":"3"}}
+],"cursor":"c","has_next":false,"stats":{}}`))
+	})
+
+	feed, err := client.Feed("token")
+	require.NoError(t, err)
+
+	var page fauna.FeedPage
+	require.NoError(t, feed.Next(&page))
+	require.Len(t, page.Events, 3)
+
+	require.Nil(t, page.Events[0].Error)
+	require.Equal(t, int64(1), page.Events[0].Data)
+
+	errEvent := page.Events[1].Error
+	require.NotNil(t, errEvent)
+	require.Equal(t, "abort", errEvent.Code)
+	require.Equal(t, int64(2), errEvent.Abort)
+	require.Equal(t, "b", page.Events[1].Cursor)
+
+	require.Equal(t, int64(3), page.Events[2].Data)
+	require.Equal(t, "c", page.Cursor)
+}
+
+func TestEventFeedDecodeWorkers(t *testing.T) {
+	events := make([]string, 100)
+	for i := range events {
+		events[i] = `{"type":"add","txn_ts":` + strconv.Itoa(i) + `,"cursor":"` + strconv.Itoa(i) + `","data":{"n":{"@int":"` + strconv.Itoa(i) + `"}}}`
+	}
+	events[50] = `{"type":"error","txn_ts":50,"cursor":"50","error":{"code":"abort","message":"oops","abort":{"@int":"50"}}}`
+	body := `{"events":[` + strings.Join(events, ",") + `],"cursor":"99","has_next":true,"stats":{"read_ops":1}}`
+
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	})
+
+	feed, err := client.Feed("token")
+	require.NoError(t, err)
+	var want fauna.FeedPage
+	require.NoError(t, feed.Next(&want))
+
+	feed, err = client.Feed("token", fauna.EventFeedDecodeWorkers(4))
+	require.NoError(t, err)
+	var page fauna.FeedPage
+	require.NoError(t, feed.Next(&page))
+
+	require.Equal(t, want, page)
+	require.Len(t, page.Events, 100)
+	for i, event := range page.Events {
+		require.Equal(t, strconv.Itoa(i), event.Cursor)
+	}
+	require.Equal(t, int64(50), page.Events[50].Error.Abort)
+	require.Equal(t, "99", page.Cursor)
+	require.True(t, page.HasNext)
+	require.Equal(t, 1, page.Stats.ReadOps)
+
+	t.Run("fails with the first event that can't be decoded", func(t *testing.T) {
+		bad := append([]string{}, events...)
+		bad[10] = `{"type":"add","txn_ts":10,"cursor":"10","data":{"@int":"ten"}}`
+		bad[90] = `{"type":"add","txn_ts":90,"cursor":"90","data":{"@time":"ninety"}}`
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"events":[` + strings.Join(bad, ",") + `],"cursor":"99","has_next":false,"stats":{}}`))
+		})
+
+		feed, err := client.Feed("token", fauna.EventFeedDecodeWorkers(4))
+		require.NoError(t, err)
+		var page fauna.FeedPage
+		require.ErrorContains(t, feed.Next(&page), `"ten"`)
+	})
+}
+
+func TestEventFeedSubscribe(t *testing.T) {
+	t.Run("delivers events across pages and polls when drained", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			cursors []string
+		)
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Cursor string `json:"cursor"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+
+			mu.Lock()
+			cursors = append(cursors, req.Cursor)
+			mu.Unlock()
+
+			switch req.Cursor {
+			case "":
+				_, _ = w.Write([]byte(`{"events":[{"type":"add","txn_ts":1,"cursor":"a","data":{"@int":"1"}}],"cursor":"a","has_next":true,"stats":{}}`))
+			case "a":
+				_, _ = w.Write([]byte(`{"events":[],"cursor":"b","has_next":false,"stats":{}}`))
+			default:
+				_, _ = w.Write([]byte(`{"events":[{"type":"add","txn_ts":2,"cursor":"c","data":{"@int":"2"}}],"cursor":"c","has_next":false,"stats":{}}`))
+			}
+		})
+
+		feed, err := client.Feed("token", fauna.EventFeedPollInterval(10*time.Millisecond))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		events, errs := feed.Subscribe(ctx)
+
+		require.Equal(t, int64(1), (<-events).Data)
+		require.Equal(t, int64(2), (<-events).Data)
+		cancel()
+
+		for range events {
+		}
+		require.NoError(t, <-errs)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, []string{"", "a", "b"}, cursors[:3])
+	})
+
+	t.Run("sends errors reading pages", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":"unauthorized","message":"Access token required"},"stats":{}}`))
+		})
+
+		feed, err := client.Feed("token")
+		require.NoError(t, err)
+
+		events, errs := feed.Subscribe(context.Background())
+		var authErr *fauna.ErrAuthentication
+		require.ErrorAs(t, <-errs, &authErr)
+
+		_, open := <-events
+		require.False(t, open)
+	})
+}
+
+func TestClientHistory(t *testing.T) {
+	var (
+		query   string
+		feedReq []map[string]any
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/query/") {
+			var body struct {
+				Query json.RawMessage `json:"query"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			query = string(body.Query)
+			_, _ = w.Write([]byte(`{"data":{"@stream":"token"},"txn_ts":1,"stats":{}}`))
+			return
+		}
+
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		feedReq = append(feedReq, req)
+
+		if len(feedReq) == 1 {
+			_, _ = w.Write([]byte(`{"events":[{"type":"update","txn_ts":3,"cursor":"b","data":{"@int":"3"}},{"type":"add","txn_ts":2,"cursor":"a","data":{"@int":"2"}}],"cursor":"b","has_next":true,"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"events":[{"type":"remove","txn_ts":4,"cursor":"c","data":{"@int":"4"}}],"cursor":"c","has_next":false,"stats":{}}`))
+	})
+
+	since := time.UnixMicro(1).UTC()
+	events, err := client.History(context.Background(), *fauna.NewRef("Product", "42"), since)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"fql":["Set.single(",{"value":{"@mod":"Product"}},".byId(",{"value":"42"},")!).eventSource()"]}`, query)
+
+	require.Len(t, events, 3)
+	require.Equal(t, []any{int64(2), int64(3), int64(4)}, []any{events[0].Data, events[1].Data, events[2].Data})
+	require.Equal(t, fauna.RemoveEvent, events[2].Type)
+
+	require.Len(t, feedReq, 2)
+	require.Equal(t, float64(1), feedReq[0]["start_ts"])
+	require.Equal(t, "b", feedReq[1]["cursor"])
+
+	_, err = client.History(context.Background(), fauna.Ref{ID: "42"}, since)
+	require.ErrorContains(t, err, "no collection")
+}
+
+func TestEventFeedFields(t *testing.T) {
+	var query string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/query/") {
+			var body struct {
+				Query json.RawMessage `json:"query"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			query = string(body.Query)
+			_, _ = w.Write([]byte(`{"data":{"@stream":"token"},"stats":{}}`))
+			return
+		}
+
+		// Fauna projected the event source
+		_, _ = w.Write([]byte(`{"events":[
+{"type":"add","txn_ts":1,"cursor":"a","data":{"name":"limes","price":{"@int":"2"}}}
+],"cursor":"a","has_next":false,"stats":{}}`))
+	})
+
+	q, err := fauna.FQL(`Product.all().eventSource()`, nil)
+	require.NoError(t, err)
+
+	feed, err := client.FeedFromQuery(q, fauna.EventFeedFields("name", "price"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"fql":["let result = {\n",{"fql":["Product.all().eventSource()"]},"\n}\nresult { name, price }"]}`, query)
+
+	var page fauna.FeedPage
+	require.NoError(t, feed.Next(&page))
+	require.Len(t, page.Events, 1)
+	require.Equal(t, map[string]any{"name": "limes", "price": int64(2)}, page.Events[0].Data)
+
+	_, err = client.Feed("token", fauna.EventFeedFields("a..b"))
+	require.EqualError(t, err, `invalid field "a..b"`)
+}
@@ -0,0 +1,89 @@
+package fauna
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// experiments are the preview features known to [Experimental], by name. Each
+// applies its value to the client, or returns an error if it is invalid.
+var experiments = map[string]func(c *Client, value string) error{
+	// header sends a preview header, given as "Name: value", with every
+	// request, without requiring AllowUnknownHeaders. Only "X-" headers the
+	// driver doesn't already know are allowed, so that it can't override
+	// authentication or the headers set by typed options.
+	"header": func(c *Client, value string) error {
+		name, val, found := strings.Cut(value, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return fmt.Errorf(`want "Name: value", got %q`, value)
+		}
+
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if !strings.HasPrefix(name, headerPrefixCustom) || knownHeaders[name] {
+			return fmt.Errorf(`%q isn't a preview header, want an unknown "X-" header`, name)
+		}
+
+		c.headers[name] = strings.TrimSpace(val)
+		c.previewHeaders[name] = true
+		return nil
+	},
+
+	// query_version, stream_version and feed_version set the version of the
+	// respective API endpoint, such as "2" for /query/2.
+	"query_version":  apiVersionExperiment("query"),
+	"stream_version": apiVersionExperiment("stream"),
+	"feed_version":   apiVersionExperiment("feed"),
+}
+
+func apiVersionExperiment(api string) func(c *Client, value string) error {
+	return func(c *Client, value string) error {
+		if value == "" || strings.ContainsAny(value, "/?#") {
+			return fmt.Errorf("invalid %s API version %q", api, value)
+		}
+
+		c.apiVersions[api] = value
+		return nil
+	}
+}
+
+// Experiments returns the names of the preview features that can be enabled
+// with [Experimental].
+func Experiments() []string {
+	names := make([]string, 0, len(experiments))
+	for name := range experiments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Experimental enables a preview feature of Fauna, such as a new header or
+// API version, before the driver has a dedicated option for it. See
+// [Experiments] for the known names. Unknown names and invalid values are
+// reported when the client sends its first request.
+//
+// Experiments may change or be removed without notice.
+func Experimental(name string, value string) ClientConfigFn {
+	return func(c *Client) {
+		apply, ok := experiments[name]
+		if !ok {
+			c.setOptionErr(fmt.Errorf("unknown experiment %q, known experiments are %s", name, strings.Join(Experiments(), ", ")))
+			return
+		}
+
+		if err := apply(c, value); err != nil {
+			c.setOptionErr(fmt.Errorf("experiment %s: %w", name, err))
+		}
+	}
+}
+
+// apiVersion returns the version of api to use, "1" unless overridden by an
+// experiment.
+func (c *Client) apiVersion(api string) string {
+	if v, ok := c.apiVersions[api]; ok {
+		return v
+	}
+	return "1"
+}
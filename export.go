@@ -0,0 +1,334 @@
+package fauna
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+const exportPageSizeDefault = 1000
+
+// ExportFormat is the format [Client.ExportCollection] writes documents in.
+type ExportFormat int
+
+const (
+	// ExportJSONL writes a JSON object per document and line, also known as
+	// NDJSON, with [JSONLEncoder].
+	ExportJSONL ExportFormat = iota
+
+	// ExportCSV writes a CSV record per document with a [CSVEncoder], whose
+	// columns are the fields of the first document exported. Use
+	// [Client.Export] with a CSVEncoder to choose the columns instead, e.g.
+	// if the collection's documents don't all have the same fields.
+	ExportCSV
+)
+
+// Encoder writes the items exported by [Client.Export]. Implement it to
+// export in formats other than [JSONLEncoder] and [CSVEncoder], such as
+// protobuf.
+type Encoder interface {
+	// Encode writes a single item to w.
+	Encode(w io.Writer, item any) error
+
+	// Flush writes any buffered items to w. It is called after each page, before
+	// the page's cursor is checkpointed.
+	Flush(w io.Writer) error
+}
+
+// ExportProgress reports how far an export has got.
+type ExportProgress struct {
+	// Pages is the number of pages exported.
+	Pages int
+	// Items is the number of items exported.
+	Items int
+	// Cursor is the cursor of the next page, or empty once the export is done.
+	Cursor string
+}
+
+type exportOptions struct {
+	pageSize   int
+	cursor     string
+	checkpoint func(cursor string) error
+	progress   func(ExportProgress)
+	queryOpts  []QueryOptFn
+}
+
+// ExportOptFn function to set options on the [Client.Export]
+type ExportOptFn func(opts *exportOptions)
+
+// ExportCursor resumes an export from a cursor saved by [ExportCheckpoint].
+func ExportCursor(cursor string) ExportOptFn {
+	return func(opts *exportOptions) { opts.cursor = cursor }
+}
+
+// ExportPageSize sets how many documents [Client.ExportCollection] reads per
+// query. Defaults to 1000.
+func ExportPageSize(size int) ExportOptFn {
+	return func(opts *exportOptions) { opts.pageSize = size }
+}
+
+// ExportCheckpoint sets a function called with the cursor of the next page
+// after each page is written and flushed. The export stops if it returns an
+// error.
+func ExportCheckpoint(fn func(cursor string) error) ExportOptFn {
+	return func(opts *exportOptions) { opts.checkpoint = fn }
+}
+
+// ExportProgressFunc sets a function called after each page is exported.
+func ExportProgressFunc(fn func(ExportProgress)) ExportOptFn {
+	return func(opts *exportOptions) { opts.progress = fn }
+}
+
+// ExportQueryOptions sets the options used for each query made by the export.
+func ExportQueryOptions(opts ...QueryOptFn) ExportOptFn {
+	return func(o *exportOptions) { o.queryOpts = opts }
+}
+
+// Export runs fql, which should return a set, and writes every item in it to
+// w with enc, paginating through the set as it goes.
+func (c *Client) Export(ctx context.Context, fql *Query, enc Encoder, w io.Writer, opts ...ExportOptFn) error {
+	options := exportOptions{}
+	for _, optFn := range opts {
+		optFn(&options)
+	}
+
+	queryOpts := append([]QueryOptFn{QueryContext(ctx)}, options.queryOpts...)
+	iter := c.Paginate(fql, queryOpts...)
+	if options.cursor != "" {
+		if err := iter.nextPage(options.cursor); err != nil {
+			return err
+		}
+	}
+
+	progress := ExportProgress{}
+	for iter.HasNext() {
+		page, err := iter.Next()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Data {
+			if err := enc.Encode(w, item); err != nil {
+				return fmt.Errorf("failed to encode item %d: %w", progress.Items, err)
+			}
+			progress.Items++
+		}
+
+		if err := enc.Flush(w); err != nil {
+			return err
+		}
+
+		progress.Pages++
+		progress.Cursor = page.After
+
+		if options.checkpoint != nil {
+			if err := options.checkpoint(page.After); err != nil {
+				return err
+			}
+		}
+
+		if options.progress != nil {
+			options.progress(progress)
+		}
+	}
+
+	return nil
+}
+
+// ExportCollection writes every document of coll to w in the given format,
+// reading them in pages of [ExportPageSize] documents. It takes the options
+// of [Client.Export], so progress can be reported with [ExportProgressFunc],
+// and an export that failed can be resumed by passing the last cursor saved
+// with [ExportCheckpoint] to [ExportCursor], appending to the same output.
+func (c *Client) ExportCollection(ctx context.Context, coll Module, w io.Writer, format ExportFormat, opts ...ExportOptFn) error {
+	options := exportOptions{pageSize: exportPageSizeDefault}
+	for _, optFn := range opts {
+		optFn(&options)
+	}
+	if options.pageSize <= 0 {
+		return fmt.Errorf("export page size must be positive, got %d", options.pageSize)
+	}
+
+	var enc Encoder
+	switch format {
+	case ExportJSONL:
+		enc = JSONLEncoder{}
+	case ExportCSV:
+		// the header was written by the export being resumed
+		enc = &CSVEncoder{NoHeader: options.cursor != ""}
+	default:
+		return fmt.Errorf("unknown export format %d", format)
+	}
+
+	q, err := FQL(`${coll}.all().pageSize(${size})`, map[string]any{"coll": &coll, "size": options.pageSize})
+	if err != nil {
+		return err
+	}
+	return c.Export(ctx, q, enc, w, opts...)
+}
+
+// JSONLEncoder is an [Encoder] writing one JSON object per line. Fauna values
+// are written as plain JSON: documents are flattened into objects with their
+// id, coll and ts, modules are written as their name, and times as RFC 3339
+// strings.
+type JSONLEncoder struct{}
+
+// Encode writes item as a line of JSON.
+func (JSONLEncoder) Encode(w io.Writer, item any) error {
+	line, err := json.Marshal(exportValue(item))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
+
+// Flush does nothing, as [JSONLEncoder] doesn't buffer.
+func (JSONLEncoder) Flush(io.Writer) error { return nil }
+
+// CSVEncoder is an [Encoder] writing the given columns of each item as a CSV
+// record. Items are flattened as with [JSONLEncoder]; nested values are
+// written as JSON.
+type CSVEncoder struct {
+	// Columns are the fields written for each item, in order. If empty, they
+	// are the fields of the first item: its id or name, coll and ts, followed
+	// by the others in alphabetical order.
+	Columns []string
+	// NoHeader skips writing the columns as a header record, e.g. when
+	// resuming an export.
+	NoHeader bool
+
+	out    io.Writer
+	writer *csv.Writer
+}
+
+// NewCSVEncoder returns a [CSVEncoder] writing the given columns.
+func NewCSVEncoder(columns ...string) *CSVEncoder {
+	return &CSVEncoder{Columns: columns}
+}
+
+// Encode writes item as a CSV record, preceded by the header if this is the
+// first record written to w.
+func (e *CSVEncoder) Encode(w io.Writer, item any) error {
+	if e.out != w {
+		if len(e.Columns) == 0 {
+			fields, _ := exportValue(item).(map[string]any)
+			e.Columns = csvColumns(fields)
+		}
+
+		e.out, e.writer = w, csv.NewWriter(w)
+		if !e.NoHeader {
+			if err := e.writer.Write(e.Columns); err != nil {
+				return err
+			}
+		}
+	}
+
+	fields, _ := exportValue(item).(map[string]any)
+
+	record := make([]string, len(e.Columns))
+	for i, col := range e.Columns {
+		switch v := fields[col].(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case map[string]any, []any:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			record[i] = string(b)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+
+	return e.writer.Write(record)
+}
+
+// Flush writes buffered records to w.
+func (e *CSVEncoder) Flush(w io.Writer) error {
+	if e.writer == nil || e.out != w {
+		return nil
+	}
+
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// csvColumns returns the columns of a flattened document: its id or name, coll
+// and ts, then its other fields in alphabetical order.
+func csvColumns(fields map[string]any) []string {
+	key := "id"
+	if _, ok := fields[key]; !ok {
+		key = "name"
+	}
+
+	var columns, rest []string
+	for _, meta := range []string{key, "coll", "ts"} {
+		if _, ok := fields[meta]; ok {
+			columns = append(columns, meta)
+		}
+	}
+	for field := range fields {
+		if field != key && field != "coll" && field != "ts" {
+			rest = append(rest, field)
+		}
+	}
+	sort.Strings(rest)
+	return append(columns, rest...)
+}
+
+// exportValue converts decoded Fauna values into values encoding/json writes
+// as plain JSON.
+func exportValue(v any) any {
+	switch val := v.(type) {
+	case *Document:
+		return exportDoc(val.Data, "id", val.ID, val.Coll, val.TS)
+	case *NamedDocument:
+		return exportDoc(val.Data, "name", val.Name, val.Coll, val.TS)
+	case *Ref:
+		return map[string]any{"id": val.ID, "coll": exportValue(val.Coll)}
+	case *NamedRef:
+		return map[string]any{"name": val.Name, "coll": exportValue(val.Coll)}
+	case *Module:
+		if val == nil {
+			return nil
+		}
+		return val.Name
+	case *time.Time:
+		if val == nil {
+			return nil
+		}
+		return val.Format(time.RFC3339Nano)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, field := range val {
+			out[k] = exportValue(field)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = exportValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func exportDoc(data map[string]any, key string, id string, coll *Module, ts *time.Time) map[string]any {
+	out := exportValue(data).(map[string]any)
+	out[key] = id
+	out["coll"] = exportValue(coll)
+	out["ts"] = exportValue(ts)
+	return out
+}
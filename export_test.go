@@ -0,0 +1,118 @@
+package fauna_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "Set.paginate") {
+			_, _ = w.Write([]byte(`{"data":{"data":[{"@doc":{"id":"3","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10Z"},"name":"limes, key","tags":["a"]}}]},"txn_ts":1,"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"@set":{"data":[
+			{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10Z"},"name":"apples","price":{"@int":"2"}}},
+			{"@doc":{"id":"2","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10Z"},"name":"pears"}}
+		],"after":"next"}},"txn_ts":1,"stats":{}}`))
+	})
+
+	q, _ := fauna.FQL(`Product.all()`, nil)
+
+	t.Run("JSONL", func(t *testing.T) {
+		var (
+			out         bytes.Buffer
+			checkpoints []string
+			progress    []fauna.ExportProgress
+		)
+		err := client.Export(context.Background(), q, fauna.JSONLEncoder{}, &out,
+			fauna.ExportCheckpoint(func(cursor string) error {
+				checkpoints = append(checkpoints, cursor)
+				return nil
+			}),
+			fauna.ExportProgressFunc(func(p fauna.ExportProgress) { progress = append(progress, p) }),
+		)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+		require.JSONEq(t, `{"id":"1","coll":"Product","ts":"2023-02-28T18:10:10Z","name":"apples","price":2}`, lines[0])
+		require.JSONEq(t, `{"id":"3","coll":"Product","ts":"2023-02-28T18:10:10Z","name":"limes, key","tags":["a"]}`, lines[2])
+
+		require.Equal(t, []string{"next", ""}, checkpoints)
+		require.Equal(t, fauna.ExportProgress{Pages: 2, Items: 3}, progress[1])
+	})
+
+	t.Run("CSV resumed from a cursor", func(t *testing.T) {
+		var out bytes.Buffer
+		err := client.Export(context.Background(), q, fauna.NewCSVEncoder("id", "name", "tags"), &out, fauna.ExportCursor("next"))
+		require.NoError(t, err)
+		require.Equal(t, "id,name,tags\n3,\"limes, key\",\"[\"\"a\"\"]\"\n", out.String())
+	})
+}
+
+func TestExportCollection(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, string(body))
+		if strings.Contains(string(body), "Set.paginate") {
+			_, _ = w.Write([]byte(`{"data":{"data":[{"@doc":{"id":"3","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10Z"},"price":{"@int":"1"},"name":"limes"}}]},"txn_ts":1,"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"@set":{"data":[
+			{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10Z"},"price":{"@int":"2"},"name":"apples"}},
+			{"@doc":{"id":"2","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10Z"},"name":"pears"}}
+		],"after":"next"}},"txn_ts":1,"stats":{}}`))
+	})
+	product := fauna.Module{Name: "Product"}
+
+	t.Run("CSV", func(t *testing.T) {
+		queries = nil
+		var (
+			out      bytes.Buffer
+			progress []fauna.ExportProgress
+		)
+		err := client.ExportCollection(context.Background(), product, &out, fauna.ExportCSV,
+			fauna.ExportPageSize(2),
+			fauna.ExportProgressFunc(func(p fauna.ExportProgress) { progress = append(progress, p) }))
+		require.NoError(t, err)
+
+		require.Equal(t, `id,coll,ts,name,price
+1,Product,2023-02-28T18:10:10Z,apples,2
+2,Product,2023-02-28T18:10:10Z,pears,
+3,Product,2023-02-28T18:10:10Z,limes,1
+`, out.String())
+		require.JSONEq(t, `{"query":{"fql":[{"value":{"@mod":"Product"}},".all().pageSize(",{"value":{"@int":"2"}},")"]}}`, queries[0])
+		require.Equal(t, []fauna.ExportProgress{{Pages: 1, Items: 2, Cursor: "next"}, {Pages: 2, Items: 3}}, progress)
+	})
+
+	t.Run("JSONL resumed from a cursor", func(t *testing.T) {
+		queries = nil
+		var out bytes.Buffer
+		err := client.ExportCollection(context.Background(), product, &out, fauna.ExportJSONL, fauna.ExportCursor("next"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"id":"3","coll":"Product","ts":"2023-02-28T18:10:10Z","name":"limes","price":1}`, out.String())
+		require.Len(t, queries, 1)
+	})
+
+	t.Run("CSV resumed from a cursor", func(t *testing.T) {
+		var out bytes.Buffer
+		err := client.ExportCollection(context.Background(), product, &out, fauna.ExportCSV, fauna.ExportCursor("next"))
+		require.NoError(t, err)
+		require.Equal(t, "3,Product,2023-02-28T18:10:10Z,limes,1\n", out.String())
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		require.EqualError(t, client.ExportCollection(context.Background(), product, io.Discard, fauna.ExportFormat(7)), "unknown export format 7")
+		require.EqualError(t, client.ExportCollection(context.Background(), product, io.Discard, fauna.ExportJSONL, fauna.ExportPageSize(0)), "export page size must be positive, got 0")
+	})
+}
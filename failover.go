@@ -0,0 +1,186 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	failoverAfterDefault = 3
+	probeIntervalDefault = 30 * time.Second
+)
+
+// Endpoints sets the URL of the primary Fauna endpoint, like [fauna.URL], and
+// fallback endpoints, such as other region groups, that the [fauna.Client]
+// fails over to when the endpoint in use is unavailable.
+//
+// The client moves to the next endpoint after consecutive network errors or
+// 503 responses, 3 by default, and back to the primary once a health probe
+// finds it healthy again. The requests that fail are not resent, as they may
+// have been applied. See [fauna.EndpointFailover] to tune failing over.
+func Endpoints(primary string, fallbacks ...string) ClientConfigFn {
+	return func(c *Client) {
+		c.url = primary
+
+		c.endpoints = &endpointSet{bases: make([]*url.URL, 0, 1+len(fallbacks))}
+		for _, endpoint := range append([]string{primary}, fallbacks...) {
+			base, err := url.Parse(endpoint)
+			if err != nil {
+				c.setOptionErr(fmt.Errorf("invalid endpoint %q: %w", endpoint, err))
+				return
+			}
+			c.endpoints.bases = append(c.endpoints.bases, base)
+		}
+	}
+}
+
+// EndpointFailover sets how many consecutive network errors or 503 responses
+// make the [fauna.Client] fail over to the next of its [fauna.Endpoints], and
+// how often it probes the primary endpoint while failed over.
+func EndpointFailover(failures int, probeInterval time.Duration) ClientConfigFn {
+	return func(c *Client) {
+		if failures <= 0 || probeInterval <= 0 {
+			c.setOptionErr(fmt.Errorf("endpoint failover needs positive failures and probe interval, got %d and %s", failures, probeInterval))
+			return
+		}
+		c.failoverAfter = failures
+		c.probeInterval = probeInterval
+	}
+}
+
+// Endpoint returns the URL of the endpoint the [fauna.Client] currently sends
+// requests to, which differs from the primary one after failing over.
+func (c *Client) Endpoint() string {
+	if c.endpoints == nil {
+		return c.url
+	}
+	return c.endpoints.bases[c.endpoints.current()].String()
+}
+
+// endpointSet tracks which of a client's endpoints is in use. The first one
+// is the primary, which the URLs of requests are built from.
+type endpointSet struct {
+	bases []*url.URL
+
+	mu        sync.Mutex
+	active    int
+	failures  int
+	lastProbe time.Time
+	probing   bool
+}
+
+func (e *endpointSet) current() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.active
+}
+
+// rewrite returns u, built from the primary endpoint, with endpoint i's
+// scheme, host and path prefix instead.
+func (e *endpointSet) rewrite(u *url.URL, i int) *url.URL {
+	if i == 0 {
+		return u
+	}
+
+	primary, base := e.bases[0], e.bases[i]
+	rewritten := *u
+	rewritten.Scheme = base.Scheme
+	rewritten.Host = base.Host
+	rewritten.Path = strings.TrimSuffix(base.Path, "/") + strings.TrimPrefix(u.Path, strings.TrimSuffix(primary.Path, "/"))
+	rewritten.RawPath = ""
+	return &rewritten
+}
+
+// observe records the outcome of a request sent to endpoint i, failing over
+// to the next endpoint once failoverAfter requests in a row failed.
+func (e *endpointSet) observe(i int, failed bool, failoverAfter int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if i != e.active {
+		return
+	}
+	if !failed {
+		e.failures = 0
+		return
+	}
+
+	if e.failures++; e.failures >= failoverAfter {
+		e.active = (e.active + 1) % len(e.bases)
+		e.failures = 0
+		e.lastProbe = time.Now()
+	}
+}
+
+// startProbe reports whether the primary endpoint is due to be probed, in
+// which case the caller must probe it and then call endProbe.
+func (e *endpointSet) startProbe(interval time.Duration) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.active == 0 || e.probing || time.Since(e.lastProbe) < interval {
+		return false
+	}
+	e.probing = true
+	return true
+}
+
+func (e *endpointSet) endProbe(healthy bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.probing = false
+	e.lastProbe = time.Now()
+	if healthy {
+		e.active = 0
+		e.failures = 0
+	}
+}
+
+// failedOver reports whether a request to an endpoint failed in a way that
+// counts towards failing over.
+func failedOver(ctx context.Context, res *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled)
+	}
+	return res.StatusCode == http.StatusServiceUnavailable
+}
+
+// probePrimary runs a trivial query against the primary endpoint, failing
+// back to it if it succeeds.
+func (c *Client) probePrimary() {
+	healthy := false
+	defer func() { c.endpoints.endProbe(healthy) }()
+
+	queryURL := c.endpoints.bases[0].JoinPath("query", c.apiVersion("query"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.probeInterval)
+	defer cancel()
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, queryURL.String(), strings.NewReader(`{"query":{"fql":["null"]}}`))
+	if err != nil {
+		return
+	}
+	req.Header.Set(headerAuthorization, `Bearer `+token)
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return
+	}
+	_ = c.drainResponse(res.Body)
+	healthy = res.StatusCode == http.StatusOK
+}
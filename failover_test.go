@@ -0,0 +1,78 @@
+package fauna_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoints(t *testing.T) {
+	var primaryDown atomic.Bool
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/query/1", r.URL.Path)
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"code":"service_unavailable","message":"down"},"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":"primary","txn_ts":1,"stats":{}}`))
+	}))
+	defer primary.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/region/query/1", r.URL.Path)
+		_, _ = w.Write([]byte(`{"data":"fallback","txn_ts":1,"stats":{}}`))
+	}))
+	defer fallback.Close()
+
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(),
+		fauna.Endpoints(primary.URL, fallback.URL+"/region"),
+		fauna.EndpointFailover(2, 50*time.Millisecond),
+	)
+
+	query := func() (any, error) {
+		q, _ := fauna.FQL(`null`, nil)
+		res, err := client.Query(q)
+		if err != nil {
+			return nil, err
+		}
+		return res.Data, nil
+	}
+
+	data, err := query()
+	require.NoError(t, err)
+	require.Equal(t, "primary", data)
+
+	primaryDown.Store(true)
+	for i := 0; i < 2; i++ {
+		_, err = query()
+		require.Error(t, err)
+	}
+	require.Equal(t, fallback.URL+"/region", client.Endpoint())
+
+	data, err = query()
+	require.NoError(t, err)
+	require.Equal(t, "fallback", data)
+
+	primaryDown.Store(false)
+	require.Eventually(t, func() bool {
+		_, _ = query()
+		return client.Endpoint() == primary.URL
+	}, time.Second, 10*time.Millisecond)
+
+	data, err = query()
+	require.NoError(t, err)
+	require.Equal(t, "primary", data)
+
+	t.Run("validates options", func(t *testing.T) {
+		client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.EndpointFailover(0, time.Second))
+		q, _ := fauna.FQL(`null`, nil)
+		_, err := client.Query(q)
+		require.ErrorContains(t, err, "endpoint failover needs positive failures")
+	})
+}
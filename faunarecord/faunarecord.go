@@ -0,0 +1,284 @@
+// Package faunarecord records the traffic between a [fauna.Client] and Fauna
+// to cassette files and replays it, so that tests written against a live
+// Fauna can then run without one, such as in CI.
+//
+// Add a [Recorder] to a client with [fauna.WithMiddleware]:
+//
+//	rec, err := faunarecord.New("testdata/products.json", faunarecord.ModeFromEnv())
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	t.Cleanup(func() { _ = rec.Save() })
+//
+//	client := fauna.NewClient(secret, fauna.DefaultTimeouts(), fauna.WithMiddleware(rec.Middleware))
+//
+// Requests are matched to recorded ones by method, path and body, so tests
+// replay deterministically as long as they send the same queries. Secrets are
+// never recorded.
+package faunarecord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// EnvRecord is the environment variable that makes [ModeFromEnv] return
+// [ModeRecord] when set to true.
+const EnvRecord = "FAUNA_RECORD"
+
+// Mode sets whether a [Recorder] records or replays traffic.
+type Mode int
+
+const (
+	// ModeReplay answers requests with the responses recorded in the
+	// cassette, without sending them.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests and records them along with their responses.
+	ModeRecord
+)
+
+// ModeFromEnv returns [ModeRecord] if the [EnvRecord] environment variable is
+// true, and [ModeReplay] otherwise.
+func ModeFromEnv() Mode {
+	if record, _ := strconv.ParseBool(os.Getenv(EnvRecord)); record {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Interaction is a request and its response, as stored in a cassette.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Recorder records traffic to a cassette file in [ModeRecord], and replays
+// it from the file in [ModeReplay].
+type Recorder struct {
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// New returns a [Recorder] for the cassette at path. In [ModeReplay], the
+// cassette is read and must exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	rec := &Recorder{path: path, mode: mode}
+	if mode == ModeRecord {
+		return rec, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &rec.interactions); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	for i := range rec.interactions {
+		// cassettes are written indented, and may be edited by hand
+		rec.interactions[i].Request.Body = normalize(rec.interactions[i].Request.Body)
+	}
+	rec.replayed = make([]bool, len(rec.interactions))
+	return rec, nil
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction{}, r.interactions...)
+}
+
+// Middleware is a [fauna.Middleware] that records requests sent with next, or
+// replays them without sending them.
+func (r *Recorder) Middleware(next http.RoundTripper) http.RoundTripper {
+	return fauna.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+
+		recReq := Request{Method: req.Method, Path: req.URL.Path, Body: normalize(body)}
+		if r.mode == ModeReplay {
+			return r.replay(req, recReq)
+		}
+		return r.record(req, recReq, next)
+	})
+}
+
+// Save writes the recorded interactions to the cassette, creating its
+// directory if needed. It does nothing in [ModeReplay].
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// replay returns the response of the first interaction matching req that
+// hasn't been replayed yet.
+func (r *Recorder) replay(req *http.Request, recReq Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !matches(interaction.Request, recReq) {
+			continue
+		}
+
+		r.replayed[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("faunarecord: no recorded interaction for %s %s %s", recReq.Method, recReq.Path, recReq.Body)
+}
+
+// record sends req with next and records the interaction once its response
+// body has been read, so that streams are recorded up to where they were
+// read.
+func (r *Recorder) record(req *http.Request, recReq Request, next http.RoundTripper) (*http.Response, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	res, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := res.Header.Clone()
+	headers.Del("Date")
+	res.Body = &recordingBody{
+		ReadCloser: res.Body,
+		done: func(body []byte) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.interactions = append(r.interactions, Interaction{
+				Request:  recReq,
+				Response: Response{StatusCode: res.StatusCode, Headers: headers, Body: string(body)},
+			})
+		},
+	}
+	return res, nil
+}
+
+func matches(recorded, req Request) bool {
+	return recorded.Method == req.Method &&
+		recorded.Path == req.Path &&
+		bytes.Equal(recorded.Body, req.Body)
+}
+
+// readBody reads the body of req, replacing it so that it can still be sent.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("faunarecord: failed to read request body: %w", err)
+	}
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// normalize returns body compacted if it is JSON, so that it can be compared
+// regardless of formatting, or as a JSON string otherwise.
+func normalize(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err == nil {
+		if out, err := json.Marshal(value); err == nil {
+			return out
+		}
+	}
+
+	out, _ := json.Marshal(string(body))
+	return out
+}
+
+// recordingBody keeps what is read from a response body, calling done with
+// it once, at EOF or when the body is closed.
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	once sync.Once
+	done func(body []byte)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *recordingBody) finish() {
+	b.once.Do(func() { b.done(b.buf.Bytes()) })
+}
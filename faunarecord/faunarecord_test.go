@@ -0,0 +1,77 @@
+package faunarecord_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/faunarecord"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Fauna-Test", "yes")
+		_, _ = fmt.Fprintf(w, `{"data":{"@int":"%d"},"txn_ts":1,"stats":{}}`, requests)
+	}))
+
+	query := func(client *fauna.Client, fql string) (any, error) {
+		q, err := fauna.FQL(fql, nil)
+		require.NoError(t, err)
+		res, err := client.Query(q)
+		if err != nil {
+			return nil, err
+		}
+		return res.Data, nil
+	}
+
+	rec, err := faunarecord.New(cassette, faunarecord.ModeRecord)
+	require.NoError(t, err)
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL), fauna.WithMiddleware(rec.Middleware))
+
+	for _, fql := range []string{`1 + 1`, `2 + 2`, `1 + 1`} {
+		_, err := query(client, fql)
+		require.NoError(t, err)
+	}
+	require.NoError(t, rec.Save())
+	server.Close()
+
+	interactions := rec.Interactions()
+	require.Len(t, interactions, 3)
+	require.Equal(t, "/query/1", interactions[0].Request.Path)
+	require.Equal(t, []string{"yes"}, interactions[0].Response.Headers["X-Fauna-Test"])
+
+	rec, err = faunarecord.New(cassette, faunarecord.ModeReplay)
+	require.NoError(t, err)
+	client = fauna.NewClient("other secret", fauna.DefaultTimeouts(), fauna.URL(server.URL), fauna.WithMiddleware(rec.Middleware), fauna.MaxAttempts(1))
+
+	for _, want := range []struct {
+		fql  string
+		data int64
+	}{{`2 + 2`, 2}, {`1 + 1`, 1}, {`1 + 1`, 3}} {
+		data, err := query(client, want.fql)
+		require.NoError(t, err)
+		require.Equal(t, want.data, data)
+	}
+
+	_, err = query(client, `1 + 1`)
+	require.ErrorContains(t, err, "no recorded interaction for POST /query/1")
+
+	_, err = faunarecord.New(filepath.Join(t.TempDir(), "missing.json"), faunarecord.ModeReplay)
+	require.ErrorContains(t, err, "failed to read cassette")
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(faunarecord.EnvRecord, "")
+	require.Equal(t, faunarecord.ModeReplay, faunarecord.ModeFromEnv())
+
+	t.Setenv(faunarecord.EnvRecord, "true")
+	require.Equal(t, faunarecord.ModeRecord, faunarecord.ModeFromEnv())
+}
@@ -0,0 +1,327 @@
+// Package faunasql adapts a [fauna.Client] to database/sql, so that tools
+// built on it can run read-only FQL queries.
+//
+// Query strings are FQL templates. Named arguments, given with [sql.Named],
+// fill the template variable of the same name, and other arguments fill ${1},
+// ${2} and so on, by their position in the argument list. Any value the
+// [fauna.Client] can encode may be passed.
+//
+//	db := sql.OpenDB(faunasql.NewConnector(client))
+//	rows, err := db.QueryContext(ctx, `Product.where(.price < ${max})`, sql.Named("max", 500))
+//
+// Sets are paginated as rows are read. Each item is a row: the columns of
+// documents are id (or name), coll and ts, followed by their fields in
+// alphabetical order, and the columns of objects are their fields in
+// alphabetical order. Other items are returned in a single value column.
+// Columns are taken from the first item; fields missing from later items are
+// NULL. Modules, refs and nested documents are returned as their name or ID,
+// and nested objects and arrays as JSON.
+//
+// Each query runs in its own transaction. Exec and transactions aren't
+// supported, but the driver doesn't stop a query from writing: use a key
+// with a read-only role to enforce it.
+package faunasql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// DriverName is the name the driver is registered with in database/sql.
+const DriverName = "fauna"
+
+// ErrReadOnly is returned by Exec and Begin, as the driver only runs queries.
+var ErrReadOnly = errors.New("faunasql: only read-only queries are supported")
+
+func init() {
+	sql.Register(DriverName, Driver{})
+}
+
+// Driver is the database/sql driver registered as [DriverName]. Its data
+// source names are URLs of the Fauna endpoint with the secret as the secret
+// query parameter, e.g. "https://db.fauna.com?secret=fn...".
+type Driver struct{}
+
+// Open implements [driver.Driver].
+func (d Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector implements [driver.DriverContext].
+func (Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	endpoint, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("faunasql: invalid data source name: %w", err)
+	}
+
+	secret := endpoint.Query().Get("secret")
+	if secret == "" {
+		return nil, errors.New("faunasql: data source name has no secret")
+	}
+	endpoint.RawQuery = ""
+
+	return NewConnector(fauna.NewClient(secret, fauna.DefaultTimeouts(), fauna.URL(endpoint.String()))), nil
+}
+
+// NewConnector returns a [driver.Connector] running queries with client, to
+// be passed to [sql.OpenDB].
+func NewConnector(client *fauna.Client) driver.Connector {
+	return &connector{client: client}
+}
+
+type connector struct {
+	client *fauna.Client
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{client: c.client}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return Driver{}
+}
+
+// conn runs queries with the client. The client is safe for concurrent use,
+// so a conn holds no state of its own.
+type conn struct {
+	client *fauna.Client
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	q, err := fauna.FQL(`null`, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Query(q, fauna.QueryContext(ctx))
+	return err
+}
+
+// CheckNamedValue passes arguments to the client's encoder as they are,
+// rather than converting them to the few types of [driver.Value].
+func (c *conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	vars := make(map[string]any, len(args))
+	for _, arg := range args {
+		if arg.Name != "" {
+			vars[arg.Name] = arg.Value
+		} else {
+			vars[strconv.Itoa(arg.Ordinal)] = arg.Value
+		}
+	}
+
+	q, err := fauna.FQL(query, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &rows{ctx: ctx, iter: c.client.Paginate(q)}
+	if err := r.fetch(); err != nil {
+		return nil, err
+	}
+	if len(r.items) > 0 {
+		r.columns = columns(r.items[0])
+	}
+	return r, nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1, as FQL templates are checked by the client.
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, ErrReadOnly
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return s.conn.QueryContext(context.Background(), s.query, named)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+type rows struct {
+	ctx     context.Context
+	iter    *fauna.QueryIterator
+	items   []any
+	columns []string
+}
+
+// fetch reads pages until one has items or there are none left.
+func (r *rows) fetch() error {
+	for len(r.items) == 0 && r.iter.HasNext() {
+		page, err := r.iter.NextWithContext(r.ctx)
+		if err != nil {
+			return err
+		}
+		r.items = page.Data
+	}
+	return nil
+}
+
+func (r *rows) Columns() []string {
+	if r.columns == nil {
+		return []string{"value"}
+	}
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	r.items = nil
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if err := r.fetch(); err != nil {
+		return err
+	}
+	if len(r.items) == 0 {
+		return io.EOF
+	}
+
+	item := r.items[0]
+	r.items = r.items[1:]
+
+	if r.columns == nil {
+		dest[0] = value(item)
+		return nil
+	}
+
+	fields := fieldsOf(item)
+	for i, column := range r.columns {
+		dest[i] = value(fields[column])
+	}
+	return nil
+}
+
+// fieldsOf returns the fields of documents and objects by column, or nil for
+// other items.
+func fieldsOf(item any) map[string]any {
+	switch v := item.(type) {
+	case *fauna.Document:
+		return withMeta(v.Data, "id", v.ID, v.Coll, v.TS)
+	case *fauna.NamedDocument:
+		return withMeta(v.Data, "name", v.Name, v.Coll, v.TS)
+	case map[string]any:
+		return v
+	default:
+		return nil
+	}
+}
+
+func withMeta(data map[string]any, key string, id string, coll *fauna.Module, ts *time.Time) map[string]any {
+	fields := make(map[string]any, len(data)+3)
+	for k, v := range data {
+		fields[k] = v
+	}
+	fields[key], fields["coll"], fields["ts"] = id, coll, ts
+	return fields
+}
+
+func columns(item any) []string {
+	var meta []string
+	switch item.(type) {
+	case *fauna.Document:
+		meta = []string{"id", "coll", "ts"}
+	case *fauna.NamedDocument:
+		meta = []string{"name", "coll", "ts"}
+	case map[string]any:
+	default:
+		return nil
+	}
+
+	isMeta := map[string]bool{}
+	for _, k := range meta {
+		isMeta[k] = true
+	}
+
+	var names []string
+	for k := range fieldsOf(item) {
+		if !isMeta[k] {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	return append(meta, names...)
+}
+
+// value converts a decoded Fauna value to a [driver.Value].
+func value(v any) driver.Value {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case int:
+		return int64(v)
+	case int64, float64, bool, string, []byte, time.Time:
+		return v
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *fauna.Module:
+		if v == nil {
+			return nil
+		}
+		return v.Name
+	case *fauna.Ref:
+		return v.ID
+	case *fauna.NamedRef:
+		return v.Name
+	case *fauna.Document:
+		return v.ID
+	case *fauna.NamedDocument:
+		return v.Name
+	case fauna.EventSource:
+		return string(v)
+	default:
+		if b, err := json.Marshal(v); err == nil {
+			return b
+		}
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,93 @@
+package faunasql_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/faunasql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, string(body))
+
+		if strings.Contains(string(body), "Set.paginate") {
+			_, _ = w.Write([]byte(`{"data":{"@set":{"data":[
+{"@doc":{"id":"2","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10Z"},"name":"pears"}}
+]}},"txn_ts":1,"stats":{}}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"@set":{"data":[
+{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2023-02-28T18:10:10Z"},"name":"limes","price":{"@int":"250"},"tags":["fruit"]}}
+],"after":"next"}},"txn_ts":1,"stats":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL))
+	db := sql.OpenDB(faunasql.NewConnector(client))
+	t.Cleanup(func() { _ = db.Close() })
+
+	rows, err := db.QueryContext(context.Background(), `Product.where(.price < ${max} && .name != ${2})`, sql.Named("max", 500), "kiwis")
+	require.NoError(t, err)
+
+	columns, err := rows.Columns()
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "coll", "ts", "name", "price", "tags"}, columns)
+
+	type product struct {
+		ID, Coll, Name string
+		Price          sql.NullInt64
+		Tags           sql.NullString
+	}
+	var products []product
+	for rows.Next() {
+		var p product
+		var ts any
+		require.NoError(t, rows.Scan(&p.ID, &p.Coll, &ts, &p.Name, &p.Price, &p.Tags))
+		products = append(products, p)
+	}
+	require.NoError(t, rows.Err())
+
+	require.Equal(t, []product{
+		{ID: "1", Coll: "Product", Name: "limes", Price: sql.NullInt64{Int64: 250, Valid: true}, Tags: sql.NullString{String: `["fruit"]`, Valid: true}},
+		{ID: "2", Coll: "Product", Name: "pears"},
+	}, products)
+
+	require.Len(t, queries, 2)
+	require.Contains(t, queries[0], `{"value":{"@int":"500"}}`)
+	require.Contains(t, queries[0], `{"value":"kiwis"}`)
+}
+
+func TestScalars(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"@int":"42"},"txn_ts":1,"stats":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	db, err := sql.Open(faunasql.DriverName, server.URL+"?secret=secret")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	var count int
+	require.NoError(t, db.QueryRow(`Product.all().count()`).Scan(&count))
+	require.Equal(t, 42, count)
+
+	_, err = db.Exec(`Product.create({ name: "limes" })`)
+	require.ErrorIs(t, err, faunasql.ErrReadOnly)
+
+	_, err = db.Begin()
+	require.ErrorIs(t, err, faunasql.ErrReadOnly)
+
+	_, err = sql.Open(faunasql.DriverName, server.URL)
+	require.ErrorContains(t, err, "no secret")
+}
@@ -0,0 +1,106 @@
+// Package faunatest provides assertions for enforcing query performance
+// budgets in tests, such as limits on the ops a query may consume, and on the
+// shape of the queries an application builds.
+package faunatest
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// AssertReadOpsBelow asserts that res consumed fewer than n read ops.
+func AssertReadOpsBelow(t testing.TB, res *fauna.QuerySuccess, n int) bool {
+	t.Helper()
+
+	stats, ok := statsOf(t, res)
+	if !ok {
+		return false
+	}
+
+	if stats.ReadOps >= n {
+		t.Errorf("query used %d read ops, want fewer than %d\n%s", stats.ReadOps, n, res.Summary)
+		return false
+	}
+	return true
+}
+
+// AssertComputeOpsBelow asserts that res consumed fewer than n compute ops.
+func AssertComputeOpsBelow(t testing.TB, res *fauna.QuerySuccess, n int) bool {
+	t.Helper()
+
+	stats, ok := statsOf(t, res)
+	if !ok {
+		return false
+	}
+
+	if stats.ComputeOps >= n {
+		t.Errorf("query used %d compute ops, want fewer than %d\n%s", stats.ComputeOps, n, res.Summary)
+		return false
+	}
+	return true
+}
+
+// AssertNoWrites asserts that res didn't write anything.
+func AssertNoWrites(t testing.TB, res *fauna.QuerySuccess) bool {
+	t.Helper()
+
+	stats, ok := statsOf(t, res)
+	if !ok {
+		return false
+	}
+
+	if stats.WriteOps > 0 || stats.StorageBytesWrite > 0 {
+		t.Errorf("query used %d write ops and wrote %d bytes, want no writes", stats.WriteOps, stats.StorageBytesWrite)
+		return false
+	}
+	return true
+}
+
+// AssertQueryContains asserts that the FQL of q, as returned by
+// [fauna.Query.String], contains fragment.
+func AssertQueryContains(t testing.TB, q *fauna.Query, fragment string) bool {
+	t.Helper()
+
+	if fql := q.String(); !strings.Contains(fql, fragment) {
+		t.Errorf("query doesn't contain %q:\n%s", fragment, fql)
+		return false
+	}
+	return true
+}
+
+// AssertQueryNotContains asserts that the FQL of q doesn't contain fragment,
+// e.g. to rule out full collection scans with ".all()".
+func AssertQueryNotContains(t testing.TB, q *fauna.Query, fragment string) bool {
+	t.Helper()
+
+	if fql := q.String(); strings.Contains(fql, fragment) {
+		t.Errorf("query contains %q:\n%s", fragment, fql)
+		return false
+	}
+	return true
+}
+
+// AssertQueryMatches asserts that the FQL of q matches the regular expression
+// pattern.
+func AssertQueryMatches(t testing.TB, q *fauna.Query, pattern string) bool {
+	t.Helper()
+
+	if fql := q.String(); !regexp.MustCompile(pattern).MatchString(fql) {
+		t.Errorf("query doesn't match %s:\n%s", pattern, fql)
+		return false
+	}
+	return true
+}
+
+func statsOf(t testing.TB, res *fauna.QuerySuccess) (*fauna.Stats, bool) {
+	t.Helper()
+
+	if res == nil || res.QueryInfo == nil || res.Stats == nil {
+		t.Errorf("query result has no stats")
+		return nil, false
+	}
+	return res.Stats, true
+}
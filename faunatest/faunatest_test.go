@@ -0,0 +1,55 @@
+package faunatest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/faunatest"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the failures reported by an assertion instead of failing
+// the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestStatsAssertions(t *testing.T) {
+	res := &fauna.QuerySuccess{QueryInfo: &fauna.QueryInfo{Stats: &fauna.Stats{ReadOps: 8, ComputeOps: 1, WriteOps: 2}}}
+
+	rec := &recorder{TB: t}
+	require.True(t, faunatest.AssertReadOpsBelow(rec, res, 9))
+	require.True(t, faunatest.AssertComputeOpsBelow(rec, res, 2))
+	require.Empty(t, rec.failures)
+
+	require.False(t, faunatest.AssertReadOpsBelow(rec, res, 8))
+	require.False(t, faunatest.AssertComputeOpsBelow(rec, res, 1))
+	require.False(t, faunatest.AssertNoWrites(rec, res))
+	require.False(t, faunatest.AssertNoWrites(rec, &fauna.QuerySuccess{}))
+	require.Len(t, rec.failures, 4)
+	require.Contains(t, rec.failures[0], "query used 8 read ops, want fewer than 8")
+}
+
+func TestQueryAssertions(t *testing.T) {
+	filter, _ := fauna.FQL(`.where(.price < ${max})`, map[string]any{"max": 10})
+	q, _ := fauna.FQL(`Product.byCategory(${category})${filter}`, map[string]any{"category": "fruit", "filter": filter})
+
+	rec := &recorder{TB: t}
+	require.True(t, faunatest.AssertQueryContains(rec, q, ".where(.price < ${...})"))
+	require.True(t, faunatest.AssertQueryNotContains(rec, q, ".all()"))
+	require.True(t, faunatest.AssertQueryMatches(rec, q, `^Product\.by\w+\(`))
+	require.Empty(t, rec.failures)
+
+	require.False(t, faunatest.AssertQueryContains(rec, q, "fruit"))
+	require.False(t, faunatest.AssertQueryNotContains(rec, q, ".where("))
+	require.False(t, faunatest.AssertQueryMatches(rec, q, `\.all\(\)`))
+	require.Len(t, rec.failures, 3)
+}
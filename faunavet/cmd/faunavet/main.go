@@ -0,0 +1,18 @@
+// Command faunavet checks Go packages for mistakes in their use of the Fauna
+// driver, such as invalid fauna struct tags and FQL templates missing
+// arguments. It runs as part of go vet:
+//
+//	go install github.com/fauna/fauna-go/v3/faunavet/cmd/faunavet
+//	go vet -vettool=$(which faunavet) ./...
+//
+// See package [github.com/fauna/fauna-go/v3/faunavet] for what is checked.
+package main
+
+import (
+	"github.com/fauna/fauna-go/v3/faunavet"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(faunavet.Analyzer)
+}
@@ -0,0 +1,345 @@
+// Package faunavet defines an [analysis.Analyzer] that checks code using the
+// Fauna driver for mistakes that would otherwise only show at runtime:
+//
+//   - fauna struct tags with unknown or misplaced hints, such as
+//     `fauna:"created,date"` on a field that isn't a time, or fields of a
+//     struct that are encoded under the same name
+//   - constant FQL templates, passed to [fauna.FQL], [fauna.FQLStrict] or
+//     [fauna.FQLf], with arguments missing or unused, or with the problems
+//     [fauna.LintTemplate] reports
+//   - errors returned by these functions and [fauna.FQLFromFS] that are
+//     ignored
+//
+// The cmd/faunavet command runs it with go vet, and it can be added to any
+// other driver for analyzers, such as golangci-lint. faunavet is a module of
+// its own, so that the driver doesn't depend on golang.org/x/tools.
+package faunavet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fauna/fauna-go/v3"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const faunaPath = "github.com/fauna/fauna-go/v3"
+
+// Analyzer checks fauna struct tags and FQL templates.
+var Analyzer = &analysis.Analyzer{
+	Name:     "faunavet",
+	Doc:      "check fauna struct tags, FQL templates and their arguments",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// templateConstructors are the functions of the fauna package building
+// queries, whose errors must be checked.
+var templateConstructors = map[string]bool{
+	"FQL":       true,
+	"FQLStrict": true,
+	"FQLf":      true,
+	"FQLFromFS": true,
+}
+
+// tagHints are the hints a fauna struct tag can have after the field name.
+var tagHints = map[string]bool{
+	"date":      true,
+	"time":      true,
+	"timelocal": true,
+	"squash":    true,
+	"remain":    true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodes := []ast.Node{(*ast.StructType)(nil), (*ast.CallExpr)(nil), (*ast.ExprStmt)(nil), (*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}
+	insp.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.StructType:
+			checkStruct(pass, n)
+		case *ast.CallExpr:
+			checkTemplate(pass, n)
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok {
+				if name := templateConstructor(pass, call); name != "" {
+					pass.Reportf(call.Pos(), "error returned by fauna.%s is not checked", name)
+				}
+			}
+		case *ast.AssignStmt:
+			checkIgnoredError(pass, n.Lhs, n.Rhs)
+		case *ast.ValueSpec:
+			names := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				names[i] = name
+			}
+			checkIgnoredError(pass, names, n.Values)
+		}
+	})
+	return nil, nil
+}
+
+// templateConstructor returns the name of the fauna function call calls, if
+// it is one of templateConstructors.
+func templateConstructor(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != faunaPath || !templateConstructors[fn.Name()] {
+		return ""
+	}
+	return fn.Name()
+}
+
+// checkIgnoredError reports a template constructor whose error is assigned
+// to the blank identifier.
+func checkIgnoredError(pass *analysis.Pass, lhs []ast.Expr, rhs []ast.Expr) {
+	if len(rhs) != 1 || len(lhs) != 2 {
+		return
+	}
+	call, ok := rhs[0].(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	if name := templateConstructor(pass, call); name != "" {
+		if id, ok := lhs[1].(*ast.Ident); ok && id.Name == "_" {
+			pass.Reportf(id.Pos(), "error returned by fauna.%s is ignored", name)
+		}
+	}
+}
+
+var placeholderRegex = regexp.MustCompile(`\$\$|\$\{([_a-zA-Z0-9]*)\}`)
+
+// checkTemplate checks the constant template of a call to FQL, FQLStrict or
+// FQLf, and the arguments given for it when they are a literal.
+func checkTemplate(pass *analysis.Pass, call *ast.CallExpr) {
+	name := templateConstructor(pass, call)
+	if name == "" || name == "FQLFromFS" || len(call.Args) == 0 {
+		return
+	}
+
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+	template := constant.StringVal(tv.Value)
+
+	for _, issue := range fauna.LintTemplate(template) {
+		pass.Reportf(templatePos(call.Args[0], issue.Position.Offset), "FQL template: %s (%s)", issue.Message, issue.Code)
+	}
+
+	used := map[string]bool{}
+	var vars []string
+	for _, m := range placeholderRegex.FindAllStringSubmatch(template, -1) {
+		if v := m[1]; v != "" && !used[v] {
+			used[v] = true
+			vars = append(vars, v)
+		}
+	}
+
+	var args map[string]bool
+	var argsNode ast.Node
+	if name == "FQLf" {
+		if call.Ellipsis.IsValid() {
+			return
+		}
+		args = map[string]bool{}
+		for i := range call.Args[1:] {
+			args[strconv.Itoa(i)] = true
+		}
+		argsNode = call
+	} else {
+		if len(call.Args) < 2 {
+			return
+		}
+		args, ok = literalKeys(pass, call.Args[1])
+		if !ok {
+			return
+		}
+		argsNode = call.Args[1]
+	}
+
+	for _, v := range vars {
+		if !args[v] {
+			pass.Reportf(argsNode.Pos(), "template variable %s is missing from the args of fauna.%s", v, name)
+		}
+	}
+
+	var unused []string
+	for arg := range args {
+		if !used[arg] {
+			unused = append(unused, arg)
+		}
+	}
+	sort.Strings(unused)
+	for _, arg := range unused {
+		pass.Reportf(argsNode.Pos(), "arg %s is not used by the template of fauna.%s", arg, name)
+	}
+}
+
+// literalKeys returns the keys of args if it is nil or a map literal with
+// constant keys.
+func literalKeys(pass *analysis.Pass, args ast.Expr) (map[string]bool, bool) {
+	if pass.TypesInfo.Types[args].IsNil() {
+		return map[string]bool{}, true
+	}
+
+	lit, ok := args.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	keys := map[string]bool{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, false
+		}
+		tv := pass.TypesInfo.Types[kv.Key]
+		if tv.Value == nil || tv.Value.Kind() != constant.String {
+			return nil, false
+		}
+		keys[constant.StringVal(tv.Value)] = true
+	}
+	return keys, true
+}
+
+// templatePos returns the position of offset in the template expr, if it is
+// a raw string literal, and the position of expr otherwise, as offsets in
+// other strings don't map to their source.
+func templatePos(expr ast.Expr, offset int) token.Pos {
+	if lit, ok := expr.(*ast.BasicLit); ok && strings.HasPrefix(lit.Value, "`") && !strings.Contains(lit.Value, "\r") {
+		return lit.Pos() + token.Pos(1+offset)
+	}
+	return expr.Pos()
+}
+
+// checkStruct checks the fauna tags of a struct's fields, and, if it has
+// any, that no two fields are encoded under the same name. Names conflict
+// regardless of case, as fields are decoded case-insensitively.
+func checkStruct(pass *analysis.Pass, st *ast.StructType) {
+	type conflict struct {
+		pos     token.Pos
+		message string
+	}
+	var conflicts []conflict
+	fields := map[string]string{}
+	tagged := false
+
+	for _, field := range st.Fields.List {
+		tag, hasTag := faunaTag(field)
+		tagged = tagged || hasTag
+
+		parts := strings.Split(tag, ",")
+		if len(parts) > 1 {
+			checkTagHints(pass, field, tag, parts)
+			if parts[1] == "squash" || parts[1] == "remain" {
+				continue
+			}
+		}
+
+		name := parts[0]
+		if name == "-" || len(field.Names) == 0 && name == "" {
+			// embedded fields are flattened unless they are named by a tag
+			continue
+		}
+
+		idents := field.Names
+		if len(idents) == 0 {
+			idents = []*ast.Ident{ast.NewIdent(types.ExprString(field.Type))}
+			idents[0].NamePos = field.Pos()
+		}
+		for _, id := range idents {
+			if len(field.Names) > 0 && !id.IsExported() {
+				continue
+			}
+			encoded := name
+			if encoded == "" {
+				encoded = id.Name
+			}
+
+			key := strings.ToLower(encoded)
+			if other, ok := fields[key]; ok {
+				conflicts = append(conflicts, conflict{id.Pos(), fmt.Sprintf("field %s is encoded as %q, which conflicts with field %s", id.Name, encoded, other)})
+				continue
+			}
+			fields[key] = id.Name
+		}
+	}
+
+	if tagged {
+		for _, c := range conflicts {
+			pass.Reportf(c.pos, "%s", c.message)
+		}
+	}
+}
+
+// faunaTag returns the fauna tag of field, if it has one.
+func faunaTag(field *ast.Field) (string, bool) {
+	if field.Tag == nil {
+		return "", false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+	return reflect.StructTag(tag).Lookup("fauna")
+}
+
+// checkTagHints reports unknown hints, and hints that don't apply to the
+// type of field.
+func checkTagHints(pass *analysis.Pass, field *ast.Field, tag string, parts []string) {
+	if len(parts) > 2 {
+		pass.Reportf(field.Tag.Pos(), "fauna tag %q has more than one hint", tag)
+		return
+	}
+	if len(parts) < 2 {
+		return
+	}
+
+	hint := parts[1]
+	switch {
+	case hint == "omitempty":
+		pass.Reportf(field.Tag.Pos(), "fauna tags have no omitempty hint, empty values are encoded")
+	case !tagHints[hint]:
+		pass.Reportf(field.Tag.Pos(), "unknown fauna tag hint %q, expected date, time, timelocal, squash or remain", hint)
+	case hint == "date" || hint == "time":
+		if !isTime(pass.TypesInfo.TypeOf(field.Type)) {
+			pass.Reportf(field.Tag.Pos(), "fauna tag hint %q only applies to time.Time fields", hint)
+		}
+	case hint == "remain":
+		if m, ok := typeUnder(pass.TypesInfo.TypeOf(field.Type)).(*types.Map); !ok || !isString(m.Key()) {
+			pass.Reportf(field.Tag.Pos(), "fauna tag hint \"remain\" only applies to map fields with string keys")
+		}
+	}
+}
+
+func typeUnder(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	return t.Underlying()
+}
+
+func isTime(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Time"
+}
+
+func isString(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
@@ -0,0 +1,78 @@
+package faunavet_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3/faunavet"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var wantRegex = regexp.MustCompile("`([^`]*)`")
+
+// TestAnalyzer runs the analyzer on testdata/a.go, checking that it reports
+// the messages quoted by the file's "// want" comments on their lines, and
+// nothing else.
+func TestAnalyzer(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "testdata/a.go", nil, parser.ParseComments)
+	require.NoError(t, err)
+
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("a", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	var got []string
+	pass := &analysis.Pass{
+		Analyzer:  faunavet.Analyzer,
+		Fset:      fset,
+		Files:     []*ast.File{file},
+		Pkg:       pkg,
+		TypesInfo: info,
+		ResultOf:  map[*analysis.Analyzer]any{inspect.Analyzer: inspector.New([]*ast.File{file})},
+		Report: func(d analysis.Diagnostic) {
+			got = append(got, fmtDiagnostic(fset.Position(d.Pos).Line, d.Message))
+		},
+	}
+	_, err = faunavet.Analyzer.Run(pass)
+	require.NoError(t, err)
+
+	var want []string
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			text := strings.TrimPrefix(c.Text, "//")
+			if !strings.HasPrefix(strings.TrimSpace(text), "want ") {
+				continue
+			}
+			for _, m := range wantRegex.FindAllStringSubmatch(text, -1) {
+				want = append(want, fmtDiagnostic(fset.Position(c.Pos()).Line, m[1]))
+			}
+		}
+	}
+
+	require.NotEmpty(t, want)
+	sort.Strings(got)
+	sort.Strings(want)
+	require.Equal(t, want, got)
+}
+
+func fmtDiagnostic(line int, message string) string {
+	return fmt.Sprintf("%04d: %s", line, message)
+}
@@ -0,0 +1,20 @@
+module github.com/fauna/fauna-go/v3/faunavet
+
+go 1.19
+
+require (
+	github.com/fauna/fauna-go/v3 v3.0.1
+	github.com/stretchr/testify v1.8.2
+	golang.org/x/tools v0.23.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.3.2 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// faunavet is developed against the driver in the parent directory.
+replace github.com/fauna/fauna-go/v3 => ../
@@ -0,0 +1,65 @@
+package a
+
+import (
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+type Product struct {
+	fauna.Document
+	Name    string         `fauna:"name"`
+	Title   string         `fauna:"NAME"` // want `field Title is encoded as "NAME", which conflicts with field Name`
+	Created time.Time      `fauna:"created,date"`
+	Updated *time.Time     `fauna:"updated,timelocal"`
+	Price   float64        `fauna:"price,date"`      // want `fauna tag hint "date" only applies to time.Time fields`
+	Stock   int            `fauna:"stock,omitempty"` // want `fauna tags have no omitempty hint, empty values are encoded`
+	Color   string         `fauna:"color,colour"`    // want `unknown fauna tag hint "colour", expected date, time, timelocal, squash or remain`
+	Size    string         `fauna:"size,date,time"`  // want `fauna tag "size,date,time" has more than one hint`
+	Extra   []string       `fauna:",remain"`         // want `fauna tag hint "remain" only applies to map fields with string keys`
+	Rest    map[string]any `fauna:",remain"`
+	Skipped string         `fauna:"-"`
+	name    string
+	Meta    map[string]string `json:"meta"`
+}
+
+// untagged structs may have fields encoded under the same name
+type Pair struct {
+	Key string
+	KEY string
+}
+
+func queries(id string) error {
+	if _, err := fauna.FQL(`Product.byId(${id})`, map[string]any{"id": id}); err != nil {
+		return err
+	}
+
+	_, _ = fauna.FQL(`Product.byId(${id})`, map[string]any{"ID": id}) // want `template variable id is missing from the args of fauna.FQL` `arg ID is not used by the template of fauna.FQL` `error returned by fauna.FQL is ignored`
+
+	if _, err := fauna.FQLStrict(`Product.all()`, nil); err != nil {
+		return err
+	}
+
+	if _, err := fauna.FQLStrict(`Product.byId(${id})`, nil); err != nil { // want `template variable id is missing from the args of fauna.FQLStrict`
+		return err
+	}
+
+	if _, err := fauna.FQLf(`${0} + ${1}`, 1); err != nil { // want `template variable 1 is missing from the args of fauna.FQLf`
+		return err
+	}
+
+	if _, err := fauna.FQLf(`Product.all().toStream()`); err != nil { // want `FQL template: toStream is deprecated, use eventSource instead (deprecated)`
+		return err
+	}
+
+	args := map[string]any{"id": id}
+	if _, err := fauna.FQL(`Product.byId(${id}, ${other})`, args); err != nil {
+		return err
+	}
+
+	fauna.FQLFromFS(nil, "query.fql", nil) // want `error returned by fauna.FQLFromFS is not checked`
+
+	var q, _ = fauna.FQLf(`1`) // want `error returned by fauna.FQLf is ignored`
+	_ = q
+	return nil
+}
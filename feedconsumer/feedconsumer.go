@@ -0,0 +1,375 @@
+// Package feedconsumer consumes a Fauna event feed at least once, resuming
+// after restarts from the last cursor committed to a [CheckpointStore]:
+//
+//	consumer := feedconsumer.New(client, "orders-mailer", query, feedconsumer.NewFileStore("checkpoints"))
+//
+//	err := consumer.Run(ctx, func(ctx context.Context, event fauna.Event) error {
+//		if err := sendMail(ctx, event); err != nil {
+//			return err
+//		}
+//		return consumer.Commit(ctx, event.Cursor)
+//	})
+//
+// Events after the last committed cursor are delivered again when the
+// consumer restarts, so handlers must tolerate duplicates. Consumers sharing
+// a group name and store share their position in the feed, but must not run
+// at the same time.
+package feedconsumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// Checkpoint is the position of a group in a feed.
+type Checkpoint struct {
+	// Source is the event source the feed reads.
+	Source fauna.EventSource `json:"source"`
+	// Cursor is the cursor of the last committed event.
+	Cursor string `json:"cursor"`
+	// StartTS is where the feed starts until an event is committed, in
+	// microseconds since the epoch, if set with [StartTime].
+	StartTS int64 `json:"start_ts,omitempty"`
+}
+
+// CheckpointStore stores the checkpoints of consumer groups.
+type CheckpointStore interface {
+	// Load returns the checkpoint of group, or nil if it has none.
+	Load(ctx context.Context, group string) (*Checkpoint, error)
+	// Save stores the checkpoint of group.
+	Save(ctx context.Context, group string, checkpoint Checkpoint) error
+}
+
+// MemoryStore is a [CheckpointStore] that keeps checkpoints in memory, e.g.
+// for tests.
+type MemoryStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryStore returns an empty [MemoryStore].
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{checkpoints: map[string]Checkpoint{}}
+}
+
+// Load implements [CheckpointStore].
+func (s *MemoryStore) Load(_ context.Context, group string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoint, ok := s.checkpoints[group]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+// Save implements [CheckpointStore].
+func (s *MemoryStore) Save(_ context.Context, group string, checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[group] = checkpoint
+	return nil
+}
+
+// FileStore is a [CheckpointStore] that keeps the checkpoint of each group in
+// a JSON file named after it in a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a [FileStore] keeping checkpoints in dir, which is
+// created when the first one is saved.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Load implements [CheckpointStore].
+func (s *FileStore) Load(_ context.Context, group string) (*Checkpoint, error) {
+	data, err := os.ReadFile(s.path(group))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint of %s: %w", group, err)
+	}
+	return &checkpoint, nil
+}
+
+// Save implements [CheckpointStore]. The file is replaced atomically, so a
+// crash while saving leaves the previous checkpoint.
+func (s *FileStore) Save(_ context.Context, group string, checkpoint Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, group+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path(group))
+}
+
+func (s *FileStore) path(group string) string {
+	return filepath.Join(s.dir, group+".json")
+}
+
+// Handler handles an event of the feed. Error events are handled like other
+// events, with their [fauna.Event.Error] set.
+type Handler func(ctx context.Context, event fauna.Event) error
+
+// Option sets an option of a [Consumer].
+type Option func(c *Consumer)
+
+// PollInterval sets how long the consumer waits for new events once it has
+// read all those available. Defaults to [fauna.DefaultFeedPollInterval].
+func PollInterval(d time.Duration) Option {
+	return func(c *Consumer) { c.pollInterval = d }
+}
+
+// StartTime sets where the feed starts when the group has no checkpoint yet.
+// By default, it starts when its event source is created, as the consumer
+// first runs.
+func StartTime(ts time.Time) Option {
+	return func(c *Consumer) { c.startTime = ts }
+}
+
+// FeedOptions adds options to the feed, such as [fauna.EventFeedPageSize].
+// Use [StartTime] rather than [fauna.EventFeedStartTime], and don't set a
+// cursor, as the consumer resumes from its checkpoint.
+func FeedOptions(opts ...fauna.FeedOptFn) Option {
+	return func(c *Consumer) { c.feedOpts = append(c.feedOpts, opts...) }
+}
+
+// Consumer reads an event feed on behalf of a group, handing its events to a
+// [Handler] and storing the cursors committed with [Consumer.Commit].
+type Consumer struct {
+	client *fauna.Client
+	group  string
+	query  *fauna.Query
+	store  CheckpointStore
+
+	pollInterval time.Duration
+	startTime    time.Time
+	feedOpts     []fauna.FeedOptFn
+
+	mu        sync.Mutex
+	source    fauna.EventSource
+	startTS   int64
+	committed string
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// New returns a [Consumer] for group, reading the feed of the event source
+// query returns, which is only run when the group has no checkpoint.
+func New(client *fauna.Client, group string, query *fauna.Query, store CheckpointStore, opts ...Option) *Consumer {
+	c := &Consumer{
+		client:       client,
+		group:        group,
+		query:        query,
+		store:        store,
+		pollInterval: fauna.DefaultFeedPollInterval,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Run reads the feed from the group's checkpoint and calls handler with each
+// event, until ctx is done, [Consumer.Shutdown] is called, or reading the
+// feed or handler fails. It returns nil after a shutdown, and ctx.Err() when
+// ctx is done. A handler error is returned as is, and the event is delivered
+// again on the next run unless it was committed.
+func (c *Consumer) Run(ctx context.Context, handler Handler) error {
+	stop, done, err := c.start()
+	if err != nil {
+		return err
+	}
+	defer close(done)
+
+	feed, err := c.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		var page fauna.FeedPage
+		if err := feed.Next(&page); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read feed: %w", err)
+		}
+
+		for _, event := range page.Events {
+			select {
+			case <-stop:
+				return nil
+			default:
+			}
+
+			if err := handler(ctx, event); err != nil {
+				return err
+			}
+		}
+
+		if page.HasNext {
+			continue
+		}
+
+		timer := time.NewTimer(c.pollInterval)
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			return nil
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Commit stores cursor as the group's checkpoint, so that the next run
+// resumes after the event it belongs to. Commit the cursor of an event once
+// it is fully handled, from the handler or later, e.g. after a batch.
+func (c *Consumer) Commit(ctx context.Context, cursor string) error {
+	c.mu.Lock()
+	checkpoint := Checkpoint{Source: c.source, Cursor: cursor, StartTS: c.startTS}
+	c.mu.Unlock()
+
+	if checkpoint.Source == "" {
+		return errors.New("consumer is not running")
+	}
+	if err := c.store.Save(ctx, c.group, checkpoint); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.committed = cursor
+	c.mu.Unlock()
+	return nil
+}
+
+// Committed returns the cursor last committed by the consumer, if any.
+func (c *Consumer) Committed() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.committed
+}
+
+// Shutdown makes [Consumer.Run] return once the event being handled, if any,
+// is done, and waits for it until ctx is done.
+func (c *Consumer) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	stop, done := c.stop, c.done
+	if stop != nil {
+		select {
+		case <-stop:
+		default:
+			close(stop)
+		}
+	}
+	c.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// start marks the consumer as running, returning the channels signalling
+// that it should stop, and that it has.
+func (c *Consumer) start() (stop chan struct{}, done chan struct{}, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.done != nil {
+		select {
+		case <-c.done:
+		default:
+			return nil, nil, errors.New("consumer is already running")
+		}
+	}
+
+	c.stop, c.done = make(chan struct{}), make(chan struct{})
+	return c.stop, c.done, nil
+}
+
+// open opens the feed from the group's checkpoint. Without one, it runs the
+// query and saves its event source right away, so that the events that follow
+// are read even if the consumer stops before committing any.
+func (c *Consumer) open(ctx context.Context) (*fauna.EventFeed, error) {
+	checkpoint, err := c.store.Load(ctx, c.group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	if checkpoint == nil || checkpoint.Source == "" {
+		source, err := c.client.EventSourceWithContext(ctx, c.query)
+		if err != nil {
+			return nil, err
+		}
+
+		checkpoint = &Checkpoint{Source: source}
+		if !c.startTime.IsZero() {
+			checkpoint.StartTS = c.startTime.UnixMicro()
+		}
+		if err := c.store.Save(ctx, c.group, *checkpoint); err != nil {
+			return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+
+	opts := append([]fauna.FeedOptFn{}, c.feedOpts...)
+	switch {
+	case checkpoint.Cursor != "":
+		opts = append(opts, fauna.EventFeedCursor(checkpoint.Cursor))
+	case checkpoint.StartTS != 0:
+		opts = append(opts, fauna.EventFeedStartTimeUnixMicros(checkpoint.StartTS))
+	}
+
+	feed, err := c.client.FeedWithContext(ctx, checkpoint.Source, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.source, c.startTS, c.committed = checkpoint.Source, checkpoint.StartTS, checkpoint.Cursor
+	c.mu.Unlock()
+	return feed, nil
+}
@@ -0,0 +1,165 @@
+package feedconsumer_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/feedconsumer"
+	"github.com/stretchr/testify/require"
+)
+
+// newFeedServer serves a feed of events with cursors a, b and c, two per page.
+func newFeedServer(t *testing.T) (*fauna.Client, func() (queries int, feedReqs []map[string]any)) {
+	var (
+		mu       sync.Mutex
+		queries  int
+		feedReqs []map[string]any
+	)
+	cursors := []string{"a", "b", "c"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if strings.HasPrefix(r.URL.Path, "/query/") {
+			queries++
+			_, _ = w.Write([]byte(`{"data":{"@stream":"token"},"stats":{}}`))
+			return
+		}
+
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		feedReqs = append(feedReqs, req)
+
+		start := 0
+		if cursor, ok := req["cursor"].(string); ok {
+			for i, c := range cursors {
+				if c == cursor {
+					start = i + 1
+				}
+			}
+		}
+		end := start + 2
+		if end > len(cursors) {
+			end = len(cursors)
+		}
+
+		var events []string
+		for i, cursor := range cursors[start:end] {
+			events = append(events, fmt.Sprintf(`{"type":"add","txn_ts":%d,"cursor":%q,"data":%q}`, start+i+1, cursor, cursor))
+		}
+		last := "c"
+		if end > 0 {
+			last = cursors[end-1]
+		}
+		_, _ = fmt.Fprintf(w, `{"events":[%s],"cursor":%q,"has_next":%t,"stats":{}}`, strings.Join(events, ","), last, end < len(cursors))
+	}))
+	t.Cleanup(server.Close)
+
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL), fauna.MaxAttempts(1))
+	return client, func() (int, []map[string]any) {
+		mu.Lock()
+		defer mu.Unlock()
+		return queries, append([]map[string]any{}, feedReqs...)
+	}
+}
+
+func TestConsumer(t *testing.T) {
+	client, requests := newFeedServer(t)
+	query, err := fauna.FQL(`Order.all().eventSource()`, nil)
+	require.NoError(t, err)
+
+	store := feedconsumer.NewMemoryStore()
+	ctx := context.Background()
+
+	// the first run fails on b after committing a
+	consumer := feedconsumer.New(client, "mailer", query, store, feedconsumer.StartTime(time.UnixMicro(5)))
+	var handled []any
+	err = consumer.Run(ctx, func(ctx context.Context, event fauna.Event) error {
+		handled = append(handled, event.Data)
+		if event.Cursor == "b" {
+			return errors.New("mail server down")
+		}
+		return consumer.Commit(ctx, event.Cursor)
+	})
+	require.EqualError(t, err, "mail server down")
+	require.Equal(t, []any{"a", "b"}, handled)
+	require.Equal(t, "a", consumer.Committed())
+
+	checkpoint, err := store.Load(ctx, "mailer")
+	require.NoError(t, err)
+	require.Equal(t, &feedconsumer.Checkpoint{Source: "token", Cursor: "a", StartTS: 5}, checkpoint)
+
+	// the next run resumes after a, and delivers b again
+	consumer = feedconsumer.New(client, "mailer", query, store, feedconsumer.PollInterval(time.Millisecond))
+	handled = nil
+	err = consumer.Run(ctx, func(ctx context.Context, event fauna.Event) error {
+		handled = append(handled, event.Data)
+		if event.Cursor == "c" {
+			go func() { _ = consumer.Shutdown(ctx) }()
+		}
+		return consumer.Commit(ctx, event.Cursor)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []any{"b", "c"}, handled)
+	require.Equal(t, "c", consumer.Committed())
+
+	queries, feedReqs := requests()
+	require.Equal(t, 1, queries, "the event source is reused from the checkpoint")
+	require.Equal(t, float64(5), feedReqs[0]["start_ts"])
+	require.Equal(t, "a", feedReqs[1]["cursor"])
+}
+
+func TestConsumerStops(t *testing.T) {
+	client, _ := newFeedServer(t)
+	query, err := fauna.FQL(`Order.all().eventSource()`, nil)
+	require.NoError(t, err)
+
+	consumer := feedconsumer.New(client, "mailer", query, feedconsumer.NewMemoryStore(), feedconsumer.PollInterval(time.Hour))
+	require.EqualError(t, consumer.Commit(context.Background(), "a"), "consumer is not running")
+	require.NoError(t, consumer.Shutdown(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := make(chan struct{}, 3)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- consumer.Run(ctx, func(context.Context, fauna.Event) error {
+			handled <- struct{}{}
+			return nil
+		})
+	}()
+
+	for i := 0; i < 3; i++ {
+		<-handled
+	}
+	require.EqualError(t, consumer.Run(ctx, nil), "consumer is already running")
+
+	// waiting for new events
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestFileStore(t *testing.T) {
+	store := feedconsumer.NewFileStore(t.TempDir() + "/checkpoints")
+	ctx := context.Background()
+
+	checkpoint, err := store.Load(ctx, "mailer")
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+
+	require.NoError(t, store.Save(ctx, "mailer", feedconsumer.Checkpoint{Source: "token", Cursor: "a"}))
+	require.NoError(t, store.Save(ctx, "mailer", feedconsumer.Checkpoint{Source: "token", Cursor: "b"}))
+
+	checkpoint, err = store.Load(ctx, "mailer")
+	require.NoError(t, err)
+	require.Equal(t, &feedconsumer.Checkpoint{Source: "token", Cursor: "b"}, checkpoint)
+}
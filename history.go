@@ -0,0 +1,56 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// History returns the events of the document ref refers to since the given
+// time, oldest first, by reading an event feed on the document to its end.
+// The document must still exist, and since must be within the history
+// retention of its collection. opts such as [EventFeedPageSize] apply to the
+// feed; its start time is since.
+//
+// An error event in the feed stops History, which returns the events read
+// before it along with the [fauna.ErrEvent].
+func (c *Client) History(ctx context.Context, ref Ref, since time.Time, opts ...FeedOptFn) ([]Event, error) {
+	if ref.Coll == nil {
+		return nil, errors.New("ref has no collection")
+	}
+
+	q, err := FQL(`Set.single(${coll}.byId(${id})!).eventSource()`, map[string]any{"coll": ref.Coll, "id": ref.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := c.FeedFromQueryWithContext(ctx, q, append(append([]FeedOptFn{}, opts...), EventFeedStartTime(since))...)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []Event{}
+	for {
+		var page FeedPage
+		if err := feed.next(ctx, &page); err != nil {
+			return events, err
+		}
+
+		for _, event := range page.Events {
+			if event.Error != nil {
+				return events, event.Error
+			}
+			if event.Type != StatusEvent {
+				events = append(events, event)
+			}
+		}
+
+		if !page.HasNext {
+			break
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].TxnTime < events[j].TxnTime })
+	return events, nil
+}
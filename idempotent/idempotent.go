@@ -0,0 +1,135 @@
+// Package idempotent runs Fauna writes at most once per idempotency key, such
+// as writes triggered by webhooks that may be delivered more than once.
+//
+// The outcome of each write is stored with its key in the _idempotency_keys
+// collection, in the same transaction as the write. Later calls with the same
+// key skip the write and return the stored outcome instead.
+package idempotent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// Collection is the name of the collection the outcome of each write is
+// stored in.
+const Collection = "_idempotency_keys"
+
+// Setup creates the [Collection] and its byKey index if they don't exist yet.
+// [Do] calls it when it finds the collection missing.
+func Setup(ctx context.Context, client *fauna.Client) error {
+	q, err := fauna.FQL(`if (Collection.byName(${name}) == null) {
+  Collection.create({
+    name: ${name},
+    indexes: { byKey: { terms: [{ field: ".key" }] } },
+    constraints: [{ unique: ["key"] }]
+  })
+}
+null`, map[string]any{"name": Collection})
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Query(q, fauna.QueryContext(ctx)); err != nil {
+		return fmt.Errorf("failed to set up %s: %w", Collection, err)
+	}
+
+	return nil
+}
+
+// Do runs write unless a write with the same key has already run, and
+// unmarshals its result, or the result stored for key, into into, which may
+// be nil. It reports whether the write had already run.
+//
+// The result of write is stored as is, so it must be a value that can be
+// stored in a document, not a set. Documents are stored as references, so a
+// duplicate call returns the document as it is now rather than as it was
+// written.
+func Do(ctx context.Context, client *fauna.Client, key string, write *fauna.Query, into any, opts ...fauna.QueryOptFn) (duplicate bool, err error) {
+	if key == "" {
+		return false, errors.New("idempotency key must not be empty")
+	}
+
+	// The collection is looked up by name so that the query still compiles
+	// before Setup has created it. The write is wrapped in a block so that it
+	// may hold several statements.
+	q, err := fauna.FQL(`if (Collection.byName(${name}) == null) {
+  { missing: true }
+} else {
+  let coll = Collection(${name})
+  let existing = coll.byKey(${key}).first()
+  if (existing != null) {
+    { duplicate: true, result: existing!.result }
+  } else {
+    let result = {
+${write}
+    }
+    coll.create({ key: ${key}, result: result })
+    { duplicate: false, result: result }
+  }
+}`, map[string]any{
+		"name":  Collection,
+		"key":   key,
+		"write": write,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	opts = append([]fauna.QueryOptFn{fauna.QueryContext(ctx)}, opts...)
+	for setUp := false; ; setUp = true {
+		res, err := client.Query(q, opts...)
+		if err != nil {
+			return false, err
+		}
+
+		missing, duplicate, err := unmarshalOutcome(res, into)
+		if err != nil || !missing {
+			return duplicate, err
+		}
+		if setUp {
+			return false, fmt.Errorf("%s is missing after setting it up", Collection)
+		}
+
+		if err := Setup(ctx, client); err != nil {
+			return false, err
+		}
+	}
+}
+
+// unmarshalOutcome decodes the { missing, duplicate, result } object returned
+// by [Do], decoding result straight into into so that it goes through the
+// same decoding as a plain query result.
+func unmarshalOutcome(res *fauna.QuerySuccess, into any) (missing bool, duplicate bool, err error) {
+	resultType := reflect.TypeOf((*any)(nil)).Elem()
+
+	target := reflect.ValueOf(into)
+	if into != nil {
+		if target.Kind() != reflect.Pointer || target.IsNil() {
+			return false, false, fmt.Errorf("into must be a non-nil pointer, got %T", into)
+		}
+		resultType = target.Type().Elem()
+	}
+
+	outcome := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Missing", Type: reflect.TypeOf(false), Tag: `fauna:"missing"`},
+		{Name: "Duplicate", Type: reflect.TypeOf(false), Tag: `fauna:"duplicate"`},
+		{Name: "Result", Type: resultType, Tag: `fauna:"result"`},
+	}))
+	if err := res.Unmarshal(outcome.Interface()); err != nil {
+		return false, false, err
+	}
+
+	if outcome.Elem().Field(0).Bool() {
+		return true, false, nil
+	}
+
+	if into != nil {
+		target.Elem().Set(outcome.Elem().Field(2))
+	}
+	return false, outcome.Elem().Field(1).Bool(), nil
+}
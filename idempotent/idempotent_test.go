@@ -0,0 +1,65 @@
+package idempotent_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/idempotent"
+	"github.com/stretchr/testify/require"
+)
+
+type order struct {
+	Total int `fauna:"total"`
+}
+
+func TestDo(t *testing.T) {
+	var queries []string
+	created, stored := false, false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, string(body))
+
+		switch {
+		case strings.Contains(string(body), "Collection.create"):
+			created = true
+			_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+		case !created:
+			_, _ = w.Write([]byte(`{"data":{"missing":true},"txn_ts":1,"stats":{}}`))
+		case stored:
+			_, _ = w.Write([]byte(`{"data":{"duplicate":true,"result":{"total":{"@int":"10"}}},"txn_ts":1,"stats":{}}`))
+		default:
+			stored = true
+			_, _ = w.Write([]byte(`{"data":{"duplicate":false,"result":{"total":{"@int":"10"}}},"txn_ts":1,"stats":{}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL))
+	write, _ := fauna.FQL("let total = 10\nOrder.create({ total: total }) { total }", nil)
+
+	var first order
+	duplicate, err := idempotent.Do(context.Background(), client, "evt_1", write, &first)
+	require.NoError(t, err)
+	require.False(t, duplicate)
+	require.Equal(t, order{Total: 10}, first)
+
+	var second order
+	duplicate, err = idempotent.Do(context.Background(), client, "evt_1", write, &second)
+	require.NoError(t, err)
+	require.True(t, duplicate)
+	require.Equal(t, first, second)
+
+	require.Len(t, queries, 4, "the collection should be set up once, when found missing")
+	require.Contains(t, queries[1], "Collection.create")
+	require.Contains(t, queries[2], `{"value":"evt_1"}`)
+	require.Contains(t, queries[2], `let result = {\n",{"fql":["let total = 10\nOrder.create({ total: total }) { total }"]},"\n    }`)
+
+	_, err = idempotent.Do(context.Background(), client, "", write, nil)
+	require.Error(t, err)
+}
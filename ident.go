@@ -0,0 +1,97 @@
+package fauna
+
+import "fmt"
+
+// fqlKeywords are the words FQL reserves, which can't be used as identifiers.
+var fqlKeywords = map[string]bool{
+	"_":     true,
+	"at":    true,
+	"else":  true,
+	"false": true,
+	"if":    true,
+	"isa":   true,
+	"let":   true,
+	"null":  true,
+	"true":  true,
+}
+
+// reservedSchemaNames are the names of built-in modules, and the other names
+// that user-defined collections, functions and roles can't take.
+var reservedSchemaNames = map[string]bool{
+	"AccessProvider": true,
+	"Any":            true,
+	"Array":          true,
+	"Boolean":        true,
+	"Bytes":          true,
+	"Collection":     true,
+	"Credential":     true,
+	"Credentials":    true,
+	"Database":       true,
+	"Date":           true,
+	"Document":       true,
+	"Double":         true,
+	"Float":          true,
+	"Function":       true,
+	"Int":            true,
+	"Key":            true,
+	"Long":           true,
+	"Math":           true,
+	"Never":          true,
+	"Null":           true,
+	"Number":         true,
+	"Object":         true,
+	"Query":          true,
+	"Ref":            true,
+	"Role":           true,
+	"Set":            true,
+	"String":         true,
+	"Time":           true,
+	"Token":          true,
+	"Transaction":    true,
+	"documents":      true,
+	"events":         true,
+	"self":           true,
+}
+
+// ValidateIdent checks that name is an FQL identifier: a letter or underscore
+// followed by letters, digits and underscores, and not a keyword such as let
+// or null.
+func ValidateIdent(name string) error {
+	if !fieldNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid identifier %q", name)
+	}
+	if fqlKeywords[name] {
+		return fmt.Errorf("invalid identifier %q: reserved keyword", name)
+	}
+	return nil
+}
+
+// ValidateSchemaName checks that name can name a user-defined collection,
+// function or role: it must be an FQL identifier, per [ValidateIdent], and
+// not the name of a built-in module such as Collection or Key.
+func ValidateSchemaName(name string) error {
+	if err := ValidateIdent(name); err != nil {
+		return err
+	}
+	if reservedSchemaNames[name] {
+		return fmt.Errorf("invalid identifier %q: reserved schema name", name)
+	}
+	return nil
+}
+
+// Ident returns the [Module] of the user-defined collection or function
+// named name, checked with [ValidateSchemaName]. Use it when the name comes
+// from user input, so that it can't refer to a built-in module or anything
+// but a single schema item:
+//
+//	coll, err := fauna.Ident(r.FormValue("collection"))
+//	if err != nil {
+//		return err
+//	}
+//	q, err := fauna.FQL(`${coll}.all()`, map[string]any{"coll": coll})
+func Ident(name string) (Module, error) {
+	if err := ValidateSchemaName(name); err != nil {
+		return Module{}, err
+	}
+	return Module{Name: name}, nil
+}
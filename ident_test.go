@@ -0,0 +1,34 @@
+package fauna_test
+
+import (
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdent(t *testing.T) {
+	for _, name := range []string{"Product", "_drafts", "order_items2"} {
+		mod, err := fauna.Ident(name)
+		require.NoError(t, err)
+		require.Equal(t, fauna.Module{Name: name}, mod)
+	}
+
+	for name, msg := range map[string]string{
+		"":              `invalid identifier ""`,
+		"2fast":         `invalid identifier "2fast"`,
+		"Product.all()": `invalid identifier "Product.all()"`,
+		"Product}; Key": `invalid identifier "Product}; Key"`,
+		"prodüct":       `invalid identifier "prodüct"`,
+		"let":           `invalid identifier "let": reserved keyword`,
+		"_":             `invalid identifier "_": reserved keyword`,
+		"Key":           `invalid identifier "Key": reserved schema name`,
+		"Collection":    `invalid identifier "Collection": reserved schema name`,
+	} {
+		_, err := fauna.Ident(name)
+		require.EqualError(t, err, msg, name)
+	}
+
+	require.NoError(t, fauna.ValidateIdent("Collection"))
+	require.EqualError(t, fauna.ValidateSchemaName("null"), `invalid identifier "null": reserved keyword`)
+}
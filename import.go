@@ -0,0 +1,448 @@
+package fauna
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const importBatchSizeDefault = 100
+
+// ImportConflict is what [Client.Import] does with a line whose id is already
+// used by a document of the collection.
+type ImportConflict int
+
+const (
+	// ImportConflictError fails the import, as creating the document fails.
+	ImportConflictError ImportConflict = iota
+	// ImportConflictSkip keeps the existing document and skips the line.
+	ImportConflictSkip
+	// ImportConflictReplace replaces the existing document with the line.
+	ImportConflictReplace
+)
+
+type importOptions struct {
+	batchSize   int
+	offset      int
+	schema      reflect.Type
+	queryOpts   []QueryOptFn
+	csv         bool
+	fieldMap    map[string]string
+	concurrency int
+	onConflict  ImportConflict
+	dryRun      bool
+}
+
+// ImportOptFn function to set options on the [Client.Import]
+type ImportOptFn func(opts *importOptions)
+
+// ImportBatchSize sets the number of documents created by each query. Defaults
+// to 100.
+func ImportBatchSize(size int) ImportOptFn {
+	return func(opts *importOptions) { opts.batchSize = size }
+}
+
+// ImportOffset skips the first lines of the input, such as those already
+// imported before a failure.
+func ImportOffset(lines int) ImportOptFn {
+	return func(opts *importOptions) { opts.offset = lines }
+}
+
+// ImportSchema validates each line against the struct type of prototype, and
+// creates documents from the decoded struct, so that its field types and
+// fauna tags decide how values are stored. For example, RFC 3339 strings in
+// the input are stored as times for time.Time fields. Lines with fields the
+// struct doesn't have, other than coll and ts, fail the import.
+func ImportSchema(prototype any) ImportOptFn {
+	return func(opts *importOptions) {
+		t := reflect.TypeOf(prototype)
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		opts.schema = t
+	}
+}
+
+// ImportQueryOptions sets the options used for each query made by the import.
+func ImportQueryOptions(opts ...QueryOptFn) ImportOptFn {
+	return func(o *importOptions) { o.queryOpts = opts }
+}
+
+// ImportCSV reads the input as CSV, such as the output of [CSVEncoder], with
+// a header record naming the field of each column. Lines are counted in
+// records, the header being the first, which is read even when skipped with
+// [ImportOffset]. Empty cells are left out, and cells holding JSON numbers,
+// booleans, objects or arrays are stored as such, other cells and ids as
+// strings. With [ImportSchema], cells are converted to the types of the
+// struct's fields instead.
+func ImportCSV() ImportOptFn {
+	return func(opts *importOptions) { opts.csv = true }
+}
+
+// ImportFieldMap renames the fields of each line before it is validated or
+// stored: a field named like a key of mapping is renamed to its value, such
+// as the name in the fauna tag of an [ImportSchema] field. Fields mapped to
+// an empty name are dropped.
+func ImportFieldMap(mapping map[string]string) ImportOptFn {
+	return func(opts *importOptions) { opts.fieldMap = mapping }
+}
+
+// ImportConcurrency sets how many batches are imported at once. Defaults to
+// 1. With more, batches after one that failed may have been imported as
+// well, so resuming with the count [Client.Import] returns can import them
+// again, unless with [ImportConflictSkip] or [ImportConflictReplace].
+func ImportConcurrency(batches int) ImportOptFn {
+	return func(opts *importOptions) { opts.concurrency = batches }
+}
+
+// ImportOnConflict sets what to do with lines whose id is already used by a
+// document of the collection. Defaults to [ImportConflictError].
+func ImportOnConflict(strategy ImportConflict) ImportOptFn {
+	return func(opts *importOptions) { opts.onConflict = strategy }
+}
+
+// ImportDryRun validates every line, and encodes the documents they produce
+// as they would be sent to Fauna, without creating them.
+func ImportDryRun() ImportOptFn {
+	return func(opts *importOptions) { opts.dryRun = true }
+}
+
+// Import reads one JSON object per line from r, such as the output of
+// [JSONLEncoder], or CSV with [ImportCSV], and creates a document in coll for
+// each. The coll and ts fields written by an export are dropped. Without
+// [ImportSchema], integral numbers are stored as Longs and other numbers as
+// Doubles.
+//
+// Import returns the number of lines read, including those skipped by
+// [ImportOffset]. If it fails, no document from the failing batch has been
+// created, so passing the returned count to ImportOffset resumes the import.
+func (c *Client) Import(ctx context.Context, r io.Reader, coll string, opts ...ImportOptFn) (int, error) {
+	options := importOptions{batchSize: importBatchSizeDefault, concurrency: 1}
+	for _, optFn := range opts {
+		optFn(&options)
+	}
+	if options.batchSize < 1 {
+		options.batchSize = 1
+	}
+	if options.concurrency < 1 {
+		options.concurrency = 1
+	}
+
+	fql, err := options.importFQL()
+	if err != nil {
+		return 0, err
+	}
+
+	next := jsonLines(r)
+	if options.csv {
+		next = csvLines(r)
+	}
+
+	imp := &importer{sem: make(chan struct{}, options.concurrency)}
+	imp.write = func(batch []any) error {
+		if options.dryRun {
+			_, err := c.encoder.marshal(batch)
+			return err
+		}
+
+		q, err := FQL(fql, map[string]any{"docs": batch, "coll": &Module{Name: coll}})
+		if err != nil {
+			return err
+		}
+		queryOpts := append([]QueryOptFn{QueryContext(ctx)}, options.queryOpts...)
+		_, err = c.Query(q, queryOpts...)
+		return err
+	}
+
+	var (
+		line  int
+		batch []any
+	)
+	for !imp.failed() {
+		parse, n, readErr := next()
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return imp.wait(), readErr
+		}
+		line = n
+
+		if line > options.offset && parse != nil {
+			doc, err := options.importDoc(parse)
+			if err != nil {
+				return imp.wait(), fmt.Errorf("invalid line %d: %w", line, err)
+			}
+			batch = append(batch, doc)
+
+			if len(batch) >= options.batchSize {
+				imp.start(batch, line)
+				batch = nil
+			}
+		} else if len(batch) == 0 {
+			imp.skip(line)
+		}
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	if len(batch) > 0 && !imp.failed() {
+		imp.start(batch, line)
+	}
+	if done := imp.wait(); imp.err != nil {
+		return done, imp.err
+	}
+	return line, nil
+}
+
+// importFQL returns the FQL creating a batch of documents, with docs and coll
+// arguments.
+func (o importOptions) importFQL() (string, error) {
+	switch o.onConflict {
+	case ImportConflictError:
+		return `${docs}.forEach(doc => ${coll}.create(doc))
+null`, nil
+	case ImportConflictSkip:
+		return `${docs}.forEach(doc => if (doc.id == null || !${coll}.byId(doc.id).exists()) ${coll}.create(doc))
+null`, nil
+	case ImportConflictReplace:
+		return `${docs}.forEach(doc => if (doc.id != null && ${coll}.byId(doc.id).exists()) ${coll}.byId(doc.id)!.replace(Object.assign(doc, { id: null })) else ${coll}.create(doc))
+null`, nil
+	default:
+		return "", fmt.Errorf("unknown import conflict strategy %d", o.onConflict)
+	}
+}
+
+// importer writes batches, up to cap(sem) at a time, and tracks how many
+// lines of the input were imported without a gap.
+type importer struct {
+	write func(batch []any) error
+	sem   chan struct{}
+	wg    sync.WaitGroup
+
+	mu       sync.Mutex
+	segments []*importSegment
+	err      error
+	errAt    int
+}
+
+// importSegment is a run of lines ending at end, imported once done.
+type importSegment struct {
+	end  int
+	done bool
+}
+
+// start writes batch, made of the lines up to end, in a goroutine once fewer
+// than cap(sem) batches are being written.
+func (imp *importer) start(batch []any, end int) {
+	imp.mu.Lock()
+	start := 1
+	if n := len(imp.segments); n > 0 {
+		start = imp.segments[n-1].end + 1
+	}
+	segment := &importSegment{end: end}
+	imp.segments = append(imp.segments, segment)
+	index := len(imp.segments) - 1
+	imp.mu.Unlock()
+
+	imp.sem <- struct{}{}
+	imp.wg.Add(1)
+	go func() {
+		defer imp.wg.Done()
+		defer func() { <-imp.sem }()
+
+		err := imp.write(batch)
+
+		imp.mu.Lock()
+		defer imp.mu.Unlock()
+		if err == nil {
+			segment.done = true
+		} else if imp.err == nil || index < imp.errAt {
+			imp.err = fmt.Errorf("failed to import lines %d to %d: %w", start, end, err)
+			imp.errAt = index
+		}
+	}()
+}
+
+// skip records that the lines up to end needed no writing.
+func (imp *importer) skip(end int) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+
+	if n := len(imp.segments); n > 0 && imp.segments[n-1].done {
+		imp.segments[n-1].end = end
+		return
+	}
+	imp.segments = append(imp.segments, &importSegment{end: end, done: true})
+}
+
+func (imp *importer) failed() bool {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	return imp.err != nil
+}
+
+// wait waits for the batches being written, and returns the number of lines
+// imported before the first that wasn't.
+func (imp *importer) wait() int {
+	imp.wg.Wait()
+
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+
+	done := 0
+	for _, segment := range imp.segments {
+		if !segment.done {
+			break
+		}
+		done = segment.end
+	}
+	return done
+}
+
+// jsonLines returns a function reading a line of r at a time, along with the
+// number of lines read. It returns a nil parse function for blank lines, and
+// io.EOF after the last line.
+func jsonLines(r io.Reader) func() (parse func() (map[string]any, error), line int, err error) {
+	reader := bufio.NewReader(r)
+	line := 0
+
+	return func() (func() (map[string]any, error), int, error) {
+		raw, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, line, err
+		}
+		if len(raw) > 0 {
+			line++
+		}
+		if len(bytes.TrimSpace(raw)) == 0 {
+			return nil, line, err
+		}
+
+		return func() (map[string]any, error) {
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			dec.UseNumber()
+
+			var fields map[string]any
+			if err := dec.Decode(&fields); err != nil {
+				return nil, err
+			}
+			return fields, nil
+		}, line, err
+	}
+}
+
+// csvLines is like jsonLines for CSV with a header record, counting records
+// rather than lines.
+func csvLines(r io.Reader) func() (parse func() (map[string]any, error), line int, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	line := 0
+	var header []string
+
+	return func() (func() (map[string]any, error), int, error) {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, line, err
+		}
+		line++
+
+		if header == nil {
+			header = record
+			return nil, line, nil
+		}
+
+		return func() (map[string]any, error) {
+			if len(record) != len(header) {
+				return nil, fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+			}
+
+			fields := make(map[string]any, len(record))
+			for i, cell := range record {
+				if cell != "" {
+					fields[header[i]] = cell
+				}
+			}
+			return fields, nil
+		}, line, nil
+	}
+}
+
+func (o importOptions) importDoc(parse func() (map[string]any, error)) (any, error) {
+	fields, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	for from, to := range o.fieldMap {
+		value, ok := fields[from]
+		if !ok {
+			continue
+		}
+		delete(fields, from)
+		if to != "" {
+			fields[to] = value
+		}
+	}
+	delete(fields, "coll")
+	delete(fields, "ts")
+
+	if o.csv && o.schema == nil {
+		for name, value := range fields {
+			if name != "id" {
+				fields[name] = csvValue(value.(string))
+			}
+		}
+	}
+
+	fields = intNumbers(fields).(map[string]any)
+	if o.schema == nil {
+		return fields, nil
+	}
+
+	doc := reflect.New(o.schema)
+	structDec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          fieldTag,
+		Result:           doc.Interface(),
+		DecodeHook:       mapstructure.StringToTimeHookFunc(time.RFC3339Nano),
+		Squash:           true,
+		ErrorUnused:      true,
+		WeaklyTypedInput: o.csv,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := structDec.Decode(fields); err != nil {
+		return nil, err
+	}
+	return doc.Elem().Interface(), nil
+}
+
+// csvValue returns the JSON number, boolean, object or array cell holds, or
+// cell itself.
+func csvValue(cell string) any {
+	switch cell[0] {
+	case '{', '[', 't', 'f', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		dec := json.NewDecoder(strings.NewReader(cell))
+		dec.UseNumber()
+
+		var value any
+		if err := dec.Decode(&value); err == nil && !dec.More() {
+			if _, isString := value.(string); !isString && value != nil {
+				return value
+			}
+		}
+	}
+	return cell
+}
@@ -0,0 +1,230 @@
+package fauna_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport(t *testing.T) {
+	input := `{"id":"1","coll":"Product","ts":"2023-02-28T18:10:10Z","name":"apples","price":2}
+{"name":"pears","price":1.5}
+
+{"name":"limes","created_at":"2023-02-28T18:10:10Z"}
+`
+
+	var (
+		batches [][]any
+		fail    bool
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var req struct {
+			Query struct {
+				FQL []json.RawMessage `json:"fql"`
+			} `json:"query"`
+		}
+		require.NoError(t, json.Unmarshal(body, &req))
+
+		var docs struct {
+			Value []any `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal(req.Query.FQL[0], &docs))
+
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"code":"unavailable","message":"unavailable"},"stats":{}}`))
+			return
+		}
+
+		batches = append(batches, docs.Value)
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxAttempts(1))
+
+	t.Run("batches documents", func(t *testing.T) {
+		batches = nil
+		lines, err := client.Import(context.Background(), strings.NewReader(input), "Product", fauna.ImportBatchSize(2))
+		require.NoError(t, err)
+		require.Equal(t, 4, lines)
+
+		require.Len(t, batches, 2)
+		require.Equal(t, []any{
+			map[string]any{"id": "1", "name": "apples", "price": map[string]any{"@int": "2"}},
+			map[string]any{"name": "pears", "price": map[string]any{"@double": "1.5"}},
+		}, batches[0])
+		require.Len(t, batches[1], 1)
+	})
+
+	t.Run("resumes from a failure", func(t *testing.T) {
+		batches = nil
+		fail = true
+		lines, err := client.Import(context.Background(), strings.NewReader(input), "Product", fauna.ImportBatchSize(1), fauna.ImportOffset(1))
+		require.Error(t, err)
+		require.Equal(t, 1, lines)
+
+		fail = false
+		lines, err = client.Import(context.Background(), strings.NewReader(input), "Product", fauna.ImportBatchSize(1), fauna.ImportOffset(lines))
+		require.NoError(t, err)
+		require.Equal(t, 4, lines)
+		require.Len(t, batches, 2)
+	})
+
+	t.Run("validates against a schema", func(t *testing.T) {
+		type product struct {
+			Name      string    `fauna:"name"`
+			CreatedAt time.Time `fauna:"created_at"`
+		}
+
+		batches = nil
+		_, err := client.Import(context.Background(), strings.NewReader(input[strings.Index(input, `{"name":"limes"`):]), "Product", fauna.ImportSchema(product{}))
+		require.NoError(t, err)
+		require.Equal(t, []any{
+			map[string]any{"name": "limes", "created_at": map[string]any{"@time": "2023-02-28T18:10:10Z"}},
+		}, batches[0])
+
+		_, err = client.Import(context.Background(), strings.NewReader(`{"name":1.5}`), "Product", fauna.ImportSchema(&product{}))
+		require.ErrorContains(t, err, "invalid line 1")
+
+		_, err = client.Import(context.Background(), strings.NewReader(`{"name":"limes","colour":"green"}`), "Product", fauna.ImportSchema(product{}))
+		require.ErrorContains(t, err, "invalid line 1")
+		require.ErrorContains(t, err, "colour")
+	})
+}
+
+func TestImportOptions(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		batches  [][]any
+		queries  []string
+		failDocs string
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query struct {
+				FQL []json.RawMessage `json:"fql"`
+			} `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var docs struct {
+			Value []any `json:"value"`
+		}
+		require.NoError(t, json.Unmarshal(req.Query.FQL[0], &docs))
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if failDocs != "" && strings.Contains(string(req.Query.FQL[0]), failDocs) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"constraint_failure","message":"unique"},"stats":{}}`))
+			return
+		}
+		batches = append(batches, docs.Value)
+		var fql string
+		require.NoError(t, json.Unmarshal(req.Query.FQL[1], &fql))
+		queries = append(queries, fql)
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxAttempts(1))
+
+	reset := func() {
+		requests, batches, queries, failDocs = 0, nil, nil, ""
+	}
+
+	csvInput := `id,name,price,tags,organic
+1,apples,2,"[""fruit""]",true
+
+2,pears,1.5,,false
+`
+
+	t.Run("reads CSV", func(t *testing.T) {
+		reset()
+		lines, err := client.Import(context.Background(), strings.NewReader(csvInput), "Product", fauna.ImportCSV())
+		require.NoError(t, err)
+		require.Equal(t, 3, lines)
+		require.Equal(t, [][]any{{
+			map[string]any{"id": "1", "name": "apples", "price": map[string]any{"@int": "2"}, "tags": []any{"fruit"}, "organic": true},
+			map[string]any{"id": "2", "name": "pears", "price": map[string]any{"@double": "1.5"}, "organic": false},
+		}}, batches)
+
+		type product struct {
+			ID    string  `fauna:"id"`
+			Name  string  `fauna:"name"`
+			Price float64 `fauna:"price"`
+		}
+		reset()
+		_, err = client.Import(context.Background(), strings.NewReader("id,name,price\n1,apples,2\n"), "Product", fauna.ImportCSV(), fauna.ImportSchema(product{}))
+		require.NoError(t, err)
+		require.Equal(t, []any{
+			map[string]any{"id": "1", "name": "apples", "price": map[string]any{"@double": "2"}},
+		}, batches[0])
+
+		_, err = client.Import(context.Background(), strings.NewReader("id,name\n1\n"), "Product", fauna.ImportCSV())
+		require.EqualError(t, err, "invalid line 2: expected 2 fields, got 1")
+	})
+
+	t.Run("maps fields", func(t *testing.T) {
+		reset()
+		_, err := client.Import(context.Background(), strings.NewReader(`{"sku":"1","title":"apples","internal":true}`), "Product",
+			fauna.ImportFieldMap(map[string]string{"sku": "id", "title": "name", "internal": ""}))
+		require.NoError(t, err)
+		require.Equal(t, []any{map[string]any{"id": "1", "name": "apples"}}, batches[0])
+	})
+
+	t.Run("handles conflicts", func(t *testing.T) {
+		for strategy, want := range map[fauna.ImportConflict]string{
+			fauna.ImportConflictError:   ".forEach(doc => ",
+			fauna.ImportConflictSkip:    ".forEach(doc => if (doc.id == null || !",
+			fauna.ImportConflictReplace: ".forEach(doc => if (doc.id != null && ",
+		} {
+			reset()
+			_, err := client.Import(context.Background(), strings.NewReader(`{"id":"1"}`), "Product", fauna.ImportOnConflict(strategy))
+			require.NoError(t, err)
+			require.Equal(t, want, queries[0])
+		}
+
+		_, err := client.Import(context.Background(), strings.NewReader(`{"id":"1"}`), "Product", fauna.ImportOnConflict(7))
+		require.EqualError(t, err, "unknown import conflict strategy 7")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		reset()
+		lines, err := client.Import(context.Background(), strings.NewReader(csvInput), "Product", fauna.ImportCSV(), fauna.ImportDryRun())
+		require.NoError(t, err)
+		require.Equal(t, 3, lines)
+		require.Zero(t, requests)
+
+		_, err = client.Import(context.Background(), strings.NewReader("{\"name\":\"apples\"}\n{\"name\":}\n"), "Product", fauna.ImportDryRun())
+		require.ErrorContains(t, err, "invalid line 2")
+		require.Zero(t, requests)
+	})
+
+	t.Run("imports batches concurrently", func(t *testing.T) {
+		var input strings.Builder
+		for i := 1; i <= 10; i++ {
+			_, _ = fmt.Fprintf(&input, "{\"name\":\"product %d\"}\n", i)
+		}
+
+		reset()
+		lines, err := client.Import(context.Background(), strings.NewReader(input.String()), "Product", fauna.ImportBatchSize(2), fauna.ImportConcurrency(3))
+		require.NoError(t, err)
+		require.Equal(t, 10, lines)
+		require.Len(t, batches, 5)
+
+		reset()
+		failDocs = "product 5"
+		lines, err = client.Import(context.Background(), strings.NewReader(input.String()), "Product", fauna.ImportBatchSize(2), fauna.ImportConcurrency(3))
+		require.ErrorContains(t, err, "failed to import lines 5 to 6: ")
+		require.Equal(t, 4, lines)
+	})
+}
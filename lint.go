@@ -0,0 +1,254 @@
+package fauna
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LintIssue is a problem [LintTemplate] found in an FQL template.
+type LintIssue struct {
+	// Severity is [SeverityError] for problems that make the template fail,
+	// when passed to [fauna.FQL] or run, and [SeverityWarning] for those
+	// that are likely mistakes.
+	Severity Severity
+	// Code identifies the kind of issue, such as "unbalanced_brace".
+	Code    string
+	Message string
+	// Position is where the issue is in the template.
+	Position TemplatePosition
+	// Line is the template's line at Position, without its line break.
+	Line string
+}
+
+// String returns the issue as "line:column: severity: message (code)".
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s (%s)", i.Position.Line, i.Position.Column, i.Severity, i.Message, i.Code)
+}
+
+// deprecatedBuiltins maps deprecated FQL methods to their replacements.
+var deprecatedBuiltins = map[string]string{
+	"toStream":  "eventSource",
+	"changesOn": "eventsOn",
+}
+
+// LintTemplate checks an FQL template, such as one passed to [fauna.FQL],
+// without running it, and returns the issues it found in the order they
+// appear. It reports:
+//
+//   - placeholders that aren't of the form ${name}, or are empty
+//   - placeholders inside string literals, which aren't interpolated
+//   - unbalanced parentheses, brackets and braces, and unterminated strings
+//   - string literals concatenated with placeholders, which are better
+//     passed as a single argument
+//   - deprecated methods, such as toStream, in favor of eventSource
+//
+// It only looks at the template's text: it doesn't know the database's
+// schema, so it can't tell whether the query typechecks; use [Client.Check]
+// for that. It is meant for tools, such as a vet-style analyzer, checking the
+// FQL strings of a code base.
+func LintTemplate(template string) []LintIssue {
+	l := &linter{text: template}
+	l.scan(0, false)
+	l.checkBrackets()
+	l.checkConcatenation()
+	l.checkDeprecated()
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		return l.issues[i].Position.Offset < l.issues[j].Position.Offset
+	})
+	return l.issues
+}
+
+type lintTokenKind int
+
+const (
+	lintPunct lintTokenKind = iota
+	lintIdent
+	lintString
+	lintPlaceholder
+)
+
+type lintToken struct {
+	kind   lintTokenKind
+	text   string
+	offset int
+}
+
+type linter struct {
+	text   string
+	tokens []lintToken
+	issues []LintIssue
+}
+
+func (l *linter) report(offset int, severity Severity, code string, format string, args ...any) {
+	pos := newErrTemplate(l.text, offset, "")
+	l.issues = append(l.issues, LintIssue{
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Position: pos.Position,
+		Line:     pos.Line,
+	})
+}
+
+// scan tokenizes the template from offset, skipping comments, and returns
+// the offset where it stopped: the end of the template or, in a string
+// interpolation, its closing brace.
+func (l *linter) scan(i int, interpolation bool) int {
+	depth := 0
+	for i < len(l.text) {
+		c := l.text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(l.text[i:], "//"):
+			end := strings.IndexByte(l.text[i:], '\n')
+			if end < 0 {
+				return len(l.text)
+			}
+			i += end
+		case strings.HasPrefix(l.text[i:], "/*"):
+			end := strings.Index(l.text[i+2:], "*/")
+			if end < 0 {
+				l.report(i, SeverityError, "unterminated_comment", "unterminated comment")
+				return len(l.text)
+			}
+			i += end + 4
+		case c == '$':
+			i = l.scanPlaceholder(i, false)
+		case c == '"' || c == '\'':
+			i = l.scanString(i)
+		case isIdentStart(c):
+			start := i
+			for i < len(l.text) && isIdentPart(l.text[i]) {
+				i++
+			}
+			l.tokens = append(l.tokens, lintToken{lintIdent, l.text[start:i], start})
+		case interpolation && c == '}' && depth == 0:
+			return i
+		default:
+			if interpolation && c == '{' {
+				depth++
+			} else if interpolation && c == '}' {
+				depth--
+			}
+			l.tokens = append(l.tokens, lintToken{lintPunct, string(c), i})
+			i++
+		}
+	}
+	return i
+}
+
+// scanPlaceholder reads the placeholder or $$ escape at offset, and returns
+// the offset after it.
+func (l *linter) scanPlaceholder(i int, inString bool) int {
+	if strings.HasPrefix(l.text[i:], "$$") {
+		return i + 2
+	}
+
+	m := templateRegex.FindStringSubmatchIndex(l.text[i:])
+	if m == nil || m[0] != 0 || m[invalidIndex*2] >= 0 {
+		l.report(i, SeverityError, "invalid_placeholder", "invalid placeholder, expected ${name}")
+		return i + 1
+	}
+
+	name := l.text[i+m[bracedIndex*2] : i+m[bracedIndex*2+1]]
+	switch {
+	case name == "":
+		l.report(i, SeverityError, "empty_placeholder", "empty placeholder ${}")
+	case inString:
+		l.report(i, SeverityError, "placeholder_in_string", "placeholder ${%s} in a string literal isn't interpolated, pass the whole string as an argument", name)
+	default:
+		l.tokens = append(l.tokens, lintToken{lintPlaceholder, name, i})
+	}
+	return i + m[1]
+}
+
+// scanString reads the string literal at offset, including the expressions
+// interpolated in double-quoted strings, and returns the offset after it.
+func (l *linter) scanString(i int) int {
+	start, quote := i, l.text[i]
+	for i++; i < len(l.text); i++ {
+		switch c := l.text[i]; {
+		case c == '\\':
+			i++
+		case c == quote:
+			l.tokens = append(l.tokens, lintToken{lintString, l.text[start : i+1], start})
+			return i + 1
+		case c == '$':
+			i = l.scanPlaceholder(i, true) - 1
+		case quote == '"' && strings.HasPrefix(l.text[i:], "#{"):
+			i = l.scan(i+2, true)
+		}
+	}
+
+	l.report(start, SeverityError, "unterminated_string", "unterminated string")
+	return len(l.text)
+}
+
+// checkBrackets reports brackets that are closed by the wrong kind of
+// bracket, or not opened or closed at all.
+func (l *linter) checkBrackets() {
+	pairs := map[string]string{")": "(", "]": "[", "}": "{"}
+
+	var open []lintToken
+	for _, tok := range l.tokens {
+		if tok.kind != lintPunct {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			open = append(open, tok)
+		case ")", "]", "}":
+			if len(open) == 0 {
+				l.report(tok.offset, SeverityError, "unbalanced_brace", "unexpected %q", tok.text)
+				continue
+			}
+			last := open[len(open)-1]
+			open = open[:len(open)-1]
+			if last.text != pairs[tok.text] {
+				l.report(tok.offset, SeverityError, "unbalanced_brace", "%q doesn't match %q at line %d", tok.text, last.text, newErrTemplate(l.text, last.offset, "").Position.Line)
+			}
+		}
+	}
+
+	for _, tok := range open {
+		l.report(tok.offset, SeverityError, "unbalanced_brace", "%q is never closed", tok.text)
+	}
+}
+
+// checkConcatenation reports string literals concatenated with placeholders,
+// e.g. "user-" + ${id}, which usually means a value was split between the
+// template and its arguments.
+func (l *linter) checkConcatenation() {
+	for i := 1; i+1 < len(l.tokens); i++ {
+		if l.tokens[i].kind != lintPunct || l.tokens[i].text != "+" {
+			continue
+		}
+		prev, next := l.tokens[i-1], l.tokens[i+1]
+		if prev.kind == lintString && next.kind == lintPlaceholder || prev.kind == lintPlaceholder && next.kind == lintString {
+			l.report(prev.offset, SeverityWarning, "string_concatenation", "string literal concatenated with a placeholder, pass the whole string as an argument")
+		}
+	}
+}
+
+// checkDeprecated reports calls of deprecated methods.
+func (l *linter) checkDeprecated() {
+	for i := 1; i+1 < len(l.tokens); i++ {
+		tok := l.tokens[i]
+		replacement, ok := deprecatedBuiltins[tok.text]
+		if !ok || tok.kind != lintIdent || l.tokens[i-1].text != "." || l.tokens[i+1].text != "(" {
+			continue
+		}
+		l.report(tok.offset, SeverityWarning, "deprecated", "%s is deprecated, use %s instead", tok.text, replacement)
+	}
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || '0' <= c && c <= '9'
+}
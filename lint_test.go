@@ -0,0 +1,50 @@
+package fauna_test
+
+import (
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintTemplate(t *testing.T) {
+	lint := func(template string) []string {
+		var issues []string
+		for _, issue := range fauna.LintTemplate(template) {
+			issues = append(issues, issue.String())
+		}
+		return issues
+	}
+
+	require.Empty(t, lint(`
+		// a comment with ( and "
+		let user = Users.byId(${id})! /* { */
+		user { name, greeting: "Hi #{user.name}, it's $${x}", tags: ['a)', "b\"]"] }
+		Order.all().eventSource()`))
+
+	require.Equal(t, []string{
+		"1:14: error: placeholder ${name} in a string literal isn't interpolated, pass the whole string as an argument (placeholder_in_string)",
+		"2:1: error: invalid placeholder, expected ${name} (invalid_placeholder)",
+		"2:7: error: empty placeholder ${} (empty_placeholder)",
+	}, lint("Users.where(\"${name}\" == .name)\n$id + ${}"))
+
+	require.Equal(t, []string{
+		`1:25: error: "]" doesn't match "{" at line 1 (unbalanced_brace)`,
+		`2:1: error: "}" doesn't match "(" at line 1 (unbalanced_brace)`,
+	}, lint("Users.map(u => { u.name ]\n}"))
+
+	require.Equal(t, []string{
+		`1:1: error: unexpected "}" (unbalanced_brace)`,
+		`2:10: error: "(" is never closed (unbalanced_brace)`,
+	}, lint("}\nUsers.all(\nUsers.byId(${id})"))
+
+	require.Equal(t, []string{
+		"1:9: error: unterminated string (unterminated_string)",
+	}, lint(`let x = "abc #{y}`))
+
+	require.Equal(t, []string{
+		`1:12: warning: string literal concatenated with a placeholder, pass the whole string as an argument (string_concatenation)`,
+		`1:43: warning: toStream is deprecated, use eventSource instead (deprecated)`,
+		`1:67: warning: changesOn is deprecated, use eventsOn instead (deprecated)`,
+	}, lint(`Users.byId("user-" + ${id})!; Users.all().toStream(); Users.all().changesOn(.name)`))
+}
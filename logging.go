@@ -0,0 +1,179 @@
+package fauna
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// The components of the [fauna.Client] that can be logged at their own level
+// with WithLogComponentLevels.
+const (
+	LogComponentQuery  = "query"
+	LogComponentStream = "stream"
+	LogComponentFeed   = "feed"
+	LogComponentRetry  = "retry"
+)
+
+var logComponents = map[string]bool{
+	LogComponentQuery:  true,
+	LogComponentStream: true,
+	LogComponentFeed:   true,
+	LogComponentRetry:  true,
+}
+
+// logComponentOf returns the component sending requests to u, such as
+// LogComponentStream for <endpoint>/stream/1.
+func logComponentOf(u *url.URL) string {
+	switch component := path.Base(path.Dir(u.Path)); component {
+	case LogComponentStream, LogComponentFeed:
+		return component
+	default:
+		return LogComponentQuery
+	}
+}
+
+// loggerFor returns the logger of component, which is the client's logger
+// unless the component has its own level or sampling.
+func (c *Client) loggerFor(component string) Logger {
+	if logger, found := c.componentLoggers[component]; found {
+		return logger
+	}
+	return c.logger
+}
+
+// logResponse logs the response to a request with body with the logger of
+// component, after redacting the fields set with [WithLogRedaction]. Failed
+// requests without a response aren't logged.
+func (c *Client) logResponse(component string, body []byte, r *http.Response) {
+	if r == nil {
+		return
+	}
+
+	// the request's context holds whether it is sampled
+	ctx := c.ctx
+	if r.Request != nil {
+		ctx = r.Request.Context()
+	}
+	c.loggerFor(component).LogResponse(ctx, c.redactLogBody(body), r)
+}
+
+// logSlowQuery warns about the query with response qRes if it ran for longer
+// than the threshold set with [WithSlowQueryLog], in Fauna or including the
+// elapsed time to send it.
+func (c *Client) logSlowQuery(elapsed time.Duration, qRes *queryResponse) {
+	if c.slowQueryThreshold <= 0 {
+		return
+	}
+
+	stats := Stats{}
+	if qRes.Stats != nil {
+		stats = *qRes.Stats
+	}
+	queryTime := time.Duration(stats.QueryTimeMs) * time.Millisecond
+	if elapsed <= c.slowQueryThreshold && queryTime <= c.slowQueryThreshold {
+		return
+	}
+
+	msg := fmt.Sprintf("slow query %s took %v, %v in Fauna (tags: %s, compute_ops: %d, read_ops: %d, write_ops: %d, contention_retries: %d, storage_bytes_read: %d, storage_bytes_write: %d)",
+		qRes.RequestID, elapsed.Round(time.Millisecond), queryTime, qRes.Tags,
+		stats.ComputeOps, stats.ReadOps, stats.WriteOps, stats.ContentionRetries, stats.StorageBytesRead, stats.StorageBytesWrite)
+	if qRes.Summary != "" {
+		msg += "\n" + qRes.Summary
+	}
+	c.loggerFor(LogComponentQuery).Warn(msg)
+}
+
+// redactLogBody returns body with the values of the fields at c.logRedaction
+// replaced, or body itself if there are none or it isn't JSON.
+func (c *Client) redactLogBody(body []byte) []byte {
+	if len(c.logRedaction) == 0 {
+		return body
+	}
+
+	var request map[string]any
+	if err := json.Unmarshal(body, &request); err != nil {
+		return body
+	}
+
+	request["query"] = redactPaths(request["query"], c.logRedaction, nil)
+	if arguments, ok := request["arguments"].(map[string]any); ok {
+		redactFieldPaths(arguments, c.logRedaction, nil)
+	}
+
+	redacted, err := json.Marshal(request)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactPaths replaces the fields of value at paths, which are matched
+// against the end of the path of each field, given the path at of value. The
+// wrappers of the wire format, such as {"value": ...}, aren't part of paths.
+func redactPaths(value any, paths [][]string, at []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 1 {
+			for _, wrapper := range []string{"fql", "value", "@object"} {
+				if inner, found := v[wrapper]; found {
+					v[wrapper] = redactPaths(inner, paths, at)
+					return v
+				}
+			}
+		}
+
+		redactFieldPaths(v, paths, at)
+	case []any:
+		for i, item := range v {
+			v[i] = redactPaths(item, paths, at)
+		}
+	}
+	return value
+}
+
+// redactFieldPaths redacts the fields of obj, at path at, like redactPaths.
+func redactFieldPaths(obj map[string]any, paths [][]string, at []string) {
+	for k, field := range obj {
+		fieldAt := append(append([]string{}, at...), k)
+		if matchesPath(fieldAt, paths) {
+			obj[k] = redactedValue
+		} else {
+			obj[k] = redactPaths(field, paths, fieldAt)
+		}
+	}
+}
+
+func matchesPath(at []string, paths [][]string) bool {
+	for _, path := range paths {
+		if len(path) > len(at) {
+			continue
+		}
+
+		matched := true
+		for i, segment := range path {
+			if at[len(at)-len(path)+i] != segment {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func splitLogPaths(paths []string) [][]string {
+	split := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		if path = strings.TrimSpace(path); path != "" {
+			split = append(split, strings.Split(path, "."))
+		}
+	}
+	return split
+}
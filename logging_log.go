@@ -78,10 +78,31 @@ func DefaultLogger() Logger {
 
 	if val, found := os.LookupEnv(EnvFaunaDebug); found {
 		if level, _ := strconv.Atoi(val); level >= -4 {
-			clientLogger.level = level
-			clientLogger.logger = log.New(os.Stdout, "[fauna-go] ", log.LstdFlags|log.Lshortfile)
+			return newLevelLogger(level)
 		}
 	}
 
 	return clientLogger
 }
+
+// newLevelLogger returns a logger writing to standard output at level, as
+// set with [EnvFaunaDebug].
+func newLevelLogger(level int) ClientLogger {
+	return ClientLogger{
+		level:  level,
+		logger: log.New(os.Stdout, "[fauna-go] ", log.LstdFlags|log.Lshortfile),
+	}
+}
+
+// logConfig is empty, as log components can only be configured with Go 1.21
+// and later.
+type logConfig struct{}
+
+// scopeLoggers does nothing, as loggers can only be scoped to components with
+// Go 1.21 and later.
+func (c *Client) scopeLoggers() {}
+
+// sampleLogs returns ctx, as logs can only be sampled with Go 1.21 and later.
+func (c *Client) sampleLogs(ctx context.Context, _ string) context.Context {
+	return ctx
+}
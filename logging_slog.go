@@ -4,10 +4,12 @@ package fauna
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"strconv"
+	"sync/atomic"
 )
 
 type Logger interface {
@@ -86,11 +88,205 @@ func DefaultLogger() Logger {
 
 	if val, found := os.LookupEnv(EnvFaunaDebug); found {
 		if level, _ := strconv.Atoi(val); level >= -4 {
-			clientLogger.logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-				Level: slog.Level(level),
-			}))
+			return newLevelLogger(level)
 		}
 	}
 
 	return clientLogger
 }
+
+// newLevelLogger returns a logger writing to standard output at level, as
+// set with [EnvFaunaDebug].
+func newLevelLogger(level int) ClientLogger {
+	return ClientLogger{logger: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.Level(level),
+	}))}
+}
+
+// WithLogComponentLevels sets the minimum level logged for components of the
+// [fauna.Client], such as [LogComponentStream], overriding the level of its
+// logger. Logs of a component with a level are tagged with a "component"
+// attribute, and are written to standard output if the client has no logger,
+// such as when [EnvFaunaDebug] isn't set.
+//
+// Components are only filtered by level, and not tagged, when the client uses
+// a custom [fauna.Logger] set with [WithLogger].
+func WithLogComponentLevels(levels map[string]slog.Level) ClientConfigFn {
+	return func(c *Client) {
+		for component, level := range levels {
+			if !logComponents[component] {
+				c.setOptionErr(fmt.Errorf("unknown log component %q", component))
+				return
+			}
+			if c.logConfig.levels == nil {
+				c.logConfig.levels = map[string]slog.Level{}
+			}
+			c.logConfig.levels[component] = level
+		}
+	}
+}
+
+// WithLogSampling logs only one in every n messages of component, such as
+// [LogComponentStream], to limit the volume of logs of busy components.
+func WithLogSampling(component string, n int) ClientConfigFn {
+	return func(c *Client) {
+		if !logComponents[component] {
+			c.setOptionErr(fmt.Errorf("unknown log component %q", component))
+			return
+		}
+		if n < 1 {
+			c.setOptionErr(fmt.Errorf("log sampling must be at least 1, got %d", n))
+			return
+		}
+		if c.logConfig.sampling == nil {
+			c.logConfig.sampling = map[string]int{}
+		}
+		c.logConfig.sampling[component] = n
+	}
+}
+
+// logConfig holds the levels and sampling of the client's log components.
+type logConfig struct {
+	levels   map[string]slog.Level
+	sampling map[string]int
+}
+
+// scopeLoggers sets up the loggers of the components with their own level or
+// sampling.
+func (c *Client) scopeLoggers() {
+	for component := range logComponents {
+		level, hasLevel := c.logConfig.levels[component]
+		every := c.logConfig.sampling[component]
+		if !hasLevel && every == 0 {
+			continue
+		}
+
+		logger := c.logger
+		if clientLogger, ok := logger.(ClientLogger); ok && hasLevel {
+			logger = clientLogger.forComponent(component, level)
+		}
+
+		if c.componentLoggers == nil {
+			c.componentLoggers = map[string]Logger{}
+		}
+		c.componentLoggers[component] = &componentLogger{
+			Logger:   logger,
+			level:    level,
+			hasLevel: hasLevel,
+			every:    int64(every),
+		}
+	}
+}
+
+// forComponent returns d logging component from level, with a "component"
+// attribute.
+func (d ClientLogger) forComponent(component string, level slog.Level) ClientLogger {
+	var handler slog.Handler
+	if d.logger == nil {
+		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+	} else {
+		handler = levelHandler{Handler: d.logger.Handler(), level: level}
+	}
+
+	d.logger = slog.New(handler).With(slog.String("component", component))
+	return d
+}
+
+// levelHandler handles records from level, regardless of the level of the
+// handler it wraps.
+type levelHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+func (h levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+// componentLogger filters the messages of a component by level, and samples
+// them.
+type componentLogger struct {
+	Logger
+
+	level    slog.Level
+	hasLevel bool
+	every    int64
+	count    atomic.Int64
+}
+
+func (l *componentLogger) allows(level slog.Level) bool {
+	return l.enabled(level) && l.sample()
+}
+
+func (l *componentLogger) enabled(level slog.Level) bool {
+	return !l.hasLevel || level >= l.level
+}
+
+// sample reports whether the next message is one of the one in every messages
+// logged.
+func (l *componentLogger) sample() bool {
+	return l.every <= 1 || (l.count.Add(1)-1)%l.every == 0
+}
+
+func (l *componentLogger) Debug(msg string, args ...any) {
+	if l.allows(slog.LevelDebug) {
+		l.Logger.Debug(msg, args...)
+	}
+}
+
+func (l *componentLogger) Info(msg string, args ...any) {
+	if l.allows(slog.LevelInfo) {
+		l.Logger.Info(msg, args...)
+	}
+}
+
+func (l *componentLogger) Warn(msg string, args ...any) {
+	if l.allows(slog.LevelWarn) {
+		l.Logger.Warn(msg, args...)
+	}
+}
+
+func (l *componentLogger) Error(msg string, args ...any) {
+	if l.allows(slog.LevelError) {
+		l.Logger.Error(msg, args...)
+	}
+}
+
+// LogResponse logs the response unless it is filtered out by level, or its
+// request wasn't sampled by [Client.sampleLogs].
+func (l *componentLogger) LogResponse(ctx context.Context, requestBody []byte, r *http.Response) {
+	if !l.enabled(slog.LevelInfo) {
+		return
+	}
+
+	sampled, decided := ctx.Value(logSampledKey{}).(bool)
+	if !decided {
+		sampled = l.sample()
+	}
+	if sampled {
+		l.Logger.LogResponse(ctx, requestBody, r)
+	}
+}
+
+// logSampledKey is the context key of whether the responses to a request are
+// logged by a sampled component.
+type logSampledKey struct{}
+
+// sampleLogs returns ctx with whether the responses to a request of component
+// are logged, if its logs are sampled, so that a request counts once towards
+// the sampling however many times its responses are logged.
+func (c *Client) sampleLogs(ctx context.Context, component string) context.Context {
+	logger, ok := c.componentLoggers[component].(*componentLogger)
+	if !ok || logger.every <= 1 {
+		return ctx
+	}
+	return context.WithValue(ctx, logSampledKey{}, logger.sample())
+}
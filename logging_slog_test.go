@@ -6,9 +6,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fauna/fauna-go/v3"
 	"github.com/stretchr/testify/assert"
@@ -81,6 +84,143 @@ func (c CustomLogger) Info(msg string, _ ...any) {
 	_, _ = fmt.Fprint(os.Stdout, msg)
 }
 
+func (c CustomLogger) Warn(msg string, _ ...any) {
+	_, _ = fmt.Fprintf(c.Output, "WARN: %s\n", msg)
+}
+
 func (c CustomLogger) LogResponse(_ context.Context, requestBody []byte, res *http.Response) {
 	_, _ = fmt.Fprintf(c.Output, "URL: %s\nStatus: %s\nBody: %s\n", res.Request.URL.String(), res.Status, string(requestBody))
 }
+
+func TestLogComponents(t *testing.T) {
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":42,"txn_ts":1,"stats":{}}`))
+	}
+	query, _ := fauna.FQL(`42`, nil)
+
+	t.Run("logs components at their own level", func(t *testing.T) {
+		output, err := pipeStdOut(func() {
+			client := newTestClient(t, handler, fauna.WithLogComponentLevels(map[string]slog.Level{
+				fauna.LogComponentQuery: slog.LevelDebug,
+			}))
+			_, err := client.Query(query)
+			require.NoError(t, err)
+		})
+		require.NoError(t, err)
+		require.Contains(t, string(output), `"component":"query"`)
+		require.Contains(t, string(output), `"requestBody":`)
+	})
+
+	t.Run("silences components above their level", func(t *testing.T) {
+		output, err := pipeStdOut(func() {
+			client := newTestClient(t, handler, fauna.WithLogComponentLevels(map[string]slog.Level{
+				fauna.LogComponentQuery: slog.LevelWarn,
+			}))
+			_, err := client.Query(query)
+			require.NoError(t, err)
+		})
+		require.NoError(t, err)
+		require.Empty(t, output)
+	})
+
+	t.Run("samples custom loggers", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		client := newTestClient(t, handler,
+			fauna.WithLogger(CustomLogger{Output: buf}),
+			fauna.WithLogSampling(fauna.LogComponentQuery, 2),
+		)
+
+		var unsampled bytes.Buffer
+		reference := newTestClient(t, handler, fauna.WithLogger(CustomLogger{Output: &unsampled}))
+		for i := 0; i < 4; i++ {
+			_, err := client.Query(query)
+			require.NoError(t, err)
+			_, err = reference.Query(query)
+			require.NoError(t, err)
+		}
+
+		logged, all := strings.Count(buf.String(), "URL:"), strings.Count(unsampled.String(), "URL:")
+		require.Equal(t, all/2, logged)
+	})
+
+	t.Run("samples each query once", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		client := newTestClient(t, handler,
+			fauna.WithLogger(CustomLogger{Output: buf}),
+			fauna.WithLogSampling(fauna.LogComponentQuery, 3),
+		)
+
+		for i := 0; i < 3; i++ {
+			q, _ := fauna.FQL(fmt.Sprintf(`%d`, i), nil)
+			_, err := client.Query(q)
+			require.NoError(t, err)
+		}
+
+		require.Contains(t, buf.String(), `{"fql":["0"]}`)
+		require.NotContains(t, buf.String(), `{"fql":["1"]}`)
+		require.NotContains(t, buf.String(), `{"fql":["2"]}`)
+	})
+
+	t.Run("validates components", func(t *testing.T) {
+		client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.WithLogSampling("queries", 2))
+		_, err := client.Query(query)
+		require.ErrorContains(t, err, `unknown log component "queries"`)
+	})
+}
+
+func TestLogRedaction(t *testing.T) {
+	buf := new(bytes.Buffer)
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	},
+		fauna.WithLogger(CustomLogger{Output: buf}),
+		fauna.WithLogRedaction([]string{"ssn", "user.email", "idempotency_key"}),
+	)
+
+	signup := map[string]any{
+		"user":  map[string]any{"name": "Ada", "email": "ada@example.com", "ssn": "123"},
+		"email": "bob@example.com",
+		"value": map[string]any{"ssn": "456"},
+	}
+	query, _ := fauna.FQL(`Signup.create(${signup})`, map[string]any{"signup": signup})
+	_, err := client.Query(query, fauna.IdempotencyKey("key-789"))
+	require.NoError(t, err)
+
+	logged := buf.String()
+	require.Contains(t, logged, "Ada")
+	require.Contains(t, logged, "bob@example.com", "email is only redacted in user objects")
+	require.NotContains(t, logged, "ada@example.com")
+	require.NotContains(t, logged, "123")
+	require.NotContains(t, logged, "456")
+	require.NotContains(t, logged, "key-789")
+	require.Contains(t, logged, `"hidden"`)
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	var delay time.Duration
+	stats := `{"query_time_ms":5,"read_ops":3}`
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(delay)
+		_, _ = fmt.Fprintf(w, `{"data":null,"txn_ts":1,"summary":"warning: full scan","query_tags":"team=search","stats":%s}`, stats)
+	}
+	query, _ := fauna.FQL(`Product.all()`, nil)
+
+	buf := new(bytes.Buffer)
+	client := newTestClient(t, handler, fauna.WithLogger(CustomLogger{Output: buf}), fauna.WithSlowQueryLog(100*time.Millisecond))
+	_, err := client.Query(query)
+	require.NoError(t, err)
+	require.NotContains(t, buf.String(), "WARN")
+
+	stats = `{"query_time_ms":250,"read_ops":3}`
+	res, err := client.Query(query)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "WARN: slow query "+res.RequestID+" took ")
+	require.Contains(t, buf.String(), ", 250ms in Fauna (tags: team=search, compute_ops: 0, read_ops: 3, ")
+	require.Contains(t, buf.String(), ")\nwarning: full scan\n")
+
+	buf.Reset()
+	stats, delay = `{"query_time_ms":5}`, 150*time.Millisecond
+	_, err = client.Query(query)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), ", 5ms in Fauna (")
+}
@@ -0,0 +1,274 @@
+// Package migrations runs ordered schema and data migrations against a Fauna
+// database and tracks which have been applied in the _migrations collection.
+//
+// Migrations are either Go functions added with [Register], or FQL files
+// added with [RegisterFS]. They are applied in order of their IDs, so IDs
+// should start with a zero-padded sequence number or timestamp, e.g.
+// "0001_create_users".
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// Collection is the name of the collection applied migrations are recorded in.
+const Collection = "_migrations"
+
+const (
+	upSuffix   = ".up.fql"
+	downSuffix = ".down.fql"
+)
+
+// Func applies or reverts a migration.
+type Func func(ctx context.Context, client *fauna.Client) error
+
+// Migration is a single, ordered change to a database.
+type Migration struct {
+	ID   string
+	Up   Func
+	Down Func
+
+	// upFQL and downFQL hold the queries of migrations registered with
+	// RegisterFS, which are run in the same transaction as their record.
+	upFQL   string
+	downFQL string
+}
+
+var (
+	mu       sync.Mutex
+	registry = map[string]Migration{}
+)
+
+// Register adds a migration to the set applied by [Migrate]. It panics if a
+// migration with the same ID is already registered, or if Up is nil.
+func Register(m Migration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if m.ID == "" || m.Up == nil {
+		panic("migrations: Register requires an ID and an Up func")
+	}
+	if _, dup := registry[m.ID]; dup {
+		panic("migrations: Register called twice for migration " + m.ID)
+	}
+	registry[m.ID] = m
+}
+
+// RegisterFS registers every "<id>.up.fql" file in dir as a migration, with
+// the matching "<id>.down.fql" file, if present, used to roll it back.
+func RegisterFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	files := map[string]bool{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			files[e.Name()] = true
+		}
+	}
+
+	for name := range files {
+		if !strings.HasSuffix(name, upSuffix) {
+			continue
+		}
+
+		id := strings.TrimSuffix(name, upSuffix)
+		up, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", id, err)
+		}
+
+		m := Migration{ID: id, Up: queryFunc(string(up)), upFQL: string(up)}
+		if files[id+downSuffix] {
+			down, err := fs.ReadFile(fsys, path.Join(dir, id+downSuffix))
+			if err != nil {
+				return fmt.Errorf("failed to read migration %s: %w", id, err)
+			}
+			m.Down, m.downFQL = queryFunc(string(down)), string(down)
+		}
+
+		Register(m)
+	}
+
+	return nil
+}
+
+func queryFunc(fql string) Func {
+	return func(ctx context.Context, client *fauna.Client) error {
+		_, err := query(ctx, client, fql, nil)
+		return err
+	}
+}
+
+func query(ctx context.Context, client *fauna.Client, fql string, args map[string]any) (*fauna.QuerySuccess, error) {
+	q, err := fauna.FQL(fql, args)
+	if err != nil {
+		return nil, err
+	}
+	return client.Query(q, fauna.QueryContext(ctx))
+}
+
+// Migrate applies every registered migration that hasn't been applied yet, in
+// order. It stops at the first migration that fails.
+//
+// FQL migrations registered with [RegisterFS] are recorded in the same
+// transaction that applies them. Go funcs registered with [Register] make
+// their own queries, so they are recorded in a separate transaction once Up
+// returns: if recording fails, the migration runs again on the next call, and
+// Up should be safe to repeat.
+func Migrate(ctx context.Context, client *fauna.Client) error {
+	if err := ensureCollection(ctx, client); err != nil {
+		return err
+	}
+
+	applied, err := appliedIDs(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	done := map[string]bool{}
+	for _, id := range applied {
+		done[id] = true
+	}
+
+	for _, m := range registered() {
+		if done[m.ID] {
+			continue
+		}
+
+		if m.upFQL != "" {
+			if err := run(ctx, client, m.upFQL, recordFQL, m.ID); err != nil {
+				return fmt.Errorf("failed to apply migration %s: %w", m.ID, err)
+			}
+			continue
+		}
+
+		if err := m.Up(ctx, client); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", m.ID, err)
+		}
+
+		if err := run(ctx, client, "", recordFQL, m.ID); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", m.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Rollback reverts the n most recently applied migrations, newest first. It
+// returns an error if one of them isn't registered or has no Down func. Like
+// [Migrate], FQL migrations are unrecorded in the same transaction that
+// reverts them, and Go funcs in a separate one.
+func Rollback(ctx context.Context, client *fauna.Client, n int) error {
+	if err := ensureCollection(ctx, client); err != nil {
+		return err
+	}
+
+	applied, err := appliedIDs(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	migrations := make(map[string]Migration, len(registry))
+	for id, m := range registry {
+		migrations[id] = m
+	}
+	mu.Unlock()
+
+	for i := len(applied) - 1; i >= 0 && n > 0; i, n = i-1, n-1 {
+		id := applied[i]
+
+		m, ok := migrations[id]
+		if !ok {
+			return fmt.Errorf("migration %s is applied but not registered", id)
+		}
+		if m.Down == nil {
+			return fmt.Errorf("migration %s can't be rolled back", id)
+		}
+
+		if m.downFQL != "" {
+			if err := run(ctx, client, m.downFQL, unrecordFQL, id); err != nil {
+				return fmt.Errorf("failed to roll back migration %s: %w", id, err)
+			}
+			continue
+		}
+
+		if err := m.Down(ctx, client); err != nil {
+			return fmt.Errorf("failed to roll back migration %s: %w", id, err)
+		}
+
+		if err := run(ctx, client, "", unrecordFQL, id); err != nil {
+			return fmt.Errorf("failed to record rollback of migration %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+const (
+	recordFQL   = `${coll}.create({ migration: ${id}, applied_at: Time.now() })`
+	unrecordFQL = `${coll}.where(.migration == ${id}).forEach(.delete())`
+)
+
+// run records or unrecords migration id with record, after running
+// migration, if any, in the same transaction. The migration is wrapped in a
+// block so that it may hold several statements.
+func run(ctx context.Context, client *fauna.Client, migration string, record string, id string) error {
+	fql := record + "\nnull"
+	if migration != "" {
+		fql = "{\n" + migration + "\n}\n" + fql
+	}
+
+	_, err := query(ctx, client, fql, map[string]any{"coll": &fauna.Module{Name: Collection}, "id": id})
+	return err
+}
+
+func registered() []Migration {
+	mu.Lock()
+	defer mu.Unlock()
+
+	out := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// ensureCollection creates the _migrations collection. This can't be done in
+// the same transaction that first writes to it.
+func ensureCollection(ctx context.Context, client *fauna.Client) error {
+	if _, err := query(ctx, client, `if (Collection.byName(${name}) == null) {
+  Collection.create({ name: ${name} })
+}
+null`, map[string]any{"name": Collection}); err != nil {
+		return fmt.Errorf("failed to create %s collection: %w", Collection, err)
+	}
+	return nil
+}
+
+// appliedIDs returns the IDs of applied migrations, in the order they were
+// applied.
+func appliedIDs(ctx context.Context, client *fauna.Client) ([]string, error) {
+	res, err := query(ctx, client, `${coll}.all().order(.applied_at, .migration).map(.migration).toArray()`, map[string]any{"coll": &fauna.Module{Name: Collection}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	var ids []string
+	if err := res.Unmarshal(&ids); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,183 @@
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Cleanup(reset)
+
+	var ran []string
+	for _, id := range []string{"0003_c", "0001_a", "0002_b"} {
+		id := id
+		Register(Migration{
+			ID:   id,
+			Up:   func(context.Context, *fauna.Client) error { ran = append(ran, "up "+id); return nil },
+			Down: func(context.Context, *fauna.Client) error { ran = append(ran, "down "+id); return nil },
+		})
+	}
+
+	db := &fakeDB{applied: []string{"0001_a"}}
+	client := db.client(t)
+
+	require.NoError(t, Migrate(context.Background(), client))
+	require.Equal(t, []string{"up 0002_b", "up 0003_c"}, ran)
+	require.Equal(t, []string{"0001_a", "0002_b", "0003_c"}, db.applied)
+
+	ran = nil
+	require.NoError(t, Rollback(context.Background(), client, 2))
+	require.Equal(t, []string{"down 0003_c", "down 0002_b"}, ran)
+	require.Equal(t, []string{"0001_a"}, db.applied)
+}
+
+func TestRollbackWithoutDown(t *testing.T) {
+	t.Cleanup(reset)
+
+	Register(Migration{ID: "0001_a", Up: func(context.Context, *fauna.Client) error { return nil }})
+
+	db := &fakeDB{applied: []string{"0001_a"}}
+	err := Rollback(context.Background(), db.client(t), 1)
+	require.EqualError(t, err, "migration 0001_a can't be rolled back")
+}
+
+func TestRegisterFS(t *testing.T) {
+	t.Cleanup(reset)
+
+	fsys := fstest.MapFS{
+		"db/0001_users.up.fql":    {Data: []byte(`Collection.create({ name: "Users" })`)},
+		"db/0001_users.down.fql":  {Data: []byte(`Collection.byName("Users")!.delete()`)},
+		"db/0002_orders.up.fql":   {Data: []byte(`Collection.create({ name: "Orders" })`)},
+		"db/README.md":            {Data: []byte(`ignored`)},
+		"other/0003_skip.up.fql":  {Data: []byte(`ignored`)},
+		"db/nested/0004.up.fql":   {Data: []byte(`ignored`)},
+		"db/0005_broken.down.fql": {Data: []byte(`ignored`)},
+	}
+	require.NoError(t, RegisterFS(fsys, "db"))
+
+	migrations := registered()
+	require.Len(t, migrations, 2)
+	require.Equal(t, "0001_users", migrations[0].ID)
+	require.NotNil(t, migrations[0].Down)
+	require.Equal(t, "0002_orders", migrations[1].ID)
+	require.Nil(t, migrations[1].Down)
+
+	db := &fakeDB{applied: []string{"0002_orders"}}
+	client := db.client(t)
+	require.NoError(t, Migrate(context.Background(), client))
+	require.Equal(t, []string{"0002_orders", "0001_users"}, db.applied)
+	require.Contains(t, db.queries, "{\n"+`Collection.create({ name: "Users" })`+"\n}\n$.create({ migration: $, applied_at: Time.now() })\nnull")
+
+	require.NoError(t, Rollback(context.Background(), client, 1))
+	require.Contains(t, db.queries, "{\n"+`Collection.byName("Users")!.delete()`+"\n}\n$.where(.migration == $).forEach(.delete())\nnull")
+	require.Equal(t, []string{"0002_orders"}, db.applied)
+}
+
+func TestRollbackInAppliedOrder(t *testing.T) {
+	t.Cleanup(reset)
+
+	var ran []string
+	for _, id := range []string{"0001_a", "0002_b"} {
+		id := id
+		Register(Migration{
+			ID:   id,
+			Up:   func(context.Context, *fauna.Client) error { return nil },
+			Down: func(context.Context, *fauna.Client) error { ran = append(ran, "down "+id); return nil },
+		})
+	}
+
+	// 0001_a was merged after 0002_b had been applied
+	db := &fakeDB{applied: []string{"0002_b"}}
+	client := db.client(t)
+	require.NoError(t, Migrate(context.Background(), client))
+	require.Equal(t, []string{"0002_b", "0001_a"}, db.applied)
+
+	require.NoError(t, Rollback(context.Background(), client, 1))
+	require.Equal(t, []string{"down 0001_a"}, ran)
+	require.Equal(t, []string{"0002_b"}, db.applied)
+}
+
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	registry = map[string]Migration{}
+}
+
+// fakeDB answers the queries made by this package, keeping track of applied
+// migrations in memory.
+type fakeDB struct {
+	applied []string
+	queries []string
+}
+
+func (db *fakeDB) client(t *testing.T) *fauna.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req struct {
+			Query struct {
+				FQL []json.RawMessage `json:"fql"`
+			} `json:"query"`
+		}
+		require.NoError(t, json.Unmarshal(body, &req))
+
+		var (
+			template strings.Builder
+			args     []string
+		)
+		for _, part := range req.Query.FQL {
+			var s string
+			if json.Unmarshal(part, &s) == nil {
+				template.WriteString(s)
+				continue
+			}
+
+			var value struct {
+				Value any `json:"value"`
+			}
+			require.NoError(t, json.Unmarshal(part, &value))
+			if s, ok := value.Value.(string); ok {
+				args = append(args, s)
+			}
+			template.WriteString("$")
+		}
+
+		fql := template.String()
+		db.queries = append(db.queries, fql)
+
+		var data any
+		switch {
+		case strings.Contains(fql, ".order(.applied_at, .migration).map(.migration)"):
+			// applied holds migrations in the order they were applied
+			data = append([]string{}, db.applied...)
+		case strings.Contains(fql, "create({ migration:"):
+			db.applied = append(db.applied, args[0])
+		case strings.Contains(fql, "where(.migration =="):
+			for i, id := range db.applied {
+				if id == args[0] {
+					db.applied = append(db.applied[:i], db.applied[i+1:]...)
+					break
+				}
+			}
+		}
+
+		res, err := json.Marshal(map[string]any{"data": data, "txn_ts": 1, "stats": map[string]any{}})
+		require.NoError(t, err)
+		_, _ = w.Write(res)
+	}))
+	t.Cleanup(server.Close)
+
+	return fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL))
+}
@@ -0,0 +1,153 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// HasNext returns whether the set has more items after this page.
+func (p Page) HasNext() bool {
+	return p.After != ""
+}
+
+// NextPage fetches the page following p with Set.paginate, using the client
+// that ran the query p was decoded from, such as a Page field of a
+// [fauna.QuerySuccess.Unmarshal] destination. It returns nil if p is the last
+// page.
+func (p Page) NextPage(ctx context.Context, opts ...QueryOptFn) (*Page, error) {
+	if !p.HasNext() {
+		return nil, nil
+	}
+	if p.decoder == nil || p.decoder.client == nil {
+		return nil, errors.New("page has no client, decode it from a query result to fetch the next page")
+	}
+
+	q, err := FQL(`Set.paginate(${after})`, map[string]any{"after": p.After})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.decoder.client.QueryWithContext(ctx, q, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(res), nil
+}
+
+// pageOf returns the page of results of res, which is a @set, an object with
+// data and after fields as returned by Set.paginate, or a single value.
+func pageOf(res *QuerySuccess) *Page {
+	page, ok := res.Data.(*Page)
+	if !ok {
+		page = &Page{}
+		if results, isPage := res.Data.(map[string]any); isPage {
+			page.Data, _ = results["data"].([]any)
+			page.After, _ = results["after"].(string)
+		} else {
+			page.Data = []any{res.Data}
+		}
+	}
+
+	page.decoder, page.info = res.decoder, res.QueryInfo
+	return page
+}
+
+// PageAs decodes the items of page into a slice of T, like
+// [fauna.Page.Unmarshal] into a *[]T, but faster for large pages: the slice
+// is allocated once and each item is decoded straight into it, and items
+// that already are of type T, such as strings or int64s, are copied as is.
+func PageAs[T any](page *Page) ([]T, error) {
+	d := decoder{}
+	if page.decoder != nil {
+		d = *page.decoder
+	}
+	// times are converted by the decoder's hooks
+	copyAsIs := d.opts.Location == nil
+
+	items := make([]T, len(page.Data))
+	var (
+		item T
+		dec  *mapstructure.Decoder
+	)
+	for i, data := range page.Data {
+		if v, ok := data.(T); ok && copyAsIs {
+			items[i] = v
+			continue
+		}
+
+		if dec == nil {
+			var err error
+			if dec, err = d.mapDecoder(&item); err != nil {
+				return nil, err
+			}
+		}
+
+		var zero T
+		item = zero
+		if err := dec.Decode(data); err != nil {
+			return nil, fmt.Errorf("failed to decode item %d of page: %w", i, err)
+		}
+		items[i] = item
+	}
+
+	d.localizeTimes(reflect.ValueOf(items))
+	return items, nil
+}
+
+// SetCursor is a [fauna.Page] of items of type T. Decode a set into a
+// SetCursor to read its items and fetch the pages that follow.
+type SetCursor[T any] struct {
+	Page
+}
+
+// Items decodes the items of the page, like [fauna.PageAs].
+func (c SetCursor[T]) Items() ([]T, error) {
+	return PageAs[T](&c.Page)
+}
+
+// NextPage fetches the page following c like [fauna.Page.NextPage].
+func (c SetCursor[T]) NextPage(ctx context.Context, opts ...QueryOptFn) (*SetCursor[T], error) {
+	page, err := c.Page.NextPage(ctx, opts...)
+	if page == nil || err != nil {
+		return nil, err
+	}
+	return &SetCursor[T]{Page: *page}, nil
+}
+
+func (c *SetCursor[T]) setPage(page Page) {
+	c.Page = page
+}
+
+// setCursor is implemented by pointers to every SetCursor type.
+type setCursor interface {
+	setPage(page Page)
+}
+
+var setCursorType = reflect.TypeOf((*setCursor)(nil)).Elem()
+
+// decodeSetCursor decodes sets into SetCursor fields, along with d so that
+// they can fetch their next page.
+func (d decoder) decodeSetCursor(f reflect.Type, t reflect.Type, data any) (any, error) {
+	if t.Kind() != reflect.Struct || !reflect.PtrTo(t).Implements(setCursorType) {
+		return data, nil
+	}
+
+	var page Page
+	switch {
+	case f == pageType:
+		page = data.(Page)
+	case f == reflect.PtrTo(pageType) && data.(*Page) != nil:
+		page = *data.(*Page)
+	default:
+		return data, nil
+	}
+	page.decoder = &d
+
+	cursor := reflect.New(t)
+	cursor.Interface().(setCursor).setPage(page)
+	return cursor.Elem().Interface(), nil
+}
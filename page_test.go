@@ -0,0 +1,115 @@
+package fauna_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageNextPage(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query json.RawMessage `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, string(body.Query))
+
+		switch len(queries) {
+		case 1:
+			_, _ = w.Write([]byte(`{"data":{"@object":{"products":{"@set":{"data":[{"name":"cup"}],"after":"c1"}},"tags":{"@set":{"data":["a"],"after":"c2"}}}},"txn_ts":1,"stats":{}}`))
+		case 2:
+			_, _ = w.Write([]byte(`{"data":{"data":[{"name":"mug"}]},"txn_ts":1,"stats":{}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{"data":["b"],"after":"c3"},"txn_ts":1,"stats":{}}`))
+		}
+	})
+
+	type product struct {
+		Name string `fauna:"name"`
+	}
+	var res struct {
+		Products fauna.SetCursor[product] `fauna:"products"`
+		Tags     *fauna.Page              `fauna:"tags"`
+	}
+
+	q, _ := fauna.FQL(`{ products: Product.all(), tags: Tag.all() }`, nil)
+	qRes, err := client.Query(q)
+	require.NoError(t, err)
+	require.NoError(t, qRes.Unmarshal(&res))
+
+	products, err := res.Products.Items()
+	require.NoError(t, err)
+	require.Equal(t, []product{{Name: "cup"}}, products)
+	require.True(t, res.Products.HasNext())
+
+	next, err := res.Products.NextPage(context.Background())
+	require.NoError(t, err)
+	require.JSONEq(t, `{"fql":["Set.paginate(",{"value":"c1"},")"]}`, queries[1])
+	products, err = next.Items()
+	require.NoError(t, err)
+	require.Equal(t, []product{{Name: "mug"}}, products)
+	require.False(t, next.HasNext())
+
+	last, err := next.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, last)
+	require.Len(t, queries, 2)
+
+	tags, err := res.Tags.NextPage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []any{"b"}, tags.Data)
+	require.Equal(t, "c3", tags.After)
+
+	_, err = fauna.Page{After: "c1"}.NextPage(context.Background())
+	require.ErrorContains(t, err, "page has no client")
+}
+
+func TestPageAs(t *testing.T) {
+	type product struct {
+		fauna.Document
+		Name    string    `fauna:"name"`
+		Created time.Time `fauna:"created,timelocal"`
+	}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"@set":{"data":[` +
+			`{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2024-01-01T00:00:00Z"},"name":"cup","created":{"@time":"2024-01-01T00:00:00Z"}}},` +
+			`{"@doc":{"id":"2","coll":{"@mod":"Product"},"ts":{"@time":"2024-01-01T00:00:00Z"},"name":"mug","created":{"@time":"2024-01-02T00:00:00Z"}}}` +
+			`],"after":"c1"}},"txn_ts":1,"stats":{}}`))
+	}, fauna.DefaultDecodeOptions(fauna.DecodeOptions{Location: time.FixedZone("CET", 3600)}))
+
+	q, _ := fauna.FQL(`Product.all()`, nil)
+	res, err := client.Query(q)
+	require.NoError(t, err)
+
+	var page fauna.Page
+	require.NoError(t, res.Unmarshal(&page))
+
+	products, err := fauna.PageAs[product](&page)
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	require.Equal(t, "2", products[1].ID)
+	require.Equal(t, "mug", products[1].Name)
+	require.Equal(t, "CET", products[1].Created.Location().String())
+
+	var unmarshaled []product
+	require.NoError(t, page.Unmarshal(&unmarshaled))
+	require.Equal(t, unmarshaled, products)
+
+	names, err := fauna.PageAs[string](&fauna.Page{Data: []any{"cup", "mug"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cup", "mug"}, names)
+
+	counts, err := fauna.PageAs[int](&fauna.Page{Data: []any{int64(1), int64(2)}})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, counts)
+
+	_, err = fauna.PageAs[int](&fauna.Page{Data: []any{int64(1), "two"}})
+	require.ErrorContains(t, err, "failed to decode item 1 of page: ")
+}
@@ -1,8 +1,15 @@
 package fauna
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type queryFragment struct {
@@ -51,3 +58,270 @@ func FQL(query string, args map[string]any) (*Query, error) {
 
 	return &Query{fragments: fragments}, nil
 }
+
+// FQLStrict creates a [fauna.Query] like [fauna.FQL], but checks args
+// against the template's `${name}` sigils first: it fails if args has keys
+// the template does not use, as well as if it is missing any, reporting all
+// of them and where the missing ones are used at once.
+func FQLStrict(query string, args map[string]any) (*Query, error) {
+	parts, err := parseTemplate(query)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTemplateArgs(parts, args); err != nil {
+		return nil, err
+	}
+	return FQL(query, args)
+}
+
+// FQLf creates a [fauna.Query] like [fauna.FQL], with args referred to by
+// position rather than name, for queries with only a few of them:
+//
+//	q, err := fauna.FQLf(`${0}.length + ${1}`, "foo", 2)
+//
+// Like with [fauna.FQLStrict], every arg must be used by the template.
+func FQLf(query string, args ...any) (*Query, error) {
+	parts, err := parseTemplate(query)
+	if err != nil {
+		return nil, err
+	}
+
+	named := make(map[string]any, len(args))
+	for i, arg := range args {
+		named[strconv.Itoa(i)] = arg
+	}
+	if err := checkTemplateArgs(parts, named); err != nil {
+		return nil, err
+	}
+	return FQL(query, named)
+}
+
+// checkTemplateArgs returns an error listing the variables of parts missing
+// from args, with their positions, and the args no part uses.
+func checkTemplateArgs(parts []templatePart, args map[string]any) error {
+	used := map[string]bool{}
+	var missing []string
+	missingAt := map[string][]string{}
+	for _, part := range parts {
+		if part.Category != templateVariable {
+			continue
+		}
+		used[part.Text] = true
+		if _, ok := args[part.Text]; !ok {
+			if _, seen := missingAt[part.Text]; !seen {
+				missing = append(missing, part.Text)
+			}
+			missingAt[part.Text] = append(missingAt[part.Text], strconv.Itoa(part.Position))
+		}
+	}
+
+	var unused []string
+	for name := range args {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+
+	var problems []string
+	if len(missing) > 0 {
+		vars := make([]string, len(missing))
+		for i, name := range missing {
+			positions := missingAt[name]
+			if len(positions) == 1 {
+				vars[i] = name + " (position " + positions[0] + ")"
+			} else {
+				vars[i] = name + " (positions " + strings.Join(positions, ", ") + ")"
+			}
+		}
+		problems = append(problems, "template variables not found in args: "+strings.Join(vars, ", "))
+	}
+	if len(unused) > 0 {
+		problems = append(problems, "args not used in template: "+strings.Join(unused, ", "))
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// FQLFromFS creates a [fauna.Query] like [fauna.FQL] from the FQL in the file
+// at path in fsys, such as an [embed.FS], so that larger queries and function
+// bodies can live in .fql files:
+//
+//	//go:embed queries
+//	var queries embed.FS
+//
+//	q, err := fauna.FQLFromFS(queries, "queries/top_products.fql", map[string]any{"limit": 10})
+//
+// The file may hold several statements, such as let bindings followed by the
+// expression that is the value of the query. Like with [fauna.FQLStrict],
+// args must match the file's `${name}` placeholders exactly.
+func FQLFromFS(fsys fs.FS, path string, args map[string]any) (*Query, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FQL file: %w", err)
+	}
+
+	query := strings.TrimSpace(strings.TrimPrefix(string(data), "\uFEFF"))
+	if query == "" {
+		return nil, fmt.Errorf("FQL file %s is empty", path)
+	}
+
+	parts, err := parseTemplate(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FQL file %s: %w", path, err)
+	}
+
+	if err := checkTemplateArgs(parts, args); err != nil {
+		return nil, fmt.Errorf("invalid FQL file %s: %w", path, err)
+	}
+
+	return FQL(query, args)
+}
+
+var fieldNameRegex = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+// fieldTree is a set of fields to project, which may be dotted paths, as a
+// tree of field names.
+type fieldTree struct {
+	names    []string
+	children map[string]*fieldTree
+}
+
+func newFieldTree(fields []string) (*fieldTree, error) {
+	newNode := func() *fieldTree { return &fieldTree{children: map[string]*fieldTree{}} }
+
+	root := newNode()
+	for _, field := range fields {
+		n := root
+		for _, name := range strings.Split(field, ".") {
+			if !fieldNameRegex.MatchString(name) {
+				return nil, fmt.Errorf("invalid field %q", field)
+			}
+			child, ok := n.children[name]
+			if !ok {
+				child = newNode()
+				n.children[name] = child
+				n.names = append(n.names, name)
+			}
+			n = child
+		}
+	}
+	return root, nil
+}
+
+// writeTo writes the tree as an FQL projection block.
+func (t *fieldTree) writeTo(sb *strings.Builder) {
+	sb.WriteString("{ ")
+	for i, name := range t.names {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(name)
+		if child := t.children[name]; len(child.names) > 0 {
+			sb.WriteByte(' ')
+			child.writeTo(sb)
+		}
+	}
+	sb.WriteString(" }")
+}
+
+// apply projects a decoded value like an FQL projection block would: objects
+// and documents become maps of the selected fields, with nil for missing ones,
+// and arrays are projected item by item.
+func (t *fieldTree) apply(value any) any {
+	if len(t.names) == 0 {
+		return value
+	}
+
+	var fields map[string]any
+	switch v := value.(type) {
+	case []any:
+		projected := make([]any, len(v))
+		for i, item := range v {
+			projected[i] = t.apply(item)
+		}
+		return projected
+	case map[string]any:
+		fields = v
+	case *Document:
+		fields = map[string]any{"id": v.ID, "coll": v.Coll, "ts": v.TS}
+		for k, val := range v.Data {
+			fields[k] = val
+		}
+	case *NamedDocument:
+		fields = map[string]any{"name": v.Name, "coll": v.Coll, "ts": v.TS}
+		for k, val := range v.Data {
+			fields[k] = val
+		}
+	default:
+		return value
+	}
+
+	projected := make(map[string]any, len(t.names))
+	for _, name := range t.names {
+		projected[name] = t.children[name].apply(fields[name])
+	}
+	return projected
+}
+
+// project appends a projection block selecting fields, which may be dotted
+// paths, to query.
+func project(query any, fields []string) (*Query, error) {
+	tree, err := newFieldTree(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	tree.writeTo(&sb)
+	return FQL("let result = {\n${query}\n}\nresult "+sb.String(), map[string]any{"query": query})
+}
+
+// String returns the query's FQL with nested queries inlined and other
+// arguments shown as ${...}, e.g. for logging or asserting on the shape of a
+// query. Argument values are left out, as they may be sensitive.
+func (q *Query) String() string {
+	var sb strings.Builder
+	q.writeTo(&sb)
+	return sb.String()
+}
+
+// Fingerprint returns a stable hash of the query's template, the FQL that
+// [Query.String] returns, so that queries built from the same template with
+// different arguments share it. [WithQueryFingerprintTag] sends it as a query
+// tag, to group queries in Fauna's query logs; use it to correlate them with
+// the application's own logs and metrics.
+func (q *Query) Fingerprint() string {
+	sum := sha256.Sum256([]byte(q.String()))
+	return hex.EncodeToString(sum[:fingerprintLen])
+}
+
+const (
+	// QueryFingerprintTag is the key of the query tag holding the
+	// [Query.Fingerprint] of queries, with [WithQueryFingerprintTag].
+	QueryFingerprintTag = "fql_hash"
+
+	// fingerprintLen is the number of bytes of the template hash kept in a
+	// fingerprint.
+	fingerprintLen = 8
+)
+
+func (q *Query) writeTo(sb *strings.Builder) {
+	for _, f := range q.fragments {
+		switch v := f.value.(type) {
+		case string:
+			if f.literal {
+				sb.WriteString(v)
+			} else {
+				sb.WriteString("${...}")
+			}
+		case *Query:
+			v.writeTo(sb)
+		default:
+			sb.WriteString("${...}")
+		}
+	}
+}
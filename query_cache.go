@@ -0,0 +1,158 @@
+package fauna
+
+import (
+	"container/list"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithQueryCache caches the results of read-only queries in the
+// [fauna.Client], so that running the same query again returns the cached
+// result instead of making a request. Queries are the same if they encode to
+// the same request, including their arguments and headers such as query tags.
+//
+// At most size results are kept, evicting the least recently used. A result
+// is dropped once it is older than ttl, or once the client's last seen
+// transaction time advances past the one it was read at, as it may no longer
+// be current then. A ttl of 0 only drops results on the latter.
+//
+// Only queries that report no write ops are cached, and never queries run
+// with [fauna.StreamResponse].
+func WithQueryCache(size int, ttl time.Duration) ClientConfigFn {
+	return func(c *Client) {
+		if size <= 0 {
+			c.setOptionErr(fmt.Errorf("query cache size must be positive, got %d", size))
+			return
+		}
+		if ttl < 0 {
+			c.setOptionErr(fmt.Errorf("query cache ttl must not be negative, got %s", ttl))
+			return
+		}
+		c.queryCache = newQueryCache(size, ttl)
+	}
+}
+
+// InvalidateQueryCache drops every result cached with
+// [fauna.WithQueryCache], e.g. after data changed outside of the client.
+func (c *Client) InvalidateQueryCache() {
+	if c.queryCache != nil {
+		c.queryCache.clear()
+	}
+}
+
+type queryCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type queryCacheEntry struct {
+	key      string
+	res      *queryResponse
+	attempts int
+	txnTime  int64
+	stored   time.Time
+}
+
+func newQueryCache(size int, ttl time.Duration) *queryCache {
+	return &queryCache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// queryCacheKey identifies a request by its encoded body and the headers
+// that can change its result.
+func queryCacheKey(body []byte, headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		switch http.CanonicalHeaderKey(name) {
+		case http.CanonicalHeaderKey(HeaderRequestID), http.CanonicalHeaderKey(HeaderTraceparent):
+		default:
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.Write(body)
+	for _, name := range names {
+		key.WriteString("\n" + name + ":" + headers[name])
+	}
+	return key.String()
+}
+
+// get returns the response cached for key, unless it is stale given the last
+// seen transaction time.
+func (qc *queryCache) get(key string, lastTxnTime int64) (*queryCacheEntry, bool) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	elem, found := qc.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	entry := elem.Value.(*queryCacheEntry)
+	if lastTxnTime > entry.txnTime || (qc.ttl > 0 && time.Since(entry.stored) > qc.ttl) {
+		qc.remove(elem)
+		return nil, false
+	}
+
+	qc.order.MoveToFront(elem)
+	return entry, true
+}
+
+func (qc *queryCache) put(key string, res *queryResponse, attempts int) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	entry := &queryCacheEntry{key: key, res: copyQueryResponse(res), attempts: attempts, txnTime: res.TxnTime, stored: time.Now()}
+	if elem, found := qc.entries[key]; found {
+		elem.Value = entry
+		qc.order.MoveToFront(elem)
+		return
+	}
+
+	qc.entries[key] = qc.order.PushFront(entry)
+	for qc.order.Len() > qc.size {
+		qc.remove(qc.order.Back())
+	}
+}
+
+func (qc *queryCache) clear() {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	qc.entries = map[string]*list.Element{}
+	qc.order.Init()
+}
+
+func (qc *queryCache) remove(elem *list.Element) {
+	qc.order.Remove(elem)
+	delete(qc.entries, elem.Value.(*queryCacheEntry).key)
+}
+
+// response returns a copy of the cached response, as the stats of a result
+// are updated by the caller.
+func (e *queryCacheEntry) response() *queryResponse {
+	return copyQueryResponse(e.res)
+}
+
+func copyQueryResponse(res *queryResponse) *queryResponse {
+	c := *res
+	if res.Stats != nil {
+		stats := *res.Stats
+		c.Stats = &stats
+	}
+	return &c
+}
@@ -0,0 +1,73 @@
+package fauna_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCache(t *testing.T) {
+	var (
+		requests atomic.Int64
+		txnTs    atomic.Int64
+	)
+	txnTs.Store(1)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		writes := 0
+		if r.Header.Get(fauna.HeaderTags) == "write=yes" {
+			writes = 1
+		}
+		_, _ = fmt.Fprintf(w, `{"data":{"@int":"%d"},"txn_ts":%d,"stats":{"write_ops":%d}}`, n, txnTs.Load(), writes)
+	}, fauna.WithQueryCache(2, time.Hour))
+
+	query := func(fql string, opts ...fauna.QueryOptFn) int64 {
+		t.Helper()
+		q, err := fauna.FQL(fql, nil)
+		require.NoError(t, err)
+		res, err := client.Query(q, opts...)
+		require.NoError(t, err)
+		return res.Data.(int64)
+	}
+
+	require.Equal(t, int64(1), query(`1`))
+	require.Equal(t, int64(1), query(`1`), "cached")
+	require.Equal(t, int64(2), query(`1`, fauna.Tags(map[string]string{"a": "b"})), "headers are part of the key")
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		require.Equal(t, int64(3), query(`2`))
+		require.Equal(t, int64(3), query(`2`))
+		require.Equal(t, int64(4), query(`1`))
+	})
+
+	t.Run("skips writes", func(t *testing.T) {
+		tags := fauna.Tags(map[string]string{"write": "yes"})
+		require.Equal(t, int64(5), query(`3`, tags))
+		require.Equal(t, int64(6), query(`3`, tags))
+	})
+
+	t.Run("drops results older than the last txn time", func(t *testing.T) {
+		require.Equal(t, int64(3), query(`2`))
+		txnTs.Store(2)
+		require.Equal(t, int64(7), query(`4`))
+		require.Equal(t, int64(8), query(`2`))
+		require.Equal(t, int64(8), query(`2`))
+	})
+
+	t.Run("invalidates", func(t *testing.T) {
+		client.InvalidateQueryCache()
+		require.Equal(t, int64(9), query(`2`))
+	})
+
+	t.Run("validates options", func(t *testing.T) {
+		client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.WithQueryCache(0, time.Minute))
+		q, _ := fauna.FQL(`1`, nil)
+		_, err := client.Query(q)
+		require.ErrorContains(t, err, "query cache size must be positive")
+	})
+}
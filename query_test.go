@@ -1,7 +1,9 @@
 package fauna
 
 import (
+	"io/fs"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -91,3 +93,74 @@ func BenchmarkFQL(b *testing.B) {
 		_, _ = FQL(`${arg0}.length`, map[string]any{"arg0": "foo"})
 	}
 }
+
+func TestQueryString(t *testing.T) {
+	inner, _ := FQL(`.where(.name == ${name})`, map[string]any{"name": "limes"})
+	q, _ := FQL(`Product.all()${inner} { name, price: ${price} }`, map[string]any{"inner": inner, "price": 1})
+
+	assert.Equal(t, `Product.all().where(.name == ${...}) { name, price: ${...} }`, q.String())
+}
+
+func TestFQLFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"queries/top.fql":   {Data: []byte("\ufefflet limit = ${limit}\n\nProduct.where(.category == ${category}).take(limit)\n")},
+		"queries/bad.fql":   {Data: []byte("Product.byId($id)")},
+		"queries/empty.fql": {Data: []byte("\n")},
+	}
+
+	q, err := FQLFromFS(fsys, "queries/top.fql", map[string]any{"limit": 10, "category": "tools"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "let limit = ${...}\n\nProduct.where(.category == ${...}).take(limit)", q.String())
+	}
+
+	_, err = FQLFromFS(fsys, "queries/top.fql", map[string]any{"limit": 10})
+	assert.EqualError(t, err, "invalid FQL file queries/top.fql: template variables not found in args: category (position 49)")
+
+	_, err = FQLFromFS(fsys, "queries/top.fql", map[string]any{"limit": 10, "category": "tools", "sort": "name", "after": nil})
+	assert.EqualError(t, err, "invalid FQL file queries/top.fql: args not used in template: after, sort")
+
+	_, err = FQLFromFS(fsys, "queries/bad.fql", map[string]any{"id": 1})
+	assert.EqualError(t, err, "invalid FQL file queries/bad.fql: invalid placeholder in template at line 1, column 14:\nProduct.byId($id)\n             ^")
+
+	_, err = FQLFromFS(fsys, "queries/empty.fql", nil)
+	assert.EqualError(t, err, "FQL file queries/empty.fql is empty")
+
+	_, err = FQLFromFS(fsys, "queries/missing.fql", nil)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestFQLStrict(t *testing.T) {
+	q, err := FQLStrict(`${coll}.byId(${id})`, map[string]any{"coll": &Module{"Product"}, "id": "1"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "${...}.byId(${...})", q.String())
+	}
+
+	_, err = FQLStrict("let x = ${id}\n${coll}.byId(${id})", map[string]any{"name": "x", "after": "y"})
+	assert.EqualError(t, err, "template variables not found in args: id (positions 8, 27), coll (position 14); args not used in template: after, name")
+
+	_, err = FQLStrict(`${id}`, nil)
+	assert.EqualError(t, err, "template variables not found in args: id (position 0)")
+
+	_, err = FQLStrict(`1 + 1`, map[string]any{"x": 1})
+	assert.EqualError(t, err, "args not used in template: x")
+}
+
+func TestFQLf(t *testing.T) {
+	q, err := FQLf(`${0}.length + ${1} + ${0}.length`, "foo", 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Query{fragments: []*queryFragment{
+			{false, "foo"}, {true, ".length + "}, {false, 2}, {true, " + "}, {false, "foo"}, {true, ".length"},
+		}}, q)
+	}
+
+	q, err = FQLf(`Product.all()`)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Product.all()", q.String())
+	}
+
+	_, err = FQLf(`${0} + ${2}`, 1, 2)
+	assert.EqualError(t, err, "template variables not found in args: 2 (position 7); args not used in template: 1")
+
+	_, err = FQLf(`${name}`, "x")
+	assert.EqualError(t, err, "template variables not found in args: name (position 0); args not used in template: 0")
+}
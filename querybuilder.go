@@ -0,0 +1,134 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// QueryBuilder builds a [fauna.Query] statement by statement, as an
+// alternative to [fauna.FQL] templates that keeps values out of the FQL text:
+//
+//	qb := fauna.NewQueryBuilder()
+//	q, err := qb.
+//		Let("limit", limit).
+//		Append("Product.where(.category == " + qb.Arg(category) + ").take(limit)").
+//		Build()
+//
+// Statements are separated by newlines, and the last one is the value of the
+// query. A QueryBuilder is not safe for concurrent use.
+type QueryBuilder struct {
+	args       []*queryFragment
+	argCount   int
+	argNames   map[any]string
+	statements [][]*queryFragment
+	bound      map[string]bool
+	err        error
+}
+
+// NewQueryBuilder returns an empty [fauna.QueryBuilder].
+func NewQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{argNames: map[any]string{}, bound: map[string]bool{}}
+}
+
+// Arg binds value to a variable named argN at the start of the query, and
+// returns its name for use in statements. Passing the same string, number,
+// bool or pointer again returns the same name instead of binding it twice.
+// value can be any type [fauna.FQL] accepts as an argument, including a
+// [fauna.Query].
+func (qb *QueryBuilder) Arg(value any) string {
+	key, dedup := argKey(value)
+	if dedup {
+		if name, ok := qb.argNames[key]; ok {
+			return name
+		}
+	}
+
+	var name string
+	for name = fmt.Sprintf("arg%d", qb.argCount); qb.bound[name]; name = fmt.Sprintf("arg%d", qb.argCount) {
+		qb.argCount++
+	}
+	qb.argCount++
+	qb.bound[name] = true
+	if dedup {
+		qb.argNames[key] = name
+	}
+
+	qb.args = append(qb.args,
+		&queryFragment{true, "let " + name + " = "},
+		&queryFragment{false, value},
+		&queryFragment{true, "\n"},
+	)
+	return name
+}
+
+// Let appends a statement binding value to a variable called name. value is
+// passed as an argument like with [fauna.QueryBuilder.Arg], so pass a
+// [fauna.Query] to bind an FQL expression.
+func (qb *QueryBuilder) Let(name string, value any) *QueryBuilder {
+	if !fieldNameRegex.MatchString(name) {
+		return qb.fail(fmt.Errorf("invalid variable name %q", name))
+	}
+	if qb.bound[name] {
+		return qb.fail(fmt.Errorf("variable %q is already bound", name))
+	}
+	qb.bound[name] = true
+	qb.statements = append(qb.statements, []*queryFragment{{true, "let " + name + " = "}, {false, value}})
+	return qb
+}
+
+// Append appends fql as a statement. fql is taken as is, with no ${name}
+// placeholders; use [fauna.QueryBuilder.Arg] to refer to values.
+func (qb *QueryBuilder) Append(fql string) *QueryBuilder {
+	qb.statements = append(qb.statements, []*queryFragment{{true, fql}})
+	return qb
+}
+
+// Build returns the query, or the first error from building it. The last
+// statement must not be a [fauna.QueryBuilder.Let], as it would leave the
+// query without a value.
+func (qb *QueryBuilder) Build() (*Query, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
+	if len(qb.statements) == 0 {
+		return nil, errors.New("query has no statements")
+	}
+	if last := qb.statements[len(qb.statements)-1]; len(last) > 1 {
+		return nil, errors.New("query must end with an expression, not a let")
+	}
+
+	fragments := append([]*queryFragment{}, qb.args...)
+	for i, statement := range qb.statements {
+		if i > 0 {
+			fragments = append(fragments, &queryFragment{true, "\n"})
+		}
+		fragments = append(fragments, statement...)
+	}
+	return &Query{fragments: fragments}, nil
+}
+
+func (qb *QueryBuilder) fail(err error) *QueryBuilder {
+	if qb.err == nil {
+		qb.err = err
+	}
+	return qb
+}
+
+// argKey returns the key identifying value among arguments, if it is of a kind
+// that can be compared safely.
+func argKey(value any) (any, bool) {
+	if value == nil {
+		return nil, false
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Bool, reflect.String, reflect.Pointer,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return value, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,65 @@
+package fauna
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	t.Run("builds statements", func(t *testing.T) {
+		inner, err := FQL(`Product.all()`, nil)
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder()
+		q, err := qb.
+			Let("products", inner).
+			Let("limit", 10).
+			Append("products.where(.category == " + qb.Arg("tools") + ").take(limit)").
+			Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, "let arg0 = ${...}\nlet products = Product.all()\nlet limit = ${...}\nproducts.where(.category == arg0).take(limit)", q.String())
+
+		encoded, err := marshal(q)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"fql": [
+			"let arg0 = ", {"value": "tools"}, "\n",
+			"let products = ", {"fql": ["Product.all()"]},
+			"\n", "let limit = ", {"value": {"@int": "10"}},
+			"\n", "products.where(.category == arg0).take(limit)"
+		]}`, string(encoded))
+	})
+
+	t.Run("dedups repeated args", func(t *testing.T) {
+		qb := NewQueryBuilder()
+		a, b, c := qb.Arg("x"), qb.Arg(1), qb.Arg("x")
+		assert.Equal(t, "arg0", a)
+		assert.Equal(t, "arg1", b)
+		assert.Equal(t, a, c)
+
+		// slices can't be compared, so are bound every time
+		assert.NotEqual(t, qb.Arg([]int{1}), qb.Arg([]int{1}))
+	})
+
+	t.Run("skips bound names", func(t *testing.T) {
+		qb := NewQueryBuilder().Let("arg0", 1)
+		assert.Equal(t, "arg1", qb.Arg(2))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewQueryBuilder().Build()
+		assert.EqualError(t, err, "query has no statements")
+
+		_, err = NewQueryBuilder().Let("x", 1).Build()
+		assert.EqualError(t, err, "query must end with an expression, not a let")
+
+		_, err = NewQueryBuilder().Let("x y", 1).Append("1").Build()
+		assert.EqualError(t, err, `invalid variable name "x y"`)
+
+		qb := NewQueryBuilder()
+		_, err = qb.Let(qb.Arg(1), 2).Append("1").Build()
+		assert.EqualError(t, err, `variable "arg0" is already bound`)
+	})
+}
@@ -0,0 +1,56 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+)
+
+// NewRef returns a reference to the document with the given ID in the
+// collection coll.
+func NewRef(coll string, id string) *Ref {
+	return &Ref{ID: id, Coll: &Module{Name: coll}}
+}
+
+// NewNamedRef returns a reference to the named document, such as a
+// collection or function definition, in the collection coll.
+func NewNamedRef(coll string, name string) *NamedRef {
+	return &NamedRef{Name: name, Coll: &Module{Name: coll}}
+}
+
+// Load reads the referenced document with client and decodes it into into,
+// like [fauna.QuerySuccess.Unmarshal]. It fails if the document doesn't
+// exist.
+func (r Ref) Load(ctx context.Context, client *Client, into any) error {
+	if r.Coll == nil {
+		return errors.New("ref has no collection")
+	}
+
+	q, err := FQL(`${coll}.byId(${id})!`, map[string]any{"coll": r.Coll, "id": r.ID})
+	if err != nil {
+		return err
+	}
+	return load(ctx, client, q, into)
+}
+
+// Load reads the referenced document with client and decodes it into into,
+// like [fauna.QuerySuccess.Unmarshal]. It fails if the document doesn't
+// exist.
+func (r NamedRef) Load(ctx context.Context, client *Client, into any) error {
+	if r.Coll == nil {
+		return errors.New("ref has no collection")
+	}
+
+	q, err := FQL(`${coll}.byName(${name})!`, map[string]any{"coll": r.Coll, "name": r.Name})
+	if err != nil {
+		return err
+	}
+	return load(ctx, client, q, into)
+}
+
+func load(ctx context.Context, client *Client, q *Query, into any) error {
+	res, err := client.QueryWithContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	return res.Unmarshal(into)
+}
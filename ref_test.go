@@ -0,0 +1,54 @@
+package fauna_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefLoad(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query json.RawMessage `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, string(body.Query))
+
+		switch len(queries) {
+		case 1:
+			_, _ = w.Write([]byte(`{"data":{"@doc":{"id":"42","coll":{"@mod":"Product"},"ts":{"@time":"2024-01-01T00:00:00Z"},"name":"cup"}},"txn_ts":1,"stats":{}}`))
+		case 2:
+			_, _ = w.Write([]byte(`{"data":{"@doc":{"name":"Product","coll":{"@mod":"Collection"},"ts":{"@time":"2024-01-01T00:00:00Z"},"history_days":{"@int":"0"}}},"txn_ts":1,"stats":{}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"null_value","message":"Null value, due to document not found"},"txn_ts":1,"stats":{}}`))
+		}
+	})
+
+	var product struct {
+		fauna.Document
+		Name string `fauna:"name"`
+	}
+	require.NoError(t, fauna.NewRef("Product", "42").Load(context.Background(), client, &product))
+	require.Equal(t, "42", product.ID)
+	require.Equal(t, "cup", product.Name)
+	require.JSONEq(t, `{"fql":[{"value":{"@mod":"Product"}},".byId(",{"value":"42"},")!"]}`, queries[0])
+
+	var coll struct {
+		fauna.NamedDocument
+		HistoryDays int `fauna:"history_days"`
+	}
+	require.NoError(t, fauna.NewNamedRef("Collection", "Product").Load(context.Background(), client, &coll))
+	require.Equal(t, "Product", coll.Name)
+	require.JSONEq(t, `{"fql":[{"value":{"@mod":"Collection"}},".byName(",{"value":"Product"},")!"]}`, queries[1])
+
+	err := fauna.NewRef("Product", "43").Load(context.Background(), client, &product)
+	require.ErrorContains(t, err, "document not found")
+
+	require.ErrorContains(t, fauna.Ref{ID: "1"}.Load(context.Background(), client, &product), "no collection")
+}
@@ -9,17 +9,33 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type apiRequest struct {
 	Context context.Context
 	Headers map[string]string
+
+	// idempotent is set for requests that are safe to send again after a
+	// network error, as they don't write.
+	idempotent bool
 }
 
 func (apiReq *apiRequest) post(cli *Client, url *url.URL, bytesOut []byte) (attempts int, httpRes *http.Response, err error) {
+	if cli.configErr != nil {
+		err = fmt.Errorf("invalid client configuration: %w", cli.configErr)
+		return
+	}
+
+	var token string
+	if token, err = cli.token(apiReq.Context); err != nil {
+		err = fmt.Errorf("failed to get access token: %w", err)
+		return
+	}
+
 	var httpReq *http.Request
 	if httpReq, err = http.NewRequestWithContext(
-		apiReq.Context,
+		cli.sampleLogs(apiReq.Context, logComponentOf(url)),
 		http.MethodPost,
 		url.String(),
 		bytes.NewReader(bytesOut),
@@ -28,7 +44,7 @@ func (apiReq *apiRequest) post(cli *Client, url *url.URL, bytesOut []byte) (atte
 		return
 	}
 
-	httpReq.Header.Set(headerAuthorization, `Bearer `+cli.secret)
+	httpReq.Header.Set(headerAuthorization, `Bearer `+token)
 	if lastTxnTs := cli.lastTxnTime.string(); lastTxnTs != "" {
 		httpReq.Header.Set(HeaderLastTxnTs, lastTxnTs)
 	}
@@ -36,19 +52,91 @@ func (apiReq *apiRequest) post(cli *Client, url *url.URL, bytesOut []byte) (atte
 	for k, v := range apiReq.Headers {
 		httpReq.Header.Set(k, v)
 	}
+	if len(cli.tracePropagators) > 0 {
+		cli.propagateTrace(apiReq.Context, httpReq.Header)
+	}
+
+	release, err := cli.acquireRequestSlot(apiReq.Context)
+	if err != nil {
+		return
+	}
+	defer release()
 
-	if attempts, httpRes, err = cli.doWithRetry(httpReq); err != nil {
-		err = ErrNetwork(fmt.Errorf("network error: %w", err))
+	var probe bool
+	if cli.breaker != nil {
+		if probe, err = cli.breaker.allow(); err != nil {
+			return
+		}
+	}
+
+	attempts, httpRes, err = cli.doWithRetry(httpReq, bytesOut, apiReq.idempotent)
+	if cli.breaker != nil {
+		cli.breaker.record(apiReq.Context, probe, httpRes, err)
 	}
-	cli.logger.LogResponse(cli.ctx, bytesOut, httpRes)
+	if err != nil {
+		err = newErrNetwork(err)
+	}
+	cli.logResponse(logComponentOf(url), bytesOut, httpRes)
 
 	return
 }
 
+// propagateTrace sets the trace context headers of header from ctx with the
+// client's propagators, unless header already has a traceparent.
+func (c *Client) propagateTrace(ctx context.Context, header http.Header) {
+	if header.Get(HeaderTraceparent) != "" {
+		return
+	}
+
+	carrier := http.Header{}
+	for _, propagate := range c.tracePropagators {
+		propagate(ctx, carrier)
+	}
+
+	if traceparent := carrier.Get(HeaderTraceparent); traceparent != "" {
+		header.Set(HeaderTraceparent, traceparent)
+		if tracestate := carrier.Get(HeaderTracestate); tracestate != "" {
+			header.Set(HeaderTracestate, tracestate)
+		}
+	}
+}
+
+// idempotencyKeyVariable is the query variable set by [IdempotencyKey].
+const idempotencyKeyVariable = "idempotency_key"
+
 type queryRequest struct {
 	apiRequest
-	Query     any
-	Arguments map[string]any
+	Query          any
+	Arguments      map[string]any
+	streamResponse bool
+	decoder        *decoder
+	pageSize       int
+	projection     []string
+	tags           map[string]string
+	optionErr      error
+}
+
+// tagFingerprint adds the fingerprint of fql to the tags of the request,
+// unless they already have one or are full.
+func (qReq *queryRequest) tagFingerprint(fql *Query) {
+	if _, ok := qReq.tags[QueryFingerprintTag]; ok {
+		return
+	}
+	tags, err := mergeTags(qReq.tags, map[string]string{QueryFingerprintTag: fql.Fingerprint()})
+	if err != nil {
+		return
+	}
+	qReq.setTags(tags)
+}
+
+// setTags sets the tags of the request and its tags header.
+func (qReq *queryRequest) setTags(tags map[string]string) {
+	qReq.tags = tags
+	if len(tags) == 0 {
+		delete(qReq.Headers, HeaderTags)
+		return
+	}
+	qReq.Headers[HeaderTags] = formatTags(tags)
 }
 
 type queryResponse struct {
@@ -62,16 +150,76 @@ type queryResponse struct {
 	Summary       string          `json:"summary"`
 	TxnTime       int64           `json:"txn_ts"`
 	Tags          string          `json:"query_tags"`
+	RequestID     string          `json:"-"`
 }
 
+// parseQueryResponse reads the response into a pooled buffer. Unmarshaling
+// copies everything kept from it, including the raw data, so the buffer is
+// reused once it returns.
 func parseQueryResponse(httpRes *http.Response) (qRes *queryResponse, err error) {
-	var bytesIn []byte
-	if bytesIn, err = io.ReadAll(httpRes.Body); err != nil {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err = buf.ReadFrom(httpRes.Body); err != nil {
 		err = fmt.Errorf("failed to read response body: %w", err)
 		return
 	}
 
-	if err = json.Unmarshal(bytesIn, &qRes); err != nil {
+	if err = json.Unmarshal(buf.Bytes(), &qRes); err != nil {
+		err = fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return
+}
+
+// streamQueryResponse decodes the response while reading it, rather than
+// buffering the whole body first. The data is converted one value at a time
+// by [decoder.convertStream], so only the converted result and the raw form
+// of a single element are held in memory.
+func streamQueryResponse(httpRes *http.Response, dec decoder) (qRes *queryResponse, data any, err error) {
+	jd := dec.jsonDecoder(httpRes.Body)
+	if err = expectDelim(jd, '{'); err != nil {
+		err = fmt.Errorf("failed to unmarshal response: %w", err)
+		return
+	}
+
+	// everything but data is small, so it is decoded as usual afterwards
+	fields := map[string]json.RawMessage{}
+	for jd.More() {
+		var tok json.Token
+		if tok, err = jd.Token(); err != nil {
+			err = fmt.Errorf("failed to unmarshal response: %w", err)
+			return
+		}
+
+		key, _ := tok.(string)
+		if key == "data" {
+			if data, err = dec.convertStream(jd); err != nil {
+				err = fmt.Errorf("failed to decode data: %w", err)
+				return
+			}
+			continue
+		}
+
+		var raw json.RawMessage
+		if err = jd.Decode(&raw); err != nil {
+			err = fmt.Errorf("failed to unmarshal response: %w", err)
+			return
+		}
+		fields[key] = raw
+	}
+
+	if err = expectDelim(jd, '}'); err != nil {
+		err = fmt.Errorf("failed to unmarshal response: %w", err)
+		return
+	}
+
+	// read to EOF so the connection can be reused
+	_, _ = io.Copy(io.Discard, httpRes.Body)
+
+	var rest []byte
+	if rest, err = json.Marshal(fields); err != nil {
+		return
+	}
+	if err = json.Unmarshal(rest, &qRes); err != nil {
 		err = fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	return
@@ -82,8 +230,9 @@ func (r *queryResponse) queryTags() map[string]string {
 
 	if r.Tags != "" {
 		for _, tag := range strings.Split(r.Tags, `,`) {
-			tokens := strings.Split(tag, `=`)
-			ret[tokens[0]] = tokens[1]
+			if k, v, found := strings.Cut(tag, `=`); found {
+				ret[k] = v
+			}
 		}
 	}
 
@@ -91,12 +240,47 @@ func (r *queryResponse) queryTags() map[string]string {
 }
 
 func (qReq *queryRequest) do(cli *Client) (qSus *QuerySuccess, err error) {
+	if qReq.optionErr != nil {
+		err = fmt.Errorf("invalid query option: %w", qReq.optionErr)
+		return
+	}
+
+	if len(qReq.projection) > 0 {
+		if qReq.Query, err = project(qReq.Query, qReq.projection); err != nil {
+			err = fmt.Errorf("invalid query option: %w", err)
+			return
+		}
+	}
+
 	var bytesOut []byte
-	if bytesOut, err = marshal(qReq); err != nil {
+	if bytesOut, err = cli.encoder.marshal(qReq); err != nil {
 		err = fmt.Errorf("marshal request failed: %w", err)
 		return
 	}
 
+	dec := cli.decoder
+	if qReq.decoder != nil {
+		dec = *qReq.decoder
+	}
+	dec.client = cli
+
+	var cacheKey string
+	if cli.queryCache != nil && !qReq.streamResponse {
+		cacheKey = queryCacheKey(bytesOut, qReq.Headers)
+		if entry, found := cli.queryCache.get(cacheKey, cli.lastTxnTime.get()); found {
+			return qReq.success(entry.response(), nil, dec, entry.attempts)
+		}
+	}
+
+	requestID := qReq.Headers[HeaderRequestID]
+	if requestID == "" {
+		requestID = newRequestID()
+		qReq.Headers[HeaderRequestID] = requestID
+	}
+	if cli.onRequest != nil {
+		cli.onRequest(requestID, string(bytesOut))
+	}
+
 	var queryURL *url.URL
 	if queryURL, err = cli.parseQueryURL(); err != nil {
 		return
@@ -105,36 +289,71 @@ func (qReq *queryRequest) do(cli *Client) (qSus *QuerySuccess, err error) {
 	var (
 		attempts int
 		httpRes  *http.Response
+		start    = time.Now()
 	)
 	if attempts, httpRes, err = qReq.post(cli, queryURL, bytesOut); err != nil {
 		return
 	}
 
-	var qRes *queryResponse
-	if qRes, err = parseQueryResponse(httpRes); err != nil {
+	var (
+		qRes       *queryResponse
+		streamData any
+	)
+	if qReq.streamResponse {
+		qRes, streamData, err = streamQueryResponse(httpRes, dec)
+	} else {
+		qRes, err = parseQueryResponse(httpRes)
+	}
+	if err != nil {
 		return
 	}
-	cli.logger.LogResponse(cli.ctx, bytesOut, httpRes)
+	qRes.RequestID = requestID
+	cli.logResponse(LogComponentQuery, bytesOut, httpRes)
+	cli.logSlowQuery(time.Since(start), qRes)
 
 	cli.lastTxnTime.sync(qRes.TxnTime)
+	if old, changed := cli.lastSchemaVersion.advance(qRes.SchemaVersion); changed && old != 0 && cli.onSchemaVersionChange != nil {
+		cli.onSchemaVersionChange(old, qRes.SchemaVersion)
+	}
 	qRes.Header = httpRes.Header
 
 	if err = getErrFauna(httpRes.StatusCode, qRes, attempts); err != nil {
 		return
 	}
 
-	var data any
-	if data, err = decode(qRes.Data); err != nil {
-		err = fmt.Errorf("failed to decode data: %w", err)
+	if qSus, err = qReq.success(qRes, streamData, dec, attempts); err != nil {
 		return
 	}
 
+	if cacheKey != "" && qRes.Stats != nil && qRes.Stats.WriteOps == 0 {
+		cli.queryCache.put(cacheKey, qRes, attempts)
+	}
+	return
+}
+
+// success returns the result of a query with response qRes, decoding its data
+// unless the response was streamed and already decoded as streamData.
+func (qReq *queryRequest) success(qRes *queryResponse, streamData any, dec decoder, attempts int) (qSus *QuerySuccess, err error) {
+	data := streamData
+	var raw *json.RawMessage
+	if !qReq.streamResponse {
+		if data, err = dec.decode(qRes.Data); err != nil {
+			err = fmt.Errorf("failed to decode data: %w", err)
+			return
+		}
+		rawData := qRes.Data
+		raw = &rawData
+	}
+
 	qSus = &QuerySuccess{
 		QueryInfo:  newQueryInfo(qRes),
 		Data:       data,
 		StaticType: qRes.StaticType,
+		decoder:    &dec,
+		raw:        raw,
 	}
 	qSus.Stats.Attempts = attempts
+	qSus.IdempotencyKey, _ = qReq.Arguments[idempotencyKeyVariable].(string)
 	return
 }
 
@@ -143,11 +362,16 @@ type streamRequest struct {
 	Stream  EventSource
 	StartTS int64
 	Cursor  string
+
+	eventTypes  []EventType
+	idleTimeout time.Duration
+	decoder     *decoder
+	fields      []string
 }
 
 func (streamReq *streamRequest) do(cli *Client) (bytes io.ReadCloser, err error) {
 	var bytesOut []byte
-	if bytesOut, err = marshal(streamReq); err != nil {
+	if bytesOut, err = cli.encoder.marshal(streamReq); err != nil {
 		err = fmt.Errorf("marshal request failed: %w", err)
 		return
 	}
@@ -164,7 +388,7 @@ func (streamReq *streamRequest) do(cli *Client) (bytes io.ReadCloser, err error)
 	if attempts, httpRes, err = streamReq.post(cli, streamURL, bytesOut); err != nil {
 		return
 	}
-	cli.logger.LogResponse(cli.ctx, bytesOut, httpRes)
+	cli.logResponse(LogComponentStream, bytesOut, httpRes)
 
 	if httpRes.StatusCode != http.StatusOK {
 		var qRes *queryResponse
@@ -189,7 +413,7 @@ type feedRequest struct {
 }
 
 func (feedReq *feedRequest) do(cli *Client) (io.ReadCloser, error) {
-	bytesOut, marshalErr := marshal(feedReq)
+	bytesOut, marshalErr := cli.encoder.marshal(feedReq)
 	if marshalErr != nil {
 		return nil, fmt.Errorf("marshal request failed: %w", marshalErr)
 	}
@@ -0,0 +1,35 @@
+package fauna
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newRequestID returns a ULID: 48 bits of millisecond timestamp followed by
+// 80 random bits, encoded as 26 characters of Crockford base32. ULIDs sort by
+// the time they were generated, which keeps logged request IDs in order.
+func newRequestID() string {
+	var id [16]byte
+
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	_, _ = rand.Read(id[6:])
+
+	// 128 bits are encoded 5 at a time, most significant first, with the
+	// first character holding the top 3 bits.
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
@@ -0,0 +1,61 @@
+package fauna
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WithMaxConcurrentRequests limits the number of requests the [fauna.Client]
+// sends at once, including queries, streams, and feeds, to n. Requests over
+// the limit wait for one of the n slots, until their context is done or, if
+// set, the timeout of [WithRequestQueueTimeout]. A request holds its slot
+// while it is sent and retried, until its response starts, so streams don't
+// hold one while open.
+func WithMaxConcurrentRequests(n int) ClientConfigFn {
+	return func(c *Client) {
+		if n <= 0 {
+			c.setOptionErr(fmt.Errorf("max concurrent requests must be positive, got %d", n))
+			return
+		}
+		c.requestSlots = make(chan struct{}, n)
+	}
+}
+
+// WithRequestQueueTimeout sets how long a request waits for a slot when the
+// limit of [WithMaxConcurrentRequests] is reached, before failing with
+// [ErrRequestQueueTimeout]. By default, it waits until its context is done.
+func WithRequestQueueTimeout(timeout time.Duration) ClientConfigFn {
+	return func(c *Client) { c.requestQueueTimeout = timeout }
+}
+
+// acquireRequestSlot waits for a request slot, if the number of concurrent
+// requests is limited, and returns the function releasing it.
+func (c *Client) acquireRequestSlot(ctx context.Context) (release func(), err error) {
+	if c.requestSlots == nil {
+		return func() {}, nil
+	}
+
+	release = func() { <-c.requestSlots }
+	select {
+	case c.requestSlots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if c.requestQueueTimeout > 0 {
+		timer := time.NewTimer(c.requestQueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case c.requestSlots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timeout:
+		return nil, &ErrRequestQueueTimeout{Limit: cap(c.requestSlots), Timeout: c.requestQueueTimeout}
+	}
+}
@@ -0,0 +1,70 @@
+package fauna_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var (
+		inFlight, maxInFlight atomic.Int64
+		unblock               = make(chan struct{})
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		<-unblock
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.WithMaxConcurrentRequests(2), fauna.WithRequestQueueTimeout(20*time.Millisecond))
+
+	// resolve the query URL before querying concurrently
+	q, _ := fauna.FQL(`null`, nil)
+	close(unblock)
+	_, err := client.Query(q)
+	require.NoError(t, err)
+	unblock = make(chan struct{})
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := client.Query(q)
+			errs <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return inFlight.Load() == 2 }, time.Second, time.Millisecond)
+
+	_, err = client.Query(q)
+	var queueErr *fauna.ErrRequestQueueTimeout
+	require.ErrorAs(t, err, &queueErr)
+	require.EqualError(t, err, "request waited 20ms for one of 2 request slots")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.QueryWithContext(ctx, q)
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(unblock)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, <-errs)
+	}
+	_, err = client.Query(q)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), maxInFlight.Load())
+
+	client = fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.WithMaxConcurrentRequests(0))
+	_, err = client.Query(q)
+	require.ErrorContains(t, err, "max concurrent requests must be positive, got 0")
+}
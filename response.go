@@ -1,5 +1,13 @@
 package fauna
 
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Stats provides access to stats generated by the query.
 type Stats struct {
 	// ComputeOps is the amount of Transactional Compute Ops consumed by the query.
@@ -29,6 +37,93 @@ type Stats struct {
 
 	// Attempts is the number of times the client attempted to run the query.
 	Attempts int `json:"_"`
+
+	// rateLimitsHit holds the limits returned by RateLimitsHit, joined with
+	// commas so that Stats stays comparable.
+	rateLimitsHit string `fauna:"-"`
+}
+
+// RateLimitsHit lists the ops limits, such as "read", "write" or "compute",
+// the query was throttled on.
+func (s Stats) RateLimitsHit() []string {
+	if s.rateLimitsHit == "" {
+		return nil
+	}
+	return strings.Split(s.rateLimitsHit, ",")
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *Stats) UnmarshalJSON(data []byte) error {
+	type stats Stats
+	raw := struct {
+		*stats
+		RateLimitsHit []string `json:"rate_limits_hit"`
+	}{stats: (*stats)(s)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	s.rateLimitsHit = strings.Join(raw.RateLimitsHit, ",")
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (s Stats) MarshalJSON() ([]byte, error) {
+	type stats Stats
+	return json.Marshal(struct {
+		stats
+		RateLimitsHit []string `json:"rate_limits_hit,omitempty"`
+	}{stats(s), s.RateLimitsHit()})
+}
+
+// RateLimit holds the throttling information Fauna returned with a query.
+type RateLimit struct {
+	// LimitsHit lists the ops limits, such as "read", "write" or "compute",
+	// the query was throttled on.
+	LimitsHit []string
+
+	// RetryAfter is how long Fauna asked clients to wait before retrying, from
+	// the Retry-After header. It is zero if the header wasn't set.
+	RetryAfter time.Duration
+
+	// Limit is the number of requests allowed in the current window, from the
+	// X-RateLimit-Limit header, or -1 if the header wasn't set.
+	Limit int
+
+	// Remaining is the number of requests left in the current window, from the
+	// X-RateLimit-Remaining header, or -1 if the header wasn't set.
+	Remaining int
+}
+
+func newRateLimit(header http.Header, stats *Stats) *RateLimit {
+	limit := &RateLimit{
+		Limit:     headerInt(header, headerRateLimitLimit),
+		Remaining: headerInt(header, headerRateLimitRemaining),
+	}
+
+	if stats != nil {
+		limit.LimitsHit = stats.RateLimitsHit()
+	}
+
+	if retryAfter := header.Get(headerRetryAfter); retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil {
+			limit.RetryAfter = time.Duration(secs) * time.Second
+		} else if at, err := http.ParseTime(retryAfter); err == nil {
+			limit.RetryAfter = time.Until(at)
+		}
+	}
+
+	if len(limit.LimitsHit) == 0 && limit.RetryAfter <= 0 && limit.Limit < 0 && limit.Remaining < 0 {
+		return nil
+	}
+	return limit
+}
+
+func headerInt(header http.Header, key string) int {
+	if i, err := strconv.Atoi(header.Get(key)); err == nil {
+		return i
+	}
+	return -1
 }
 
 // QueryInfo provides access to information about the query.
@@ -51,6 +146,28 @@ type QueryInfo struct {
 
 	// Stats provides access to stats generated by the query.
 	Stats *Stats
+
+	// IdempotencyKey is the value of [fauna.IdempotencyKey] provided with the
+	// query, if any.
+	IdempotencyKey string
+
+	// RateLimit holds the throttling information returned with the query, if
+	// there was any.
+	RateLimit *RateLimit
+
+	// RequestID is the ULID sent in the X-Request-Id header of the query, or
+	// the value of [fauna.RequestID] if one was provided. Log it to correlate
+	// application logs with the request.
+	RequestID string
+
+	// Header holds the HTTP headers of the response, such as traffic hints,
+	// region identifiers and rate limit metadata.
+	Header http.Header
+}
+
+// Time returns the query's [fauna.QueryInfo.TxnTime] as a [time.Time] in UTC.
+func (q *QueryInfo) Time() time.Time {
+	return time.UnixMicro(q.TxnTime).UTC()
 }
 
 func newQueryInfo(res *queryResponse) *QueryInfo {
@@ -60,6 +177,9 @@ func newQueryInfo(res *queryResponse) *QueryInfo {
 		Summary:       res.Summary,
 		QueryTags:     res.queryTags(),
 		Stats:         res.Stats,
+		RateLimit:     newRateLimit(res.Header, res.Stats),
+		RequestID:     res.RequestID,
+		Header:        res.Header.Clone(),
 	}
 }
 
@@ -74,10 +194,27 @@ type QuerySuccess struct {
 	// StaticType is the query's inferred static result type, if the query was
 	// typechecked.
 	StaticType string
+
+	decoder *decoder
+
+	// raw is the JSON of Data as Fauna returned it, if Data was decoded
+	// from it. It is held by pointer so that results stay comparable.
+	raw *json.RawMessage
 }
 
 // Unmarshal will unmarshal the raw [fauna.QuerySuccess.Data] value into a
 // known type provided as `into`. `into` must be a pointer to a map or struct.
+//
+// Results are decoded into most types, such as structs of strings, numbers,
+// times and slices, straight from the response in a single pass, so changes
+// made to Data don't affect what Unmarshal decodes.
 func (r *QuerySuccess) Unmarshal(into any) error {
-	return decodeInto(r.Data, into)
+	var raw []byte
+	if r.raw != nil {
+		raw = *r.raw
+	}
+	if r.decoder != nil {
+		return r.decoder.decodeResult(raw, r.Data, into)
+	}
+	return decoder{}.decodeResult(raw, r.Data, into)
 }
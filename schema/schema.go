@@ -0,0 +1,414 @@
+// Package schema manages Fauna collections and user-defined functions
+// declaratively, building the FQL for Collection.create and Function.create
+// so infrastructure code doesn't have to.
+package schema
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fauna/fauna-go/v3"
+)
+
+// CollectionDef describes a collection.
+type CollectionDef struct {
+	Name        string
+	Indexes     map[string]IndexDef
+	Constraints []ConstraintDef
+
+	// HistoryDays is the number of days of document history to retain.
+	HistoryDays *int
+	// TTLDays is the number of days documents are retained.
+	TTLDays *int
+}
+
+// IndexDef describes an index on a collection.
+type IndexDef struct {
+	Terms  []Term  `fauna:"terms"`
+	Values []Value `fauna:"values"`
+}
+
+// Term is a field an index is searchable by.
+type Term struct {
+	Field string `fauna:"field"`
+	MVA   bool   `fauna:"mva"`
+}
+
+// Value is a field an index is sorted by.
+type Value struct {
+	Field string `fauna:"field"`
+	// Order is either "asc" or "desc". Defaults to "asc".
+	Order string `fauna:"order"`
+	MVA   bool   `fauna:"mva"`
+}
+
+// ConstraintDef describes a constraint on a collection. Set either Unique or
+// Check.
+type ConstraintDef struct {
+	// Unique lists the fields whose combined values must be unique.
+	Unique []string
+	// Check is a predicate documents must satisfy.
+	Check *CheckDef
+}
+
+// CheckDef describes a check constraint.
+type CheckDef struct {
+	Name string `fauna:"name"`
+	Body string `fauna:"body"`
+}
+
+// FunctionDef describes a user-defined function.
+type FunctionDef struct {
+	Name string `fauna:"name"`
+	Body string `fauna:"body"`
+
+	// Role is the role the function runs as, if any.
+	Role string `fauna:"role"`
+	// Signature is the function's type signature, if any.
+	Signature string `fauna:"signature"`
+}
+
+// Schema is a set of definitions to compare against a database with [Diff].
+type Schema struct {
+	Collections []CollectionDef
+	Functions   []FunctionDef
+}
+
+// ChangeKind is the kind of a [Change].
+type ChangeKind string
+
+const (
+	// ChangeCreate creates a definition missing from the database.
+	ChangeCreate ChangeKind = "create"
+	// ChangeUpdate updates a definition that differs from the database.
+	ChangeUpdate ChangeKind = "update"
+)
+
+// Change is a single schema change found by [Diff]. Exactly one of
+// Collection or Function is set.
+type Change struct {
+	Kind       ChangeKind
+	Collection *CollectionDef
+	Function   *FunctionDef
+}
+
+// String describes the change.
+func (c Change) String() string {
+	if c.Collection != nil {
+		return fmt.Sprintf("%s collection %s", c.Kind, c.Collection.Name)
+	}
+	return fmt.Sprintf("%s function %s", c.Kind, c.Function.Name)
+}
+
+// EnsureCollection creates the collection described by def, or updates it if
+// it already exists. Fields left unset in def are not changed.
+func EnsureCollection(ctx context.Context, client *fauna.Client, def CollectionDef) error {
+	return ensure(ctx, client, "Collection", def.Name, def.fql())
+}
+
+// EnsureFunction creates the function described by def, or updates it if it
+// already exists.
+func EnsureFunction(ctx context.Context, client *fauna.Client, def FunctionDef) error {
+	return ensure(ctx, client, "Function", def.Name, def.fql())
+}
+
+func ensure(ctx context.Context, client *fauna.Client, module string, name string, def map[string]any) error {
+	q, err := fauna.FQL(`if (${mod}.byName(${name}).exists()) {
+  ${mod}.byName(${name})!.update(${def})
+} else {
+  ${mod}.create(${def})
+}
+null`, map[string]any{"mod": &fauna.Module{Name: module}, "name": name, "def": def})
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Query(q, fauna.QueryContext(ctx)); err != nil {
+		return fmt.Errorf("failed to ensure %s %s: %w", strings.ToLower(module), name, err)
+	}
+	return nil
+}
+
+// Diff compares desired with the collections and functions in the database
+// and returns the changes needed to make the database match. Collections and
+// functions in the database but not in desired are ignored.
+func Diff(ctx context.Context, client *fauna.Client, desired Schema) ([]Change, error) {
+	q, err := fauna.FQL(`{
+  collections: Collection.all().toArray() { name, indexes, constraints, history_days, ttl_days },
+  functions: Function.all().toArray() { name, body, role, signature }
+}`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Query(q, fauna.QueryContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	var existing struct {
+		Collections []existingCollection `fauna:"collections"`
+		Functions   []FunctionDef        `fauna:"functions"`
+	}
+	if err := res.Unmarshal(&existing); err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+
+	collections := map[string]existingCollection{}
+	for _, c := range existing.Collections {
+		collections[c.Name] = c
+	}
+
+	functions := map[string]FunctionDef{}
+	for _, f := range existing.Functions {
+		functions[f.Name] = f
+	}
+
+	var changes []Change
+	for i := range desired.Collections {
+		def := &desired.Collections[i]
+		if current, ok := collections[def.Name]; !ok {
+			changes = append(changes, Change{Kind: ChangeCreate, Collection: def})
+		} else if !current.matches(def) {
+			changes = append(changes, Change{Kind: ChangeUpdate, Collection: def})
+		}
+	}
+
+	for i := range desired.Functions {
+		def := &desired.Functions[i]
+		if current, ok := functions[def.Name]; !ok {
+			changes = append(changes, Change{Kind: ChangeCreate, Function: def})
+		} else if current != *def {
+			changes = append(changes, Change{Kind: ChangeUpdate, Function: def})
+		}
+	}
+
+	return changes, nil
+}
+
+// Apply runs changes, such as those returned by [Diff], in a single
+// transaction.
+func Apply(ctx context.Context, client *fauna.Client, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var (
+		template strings.Builder
+		args     = map[string]any{}
+	)
+	for i, change := range changes {
+		q, err := change.query()
+		if err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("change%d", i)
+		args[name] = q
+		template.WriteString("${" + name + "}\n")
+	}
+	template.WriteString("null")
+
+	q, err := fauna.FQL(template.String(), args)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Query(q, fauna.QueryContext(ctx)); err != nil {
+		return fmt.Errorf("failed to apply schema changes: %w", err)
+	}
+	return nil
+}
+
+func (c Change) query() (*fauna.Query, error) {
+	var (
+		module string
+		name   string
+		def    map[string]any
+	)
+	switch {
+	case c.Collection != nil:
+		module, name, def = "Collection", c.Collection.Name, c.Collection.fql()
+	case c.Function != nil:
+		module, name, def = "Function", c.Function.Name, c.Function.fql()
+	default:
+		return nil, fmt.Errorf("change has no definition")
+	}
+
+	args := map[string]any{"mod": &fauna.Module{Name: module}, "name": name, "def": def}
+	switch c.Kind {
+	case ChangeCreate:
+		return fauna.FQL(`${mod}.create(${def})`, args)
+	case ChangeUpdate:
+		return fauna.FQL(`${mod}.byName(${name})!.update(${def})`, args)
+	default:
+		return nil, fmt.Errorf("unknown change kind %q", c.Kind)
+	}
+}
+
+func (d CollectionDef) fql() map[string]any {
+	out := map[string]any{"name": d.Name}
+
+	if len(d.Indexes) > 0 {
+		indexes := map[string]any{}
+		for name, idx := range d.Indexes {
+			indexes[name] = idx.fql()
+		}
+		out["indexes"] = indexes
+	}
+
+	if len(d.Constraints) > 0 {
+		constraints := make([]any, 0, len(d.Constraints))
+		for _, c := range d.Constraints {
+			constraints = append(constraints, c.fql())
+		}
+		out["constraints"] = constraints
+	}
+
+	if d.HistoryDays != nil {
+		out["history_days"] = *d.HistoryDays
+	}
+	if d.TTLDays != nil {
+		out["ttl_days"] = *d.TTLDays
+	}
+
+	return out
+}
+
+func (idx IndexDef) fql() map[string]any {
+	out := map[string]any{}
+
+	if len(idx.Terms) > 0 {
+		terms := make([]any, 0, len(idx.Terms))
+		for _, t := range idx.Terms {
+			terms = append(terms, map[string]any{"field": t.Field, "mva": t.MVA})
+		}
+		out["terms"] = terms
+	}
+
+	if len(idx.Values) > 0 {
+		values := make([]any, 0, len(idx.Values))
+		for _, v := range idx.Values {
+			values = append(values, map[string]any{"field": v.Field, "order": v.order(), "mva": v.MVA})
+		}
+		out["values"] = values
+	}
+
+	return out
+}
+
+func (v Value) order() string {
+	if v.Order == "" {
+		return "asc"
+	}
+	return v.Order
+}
+
+func (c ConstraintDef) fql() map[string]any {
+	if c.Check != nil {
+		return map[string]any{"check": map[string]any{"name": c.Check.Name, "body": c.Check.Body}}
+	}
+	return map[string]any{"unique": c.Unique}
+}
+
+func (d FunctionDef) fql() map[string]any {
+	out := map[string]any{"name": d.Name, "body": d.Body}
+	if d.Role != "" {
+		out["role"] = d.Role
+	}
+	if d.Signature != "" {
+		out["signature"] = d.Signature
+	}
+	return out
+}
+
+type existingCollection struct {
+	Name        string              `fauna:"name"`
+	Indexes     map[string]IndexDef `fauna:"indexes"`
+	Constraints []map[string]any    `fauna:"constraints"`
+	HistoryDays *int                `fauna:"history_days"`
+	TTLDays     *int                `fauna:"ttl_days"`
+}
+
+// matches reports whether the collection already satisfies the fields set in
+// def.
+func (c existingCollection) matches(def *CollectionDef) bool {
+	for name, want := range def.Indexes {
+		got, ok := c.Indexes[name]
+		if !ok || !reflect.DeepEqual(normalizeIndex(got), normalizeIndex(want)) {
+			return false
+		}
+	}
+
+	if len(def.Constraints) > 0 {
+		got := make([]string, 0, len(c.Constraints))
+		for _, constraint := range c.Constraints {
+			got = append(got, constraintKey(constraint))
+		}
+
+		want := make([]string, 0, len(def.Constraints))
+		for _, constraint := range def.Constraints {
+			want = append(want, constraintKey(constraint.fql()))
+		}
+
+		sort.Strings(got)
+		sort.Strings(want)
+		if !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+
+	if def.HistoryDays != nil && (c.HistoryDays == nil || *c.HistoryDays != *def.HistoryDays) {
+		return false
+	}
+
+	return def.TTLDays == nil || (c.TTLDays != nil && *c.TTLDays == *def.TTLDays)
+}
+
+func normalizeIndex(idx IndexDef) IndexDef {
+	out := IndexDef{Terms: []Term{}, Values: []Value{}}
+	for _, t := range idx.Terms {
+		out.Terms = append(out.Terms, Term{Field: normalizeField(t.Field), MVA: t.MVA})
+	}
+	for _, v := range idx.Values {
+		out.Values = append(out.Values, Value{Field: normalizeField(v.Field), Order: v.order(), MVA: v.MVA})
+	}
+	return out
+}
+
+// normalizeField accepts both the "name" and ".name" forms of a field path.
+func normalizeField(field string) string {
+	if strings.HasPrefix(field, ".") {
+		return field
+	}
+	return "." + field
+}
+
+// constraintKey renders a constraint in a form comparable across the shapes
+// accepted and returned by Fauna.
+func constraintKey(constraint map[string]any) string {
+	if check, ok := constraint["check"].(map[string]any); ok {
+		return fmt.Sprintf("check:%v:%v", check["name"], check["body"])
+	}
+
+	var fields []string
+	switch unique := constraint["unique"].(type) {
+	case []string:
+		for _, f := range unique {
+			fields = append(fields, normalizeField(f))
+		}
+	case []any:
+		for _, f := range unique {
+			switch ft := f.(type) {
+			case string:
+				fields = append(fields, normalizeField(ft))
+			case map[string]any:
+				fields = append(fields, normalizeField(fmt.Sprint(ft["field"])))
+			}
+		}
+	}
+	return "unique:" + strings.Join(fields, ",")
+}
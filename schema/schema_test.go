@@ -0,0 +1,115 @@
+package schema_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/fauna/fauna-go/v3/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureCollection(t *testing.T) {
+	var sent string
+	client := newTestClient(t, func(body string) string {
+		sent = body
+		return `null`
+	})
+
+	historyDays := 3
+	err := schema.EnsureCollection(context.Background(), client, schema.CollectionDef{
+		Name: "Customer",
+		Indexes: map[string]schema.IndexDef{
+			"byEmail": {Terms: []schema.Term{{Field: ".email"}}},
+		},
+		Constraints: []schema.ConstraintDef{{Unique: []string{"email"}}},
+		HistoryDays: &historyDays,
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, sent, `.byName(`)
+	require.Contains(t, sent, `{"@mod":"Collection"}`)
+	require.Contains(t, sent, `"history_days":{"@int":"3"}`)
+	require.Contains(t, sent, `"terms":[{"field":".email","mva":false}]`)
+	require.Contains(t, sent, `"constraints":[{"unique":["email"]}]`)
+}
+
+func TestDiffAndApply(t *testing.T) {
+	var applied string
+	client := newTestClient(t, func(body string) string {
+		if strings.Contains(body, "Collection.all()") {
+			return `{
+				"collections": [
+					{"name": "Customer", "indexes": {"byEmail": {"terms": [{"field": ".email", "mva": false}], "queryable": true}}, "constraints": [{"unique": [{"field": ".email", "mva": false}], "status": "active"}]},
+					{"name": "Order", "indexes": {}}
+				],
+				"functions": [
+					{"name": "double", "body": "x => x * 2"}
+				]
+			}`
+		}
+		applied = body
+		return `null`
+	})
+
+	desired := schema.Schema{
+		Collections: []schema.CollectionDef{
+			{
+				Name:        "Customer",
+				Indexes:     map[string]schema.IndexDef{"byEmail": {Terms: []schema.Term{{Field: "email"}}}},
+				Constraints: []schema.ConstraintDef{{Unique: []string{"email"}}},
+			},
+			{
+				Name:    "Order",
+				Indexes: map[string]schema.IndexDef{"byCustomer": {Terms: []schema.Term{{Field: ".customer"}}}},
+			},
+			{Name: "Product"},
+		},
+		Functions: []schema.FunctionDef{
+			{Name: "double", Body: "x => x * 2"},
+			{Name: "triple", Body: "x => x * 3"},
+		},
+	}
+
+	changes, err := schema.Diff(context.Background(), client, desired)
+	require.NoError(t, err)
+
+	var described []string
+	for _, c := range changes {
+		described = append(described, c.String())
+	}
+	require.Equal(t, []string{
+		"update collection Order",
+		"create collection Product",
+		"create function triple",
+	}, described)
+
+	require.NoError(t, schema.Apply(context.Background(), client, changes))
+	require.Contains(t, applied, `.byName(`)
+	require.Contains(t, applied, `.create(`)
+}
+
+// newTestClient returns a client whose requests are answered with the data
+// returned by respond, given the request body.
+func newTestClient(t *testing.T, respond func(body string) string) *fauna.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		data := json.RawMessage(respond(string(body)))
+		res, err := json.Marshal(map[string]any{"data": data, "txn_ts": 1, "stats": map[string]any{}})
+		require.NoError(t, err)
+
+		_, _ = w.Write(res)
+	}))
+	t.Cleanup(server.Close)
+
+	return fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(server.URL))
+}
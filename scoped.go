@@ -0,0 +1,80 @@
+package fauna
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// scopeRoleDefault is the role of secrets scoped from unscoped secrets.
+const scopeRoleDefault = "admin"
+
+// ScopedTo returns a client for the child database of the client's database
+// at path, such as "tenant_1" or "tenant_1/staging", sharing the connections
+// of c. It authenticates with the client's secret scoped to the database with
+// the "secret:database:role" convention, keeping the role of a secret that is
+// already scoped, or the admin role otherwise. Tokens of an
+// [AccessTokenProvider] are scoped as they are provided.
+func (c *Client) ScopedTo(database string) *Client {
+	return c.scoped(database, "")
+}
+
+// ScopedToRole is like [fauna.Client.ScopedTo], authenticating with role,
+// such as "server", "server-readonly", or the name of a user-defined role.
+func (c *Client) ScopedToRole(database, role string) *Client {
+	return c.scoped(database, role)
+}
+
+func (c *Client) scoped(database, role string) *Client {
+	client := c.derive(scopeSecret(c.secret, database, role))
+	if c.tokenProvider != nil {
+		provider := c.tokenProvider
+		client.tokenProvider = AccessTokenProviderFunc(func(ctx context.Context) (string, error) {
+			token, err := provider.Token(ctx)
+			if err != nil {
+				return "", err
+			}
+			return scopeSecret(token, database, role), nil
+		})
+	}
+
+	if err := validateScope(database, role); err != nil && client.configErr == nil {
+		client.configErr = err
+	}
+	return client
+}
+
+// scopeSecret scopes secret to the child database at path, with role, or the
+// role of secret if it is already scoped and role is empty.
+func scopeSecret(secret, database, role string) string {
+	key, parent, parentRole := secret, "", ""
+	if parts := strings.SplitN(secret, ":", 3); len(parts) == 3 {
+		key, parent, parentRole = parts[0], parts[1], parts[2]
+	}
+
+	if parent != "" {
+		database = parent + "/" + database
+	}
+	if role == "" {
+		role = parentRole
+	}
+	if role == "" {
+		role = scopeRoleDefault
+	}
+	return key + ":" + database + ":" + role
+}
+
+func validateScope(database, role string) error {
+	if database == "" || strings.Contains(database, ":") {
+		return fmt.Errorf("invalid database path %q", database)
+	}
+	for _, name := range strings.Split(database, "/") {
+		if name == "" {
+			return fmt.Errorf("invalid database path %q", database)
+		}
+	}
+	if strings.Contains(role, ":") {
+		return fmt.Errorf("invalid role %q", role)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package fauna_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopedTo(t *testing.T) {
+	var secrets []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		secrets = append(secrets, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	})
+	q, _ := fauna.FQL(`null`, nil)
+
+	tenant := client.ScopedTo("tenant_1")
+	for _, scoped := range []*fauna.Client{
+		tenant,
+		tenant.ScopedTo("staging"),
+		client.ScopedToRole("tenant_1", "server-readonly"),
+		tenant.ScopedToRole("staging", "@role/reader"),
+	} {
+		_, err := scoped.Query(q)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{
+		"secret:tenant_1:admin",
+		"secret:tenant_1/staging:admin",
+		"secret:tenant_1:server-readonly",
+		"secret:tenant_1/staging:@role/reader",
+	}, secrets)
+	require.Equal(t, int64(1), tenant.Stats().OpenConnections, "connections are shared")
+
+	secrets = nil
+	provided := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		secrets = append(secrets, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.WithTokenProvider(fauna.AccessTokenProviderFunc(func(context.Context) (string, error) {
+		return "token", nil
+	})))
+	_, err := provided.ScopedToRole("tenant_2", "server").Query(q)
+	require.NoError(t, err)
+	require.Equal(t, []string{"token:tenant_2:server"}, secrets)
+
+	for _, database := range []string{"", "a:b", "tenant_1/", "/tenant_1"} {
+		_, err := client.ScopedTo(database).Query(q)
+		require.ErrorContains(t, err, "invalid database path")
+	}
+	_, err = client.ScopedToRole("tenant_1", "a:b").Query(q)
+	require.ErrorContains(t, err, `invalid role "a:b"`)
+}
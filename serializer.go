@@ -1,13 +1,20 @@
 package fauna
 
 import (
+	"bytes"
+	"encoding"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -93,57 +100,347 @@ type NamedRef struct {
 type Page struct {
 	Data  []any  `fauna:"data"`
 	After string `fauna:"after"`
+
+	decoder *decoder   `fauna:"-"`
+	info    *QueryInfo `fauna:"-"`
 }
 
 func (p Page) Unmarshal(into any) error {
+	if p.decoder != nil {
+		return p.decoder.decodeInto(p.Data, into)
+	}
 	return decodeInto(p.Data, into)
 }
 
 type EventSource string
 
+// Coercion describes a value converted by [fauna.UnmarshalLenient] to fit
+// its destination.
+type Coercion struct {
+	// From is the Go type of the decoded Fauna value.
+	From string
+	// To is the type of the destination.
+	To string
+	// Value is the decoded Fauna value before conversion.
+	Value any
+}
+
+// NumberMode controls how numbers without a Fauna type tag, such as those
+// nested in an @object, are decoded into interface values.
+type NumberMode int
+
+const (
+	// NumberModeFloat64 decodes untagged numbers as float64, which loses
+	// precision for integers beyond 2^53. This is the default.
+	NumberModeFloat64 NumberMode = iota
+	// NumberModeJSONNumber decodes untagged numbers as [json.Number].
+	NumberModeJSONNumber
+	// NumberModeInt64 decodes untagged integral numbers that fit in an int64
+	// as int64, and all other numbers as float64.
+	NumberModeInt64
+)
+
+// NamingConvention maps the name of a struct field without a fauna tag, or
+// the tag's name, to the name of the Fauna field it is decoded from.
+type NamingConvention func(name string) string
+
+// SnakeCase is a [NamingConvention] mapping field names such as CreatedAt to
+// created_at.
+func SnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) && runes[i-1] != '_' {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// DecodeOptions controls how Fauna values are decoded into Go values. Set the
+// defaults for a [fauna.Client] with [fauna.DefaultDecodeOptions], and
+// override them for a single query with [fauna.WithDecodeOptions]. They apply
+// to query results, stream and feed events, and pages within them.
+type DecodeOptions struct {
+	// Strict fails decoding when an object has fields with no matching struct
+	// field. Documents decoded into structs must then have fields for their
+	// metadata, such as id, coll and ts.
+	//
+	// Whether or not decoding is strict, unmatched fields can be kept in a
+	// map field tagged with the remain hint, e.g.
+	// `fauna:",remain"`. Its entries are encoded back as fields of the
+	// object.
+	Strict bool
+
+	// NamingConvention, if set, matches struct fields to Fauna fields by the
+	// name it returns. Fields are always matched case-insensitively by their
+	// Go name or fauna tag too.
+	NamingConvention NamingConvention
+
+	// Lenient converts common mismatches between a value and its destination,
+	// as [fauna.UnmarshalLenient] does, instead of failing.
+	Lenient bool
+
+	// Location, if set, is the time zone decoded times are converted to. By
+	// default, times keep the offset Fauna sent them with, which is UTC.
+	//
+	// Struct fields tagged with the timelocal hint, e.g.
+	// `fauna:"created_at,timelocal"`, are converted to Location, or to
+	// [time.Local] if it isn't set, even if other times aren't.
+	Location *time.Location
+
+	// Numbers sets how untagged numbers are decoded into interface values.
+	Numbers NumberMode
+}
+
+func (o DecodeOptions) isZero() bool {
+	return !o.Strict && o.NamingConvention == nil && !o.Lenient && o.Location == nil && o.Numbers == NumberModeFloat64
+}
+
+// decoder holds the options used to decode Fauna values into Go values.
+type decoder struct {
+	opts      DecodeOptions
+	coercions *[]Coercion
+
+	// client is the client whose query results are decoded, if any, which
+	// decoded pages fetch their next page with.
+	client *Client
+}
+
 func mapDecoder(into any) (*mapstructure.Decoder, error) {
+	return decoder{}.mapDecoder(into)
+}
+
+func (d decoder) mapDecoder(into any) (*mapstructure.Decoder, error) {
+	hooks := []mapstructure.DecodeHookFuncType{d.unmarshalDoc, d.decodeSetCursor}
+	if !d.opts.isZero() || d.client != nil {
+		hooks = append(hooks, d.attachPage)
+	}
+	if d.opts.Lenient {
+		hooks = append(hooks, d.coerce)
+	}
+	if d.opts.Location != nil {
+		hooks = append(hooks, d.inLocation)
+	}
+	hooks = append(hooks, unmarshalMapKeys, unmarshalText, unmarshalNumeric)
+
+	var matchName func(mapKey, fieldName string) bool
+	if naming := d.opts.NamingConvention; naming != nil {
+		matchName = func(mapKey, fieldName string) bool {
+			return mapKey == naming(fieldName) || strings.EqualFold(mapKey, fieldName)
+		}
+	}
+
 	return mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		TagName:              "fauna",
 		Result:               into,
 		IgnoreUntaggedFields: false,
-		ErrorUnused:          false,
+		ErrorUnused:          d.opts.Strict,
 		ErrorUnset:           false,
-		DecodeHook:           unmarshalDoc,
+		DecodeHook:           composeHooks(hooks),
 		Squash:               true,
+		MatchName:            matchName,
 	})
 }
 
+// composeHooks returns a hook calling hooks in order, each with the result of
+// the previous one. Unlike [mapstructure.ComposeDecodeHookFunc], which
+// converts each hook to its signature with reflection on every call, it calls
+// them directly, as mapstructure runs hooks for every value it decodes.
+func composeHooks(hooks []mapstructure.DecodeHookFuncType) mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		var err error
+		for _, hook := range hooks {
+			if data == nil {
+				break
+			}
+			if data, err = hook(f, t, data); err != nil {
+				return nil, err
+			}
+			f = reflect.TypeOf(data)
+		}
+		return data, nil
+	}
+}
+
 func unmarshal(body []byte, into any) error {
-	decBody, err := decode(body)
+	return decoder{}.unmarshal(body, into)
+}
+
+func (d decoder) unmarshal(body []byte, into any) error {
+	decBody, err := d.decode(body)
 	if err != nil {
 		return err
 	}
-	return decodeInto(decBody, into)
+	return d.decodeInto(decBody, into)
 }
 
 func decodeInto(body any, into any) error {
-	dec, err := mapDecoder(into)
+	return decoder{}.decodeInto(body, into)
+}
+
+func (d decoder) decodeInto(body any, into any) error {
+	dec, err := d.mapDecoder(into)
 	if err != nil {
 		return err
 	}
 
-	return dec.Decode(body)
+	if err := dec.Decode(body); err != nil {
+		return err
+	}
+
+	d.localizeTimes(reflect.ValueOf(into))
+	return nil
+}
+
+// localizeTimes converts the times of target's struct fields tagged with
+// timeLocalHint into the configured time zone, or the local one.
+func (d decoder) localizeTimes(target reflect.Value) {
+	if target.IsValid() && hasTimeLocal(target.Type()) {
+		loc := d.opts.Location
+		if loc == nil {
+			loc = time.Local
+		}
+		localizeTimes(target, loc, false)
+	}
+}
+
+// UnmarshalLenient decodes value, such as [fauna.QuerySuccess.Data] or
+// [fauna.Event.Data], into `into`. Unlike Unmarshal, common mismatches between
+// the stored value and the destination type are converted rather than
+// reported as errors: numbers and strings are converted into each other,
+// fractional numbers are truncated into integers, and scalars found where an
+// object is expected are left as the zero value. The conversions performed
+// are returned so they can be reviewed.
+func UnmarshalLenient(value any, into any) ([]Coercion, error) {
+	coercions := []Coercion{}
+	err := decoder{opts: DecodeOptions{Lenient: true}, coercions: &coercions}.decodeInto(value, into)
+	return coercions, err
 }
 
 var (
 	docType      = reflect.TypeOf(&Document{})
 	namedDocType = reflect.TypeOf(&NamedDocument{})
+	pageType     = reflect.TypeOf(Page{})
+	timePtrType  = reflect.TypeOf(&time.Time{})
 )
 
-func unmarshalDoc(f reflect.Type, t reflect.Type, data any) (any, error) {
+// attachPage makes pages decoded by d decode their own data with the same
+// options, and fetch their next page with the same client.
+func (d decoder) attachPage(f reflect.Type, t reflect.Type, data any) (any, error) {
+	switch {
+	case f == pageType:
+		page := data.(Page)
+		page.decoder = &d
+		return page, nil
+	case f == reflect.PtrTo(pageType) && data.(*Page) != nil:
+		page := *data.(*Page)
+		page.decoder = &d
+		return &page, nil
+	}
+	return data, nil
+}
+
+// timeLocalHint is the fauna tag hint marking time fields decoded into a
+// local time zone.
+const timeLocalHint = "timelocal"
+
+// timeLocalTypes caches whether a type holds fields with timeLocalHint.
+var timeLocalTypes sync.Map
+
+func hasTimeLocal(t reflect.Type) bool {
+	if has, ok := timeLocalTypes.Load(t); ok {
+		return has.(bool)
+	}
+
+	has := findTimeLocal(t, map[reflect.Type]bool{})
+	timeLocalTypes.Store(t, has)
+	return has
+}
+
+// findTimeLocal reports whether t holds fields with timeLocalHint, skipping
+// the types in seen so that recursive types terminate.
+func findTimeLocal(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return findTimeLocal(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.IsExported() && (isTimeLocal(field) || findTimeLocal(field.Type, seen)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isTimeLocal(field reflect.StructField) bool {
+	tags := strings.Split(field.Tag.Get(fieldTag), ",")
+	return len(tags) > 1 && tags[1] == timeLocalHint
+}
+
+// localizeTimes converts the times held by v into loc, if local is set, or
+// by its struct fields tagged with timeLocalHint. Maps are left as they are,
+// as their values can't be set in place.
+func localizeTimes(v reflect.Value, loc *time.Location, local bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			localizeTimes(v.Elem(), loc, local)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			localizeTimes(v.Index(i), loc, local)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if local && v.CanSet() {
+				v.Set(reflect.ValueOf(v.Interface().(time.Time).In(loc)))
+			}
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				localizeTimes(v.Field(i), loc, isTimeLocal(field))
+			}
+		}
+	}
+}
+
+// inLocation converts times into the configured time zone.
+func (d decoder) inLocation(f reflect.Type, _ reflect.Type, data any) (any, error) {
+	switch {
+	case f == timeType:
+		return data.(time.Time).In(d.opts.Location), nil
+	case f == timePtrType && data.(*time.Time) != nil:
+		ts := data.(*time.Time).In(d.opts.Location)
+		return &ts, nil
+	}
+	return data, nil
+}
+
+func (d decoder) unmarshalDoc(f reflect.Type, t reflect.Type, data any) (any, error) {
 	if f != docType && f != namedDocType {
 		return data, nil
 	}
 
-	var docData map[string]any
+	docData := map[string]any{}
 	if f == docType {
 		doc := data.(*Document)
-		docData = doc.Data
+		for k, v := range doc.Data {
+			docData[k] = v
+		}
 		docData["id"] = doc.ID
 		docData["coll"] = doc.Coll
 		docData["ts"] = doc.TS
@@ -151,14 +448,16 @@ func unmarshalDoc(f reflect.Type, t reflect.Type, data any) (any, error) {
 
 	if f == namedDocType {
 		doc := data.(*NamedDocument)
-		docData = doc.Data
+		for k, v := range doc.Data {
+			docData[k] = v
+		}
 		docData["name"] = doc.Name
 		docData["coll"] = doc.Coll
 		docData["ts"] = doc.TS
 	}
 
 	result := reflect.New(t).Interface()
-	dec, err := mapDecoder(result)
+	dec, err := d.mapDecoder(result)
 	if err != nil {
 		return nil, err
 	}
@@ -170,15 +469,429 @@ func unmarshalDoc(f reflect.Type, t reflect.Type, data any) (any, error) {
 	return result, nil
 }
 
+var (
+	bigIntType = reflect.TypeOf(big.Int{})
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// coerce converts data into the kind expected by t when lenient decoding is
+// enabled, recording each conversion.
+func (d decoder) coerce(f reflect.Type, t reflect.Type, data any) (any, error) {
+	if data == nil {
+		return data, nil
+	}
+
+	var coerced any
+	switch t.Kind() {
+	case reflect.String:
+		switch v := data.(type) {
+		case int64:
+			coerced = strconv.FormatInt(v, 10)
+		case float64:
+			coerced = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			coerced = strconv.FormatBool(v)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v := data.(type) {
+		case string:
+			if i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+				coerced = i
+			} else if fl, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				coerced = int64(fl)
+			}
+		case float64:
+			if v != math.Trunc(v) {
+				coerced = int64(v)
+			}
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if v, ok := data.(string); ok {
+			if fl, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				coerced = fl
+			}
+		}
+
+	case reflect.Bool:
+		switch v := data.(type) {
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				coerced = b
+			}
+		case int64:
+			coerced = v != 0
+		}
+
+	case reflect.Struct, reflect.Map:
+		if t == bigIntType {
+			break
+		}
+
+		if v, ok := data.(string); ok && t == timeType {
+			if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				coerced = ts
+			}
+			break
+		}
+
+		switch data.(type) {
+		case string, int64, float64, bool:
+			coerced = reflect.Zero(t).Interface()
+		}
+	}
+
+	if coerced == nil {
+		return data, nil
+	}
+
+	if d.coercions != nil {
+		*d.coercions = append(*d.coercions, Coercion{From: f.String(), To: t.String(), Value: data})
+	}
+	return coerced, nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// unmarshalMapKeys parses the keys of Fauna objects decoded into maps keyed by
+// integers or types implementing [encoding.TextUnmarshaler].
+func unmarshalMapKeys(f reflect.Type, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.Map || t.Kind() != reflect.Map || f.Key().Kind() != reflect.String {
+		return data, nil
+	}
+
+	kt := t.Key()
+	if kt.Kind() == reflect.String && !reflect.PointerTo(kt).Implements(textUnmarshalerType) {
+		return data, nil
+	}
+
+	from := reflect.ValueOf(data)
+	out := reflect.MakeMapWithSize(reflect.MapOf(kt, f.Elem()), from.Len())
+	for iter := from.MapRange(); iter.Next(); {
+		key, err := parseMapKey(iter.Key().String(), kt)
+		if err != nil {
+			return nil, err
+		}
+		out.SetMapIndex(key, iter.Value())
+	}
+	return out.Interface(), nil
+}
+
+// unmarshalText decodes strings into types implementing
+// [encoding.TextUnmarshaler], such as UUIDs and enums. Times and big integers
+// are decoded from their Fauna types instead.
+func unmarshalText(f reflect.Type, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String || t == timeType || t == bigIntType || !reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return data, nil
+	}
+
+	v := reflect.New(t)
+	if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(reflect.ValueOf(data).String())); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %q into %s: %w", data, t, err)
+	}
+	return v.Elem().Interface(), nil
+}
+
+func parseMapKey(s string, kt reflect.Type) (reflect.Value, error) {
+	key := reflect.New(kt)
+	if u, ok := key.Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid map key %q for %s: %w", s, kt, err)
+		}
+		return key.Elem(), nil
+	}
+
+	switch kt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, kt.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid map key %q for %s: %w", s, kt, err)
+		}
+		key.Elem().SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, kt.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid map key %q for %s: %w", s, kt, err)
+		}
+		key.Elem().SetUint(u)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported map key type %s, keys must be strings, integers or implement encoding.TextUnmarshaler", kt)
+	}
+	return key.Elem(), nil
+}
+
+// unmarshalNumeric validates that integers fit in their destination and
+// converts values produced by a [NumericOverflowStrategy] back into unsigned
+// and big integers.
+func unmarshalNumeric(_ reflect.Type, t reflect.Type, data any) (any, error) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := data.(int64); ok && reflect.Zero(t).OverflowInt(i) {
+			return nil, fmt.Errorf("value %d overflows %s", i, t)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch v := data.(type) {
+		case int64:
+			if v < 0 || reflect.Zero(t).OverflowUint(uint64(v)) {
+				return nil, fmt.Errorf("value %d overflows %s", v, t)
+			}
+		case float64:
+			if v < 0 || v != math.Trunc(v) || v >= math.Ldexp(1, t.Bits()) {
+				return nil, fmt.Errorf("value %v overflows %s", v, t)
+			}
+			return uint64(v), nil
+		case string:
+			if u, err := strconv.ParseUint(v, 10, t.Bits()); err != nil {
+				return nil, fmt.Errorf("value %q overflows %s", v, t)
+			} else {
+				return u, nil
+			}
+		}
+
+	case reflect.Struct:
+		if t != bigIntType {
+			return data, nil
+		}
+
+		switch v := data.(type) {
+		case int64:
+			return big.NewInt(v), nil
+		case float64:
+			if i, accuracy := big.NewFloat(v).Int(nil); accuracy == big.Exact {
+				return i, nil
+			}
+			return nil, fmt.Errorf("value %v is not an integer", v)
+		case string:
+			if i, ok := new(big.Int).SetString(v, 10); ok {
+				return i, nil
+			}
+			return nil, fmt.Errorf("value %q is not an integer", v)
+		}
+	}
+
+	return data, nil
+}
+
 func decode(bodyBytes []byte) (any, error) {
+	return decoder{}.decode(bodyBytes)
+}
+
+func (d decoder) decode(bodyBytes []byte) (any, error) {
 	var body any
-	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+	if err := d.jsonDecoder(bytes.NewReader(bodyBytes)).Decode(&body); err != nil {
 		return nil, err
 	}
 
+	return d.convert(body)
+}
+
+// jsonDecoder returns a [json.Decoder] that keeps untagged numbers as
+// [json.Number] when needed by the decoder's [NumberMode].
+func (d decoder) jsonDecoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if d.opts.Numbers != NumberModeFloat64 {
+		dec.UseNumber()
+	}
+	return dec
+}
+
+// convert unboxes tagged values in body, which was decoded with the
+// decoder's jsonDecoder.
+func (d decoder) convert(body any) (any, error) {
+	if d.opts.Numbers == NumberModeInt64 {
+		body = intNumbers(body)
+	}
 	return convert(false, body)
 }
 
+// convertStream reads the next value from jd, which must come from the
+// decoder's jsonDecoder, and unboxes it like convert. Arrays, objects and
+// sets are read one element at a time, so only the raw form of a single
+// element, such as a document, is held in memory at once.
+func (d decoder) convertStream(jd *json.Decoder) (any, error) {
+	tok, err := jd.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('['):
+		items := []any{}
+		for jd.More() {
+			item, err := d.convertStream(jd)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, expectDelim(jd, ']')
+
+	case json.Delim('{'):
+		return d.convertStreamObject(jd)
+
+	default:
+		if d.opts.Numbers == NumberModeInt64 {
+			return intNumbers(tok), nil
+		}
+		return tok, nil
+	}
+}
+
+// convertStreamObject reads the rest of an object whose opening brace was
+// read by convertStream.
+func (d decoder) convertStreamObject(jd *json.Decoder) (any, error) {
+	if !jd.More() {
+		return map[string]any{}, expectDelim(jd, '}')
+	}
+
+	key, err := streamKey(jd)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	tag := typeTag(key)
+	_, hooked := decodeHook(tag)
+
+	switch {
+	case tag == typeTagObject:
+		if err := expectDelim(jd, '{'); err != nil {
+			return nil, err
+		}
+		value, err = d.convertStreamFields(jd, map[string]any{})
+
+	case tag == typeTagSet && !hooked:
+		value, err = d.convertStreamSet(jd)
+
+	case isTypeTag(tag) || hooked:
+		// other tagged values, such as documents, are small enough to
+		// convert as a whole
+		var raw any
+		if err := jd.Decode(&raw); err != nil {
+			return nil, err
+		}
+		value, err = d.convert(map[string]any{key: raw})
+
+	default:
+		var first any
+		if first, err = d.convertStream(jd); err != nil {
+			return nil, err
+		}
+		return d.convertStreamFields(jd, map[string]any{key: first})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return value, expectDelim(jd, '}')
+}
+
+// convertStreamFields reads the remaining fields of a plain object into obj,
+// up to and including its closing brace.
+func (d decoder) convertStreamFields(jd *json.Decoder, obj map[string]any) (map[string]any, error) {
+	for jd.More() {
+		key, err := streamKey(jd)
+		if err != nil {
+			return nil, err
+		}
+
+		if obj[key], err = d.convertStream(jd); err != nil {
+			return nil, err
+		}
+	}
+	return obj, expectDelim(jd, '}')
+}
+
+// convertStreamSet reads the value of an @set, converting its page of data
+// one item at a time.
+func (d decoder) convertStreamSet(jd *json.Decoder) (any, error) {
+	tok, err := jd.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if after, ok := tok.(string); ok {
+		return unboxSet(after)
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("invalid %s %v", typeTagSet, tok)
+	}
+
+	page := &Page{}
+	for jd.More() {
+		key, err := streamKey(jd)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.convertStream(jd)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "data":
+			items, ok := value.([]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s data %v", typeTagSet, value)
+			}
+			page.Data = items
+		case "after":
+			page.After, _ = value.(string)
+		}
+	}
+	return page, expectDelim(jd, '}')
+}
+
+func streamKey(jd *json.Decoder) (string, error) {
+	tok, err := jd.Token()
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("expected object key, got %v", tok)
+	}
+	return key, nil
+}
+
+func expectDelim(jd *json.Decoder, delim json.Delim) error {
+	tok, err := jd.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// intNumbers replaces the [json.Number] values in body with int64 values, or
+// float64 values for numbers that aren't integers or don't fit in an int64.
+func intNumbers(body any) any {
+	switch b := body.(type) {
+	case json.Number:
+		if i, err := b.Int64(); err == nil {
+			return i
+		}
+		if f, err := b.Float64(); err == nil {
+			return f
+		}
+	case map[string]any:
+		for k, v := range b {
+			b[k] = intNumbers(v)
+		}
+	case []any:
+		for i, v := range b {
+			b[i] = intNumbers(v)
+		}
+	}
+	return body
+}
+
 func convert(escaped bool, body any) (any, error) {
 	switch b := body.(type) {
 	case map[string]any:
@@ -219,32 +932,99 @@ func convertSlice(body []any) ([]any, error) {
 	return body, nil
 }
 
+// DecodeHookFunc decodes the payload of a tagged Fauna value, as decoded by
+// encoding/json, into a Go value.
+type DecodeHookFunc func(raw any) (any, error)
+
+var (
+	decodeHooksMu sync.RWMutex
+	decodeHooks   = map[typeTag]DecodeHookFunc{}
+)
+
+// RegisterDecodeHook makes fn decode every value tagged with tag, such as
+// "@ref" or "@time", in place of the driver's own decoding. For example, a
+// hook for "@time" receives the time's string. Values returned by fn are set
+// as is in decoded results, so destinations of [fauna.QuerySuccess.Unmarshal]
+// must be of a type the value can be assigned to. Passing a nil fn removes
+// the hook for tag.
+//
+// Hooks apply to all clients, so register them during initialization.
+func RegisterDecodeHook(tag string, fn DecodeHookFunc) {
+	decodeHooksMu.Lock()
+	defer decodeHooksMu.Unlock()
+
+	if fn == nil {
+		delete(decodeHooks, typeTag(tag))
+	} else {
+		decodeHooks[typeTag(tag)] = fn
+	}
+}
+
+func decodeHook(tag typeTag) (DecodeHookFunc, bool) {
+	decodeHooksMu.RLock()
+	defer decodeHooksMu.RUnlock()
+
+	fn, ok := decodeHooks[tag]
+	return fn, ok
+}
+
+// hasDecodeHooks reports whether any hooks are registered.
+func hasDecodeHooks() bool {
+	decodeHooksMu.RLock()
+	defer decodeHooksMu.RUnlock()
+
+	return len(decodeHooks) > 0
+}
+
 func unboxType(body map[string]any) (any, error) {
 	if len(body) == 1 {
 		for boxedK, v := range body {
-			switch typeTag(boxedK) {
-			case typeTagInt, typeTagLong:
-				return unboxInt(v.(string))
-			case typeTagDouble:
-				return unboxDouble(v.(string))
-			case typeTagDate:
-				return unboxDate(v.(string))
-			case typeTagTime:
-				return unboxTime(v.(string))
-			case typeTagMod:
-				return unboxMod(v.(string))
-			case typeTagRef:
-				return unboxRef(v.(map[string]any))
-			case typeTagSet:
-				return unboxSet(v)
-			case typeTagStream:
-				return unboxStream(v)
-			case typeTagDoc:
-				return unboxDoc(v.(map[string]any))
-			case typeTagObject:
-				return convertMap(v.(map[string]any))
-			case typeTagBytes:
-				return unboxBytes(v.(string))
+			tag := typeTag(boxedK)
+
+			if hook, ok := decodeHook(tag); ok {
+				val, err := hook(v)
+				if err != nil {
+					return nil, fmt.Errorf("decode hook for %s failed: %w", tag, err)
+				}
+				return val, nil
+			}
+
+			switch vt := v.(type) {
+			case string:
+				switch tag {
+				case typeTagInt, typeTagLong:
+					return unboxInt(vt)
+				case typeTagDouble:
+					return unboxDouble(vt)
+				case typeTagDate:
+					return unboxDate(vt)
+				case typeTagTime:
+					return unboxTime(vt)
+				case typeTagMod:
+					return unboxMod(vt)
+				case typeTagSet:
+					return unboxSet(vt)
+				case typeTagStream:
+					return unboxStream(vt)
+				case typeTagBytes:
+					return unboxBytes(vt)
+				}
+
+			case map[string]any:
+				switch tag {
+				case typeTagRef:
+					return unboxRef(vt)
+				case typeTagSet:
+					return unboxSet(vt)
+				case typeTagDoc:
+					return unboxDoc(vt)
+				case typeTagObject:
+					return convertMap(vt)
+				}
+			}
+
+			if isTypeTag(tag) {
+				return nil, fmt.Errorf("invalid %s %v", tag, v)
 			}
 		}
 	}
@@ -252,6 +1032,18 @@ func unboxType(body map[string]any) (any, error) {
 	return convertMap(body)
 }
 
+func isTypeTag(tag typeTag) bool {
+	switch tag {
+	case typeTagInt, typeTagLong, typeTagDouble,
+		typeTagDate, typeTagTime,
+		typeTagDoc, typeTagRef, typeTagSet, typeTagStream,
+		typeTagMod, typeTagObject, typeTagBytes:
+		return true
+	default:
+		return false
+	}
+}
+
 func unboxMod(v string) (*Module, error) {
 	m := Module{v}
 	return &m, nil
@@ -288,10 +1080,10 @@ func getIDorName(v map[string]any) (id string, name string) {
 }
 
 func getExistsCause(v map[string]any) (exists bool, cause string) {
-	if existsRaw, hasExists := v["exists"]; hasExists {
-		if exists = existsRaw.(bool); !exists {
-			if causeRaw, hasCause := v["cause"]; hasCause {
-				return exists, causeRaw.(string)
+	if existsRaw, hasExists := v["exists"].(bool); hasExists {
+		if exists = existsRaw; !exists {
+			if causeRaw, hasCause := v["cause"].(string); hasCause {
+				return exists, causeRaw
 			}
 		}
 	}
@@ -387,7 +1179,7 @@ func unboxSet(v any) (any, error) {
 		return &setC, nil
 	}
 
-	set := v.(map[string]any)
+	set, _ := v.(map[string]any)
 	if dataI, ok := set["data"]; ok {
 		if dataRaw, ok := dataI.([]any); ok {
 			data, err := convertSlice(dataRaw)
@@ -418,11 +1210,14 @@ func unboxStream(v any) (any, error) {
 }
 
 func unboxTime(v string) (*time.Time, error) {
-	if t, err := time.Parse(timeFormat, v); err != nil {
-		return nil, err
-	} else {
-		return &t, nil
+	t, err := time.Parse(timeFormat, v)
+	if err != nil {
+		// keep the offset of times not sent in UTC
+		if t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, err
+		}
 	}
+	return &t, nil
 }
 
 func unboxDate(v string) (*time.Time, error) {
@@ -457,39 +1252,58 @@ func unboxBytes(v string) (any, error) {
 	}
 }
 
+// NumericOverflowStrategy controls how integers that don't fit in a Fauna
+// Long are encoded.
+type NumericOverflowStrategy int
+
+const (
+	// NumericOverflowError fails to encode integers outside the range of a
+	// Fauna Long. This is the default.
+	NumericOverflowError NumericOverflowStrategy = iota
+	// NumericOverflowClamp encodes integers outside the range of a Fauna Long
+	// as the closest Long.
+	NumericOverflowClamp
+	// NumericOverflowDouble encodes integers outside the range of a Fauna Long
+	// as a Double, which may lose precision.
+	NumericOverflowDouble
+	// NumericOverflowString encodes integers outside the range of a Fauna Long
+	// as a decimal string.
+	NumericOverflowString
+)
+
+type encoder struct {
+	numericOverflow NumericOverflowStrategy
+}
+
 func marshal(v any) ([]byte, error) {
-	if enc, err := encode(v, ""); err != nil {
-		return nil, err
-	} else {
-		return json.Marshal(enc)
-	}
+	return encoder{}.marshal(v)
 }
 
-func encode(v any, hint string) (any, error) {
+func (e encoder) encode(v any, hint string) (any, error) {
 	switch vt := v.(type) {
 	case *queryFragment:
-		return encodeQueryFragment(vt)
+		return e.encodeQueryFragment(vt)
 
 	case *Query:
-		return encodeQuery(vt)
+		return e.encodeQuery(vt)
 
 	case Module:
 		return encodeMod(vt)
 
 	case Ref,
 		NamedRef:
-		return encodeFaunaStruct(typeTagRef, vt)
+		return e.encodeFaunaStruct(typeTagRef, vt)
 
 	case Document,
 		NamedDocument:
-		return encodeFaunaStruct(typeTagDoc, vt)
+		return e.encodeFaunaStruct(typeTagDoc, vt)
 
 	case NullDocument,
 		NullNamedDocument:
-		return encodeStruct(v)
+		return e.encodeStruct(v)
 
 	case Page:
-		return encodeFaunaStruct(typeTagSet, vt)
+		return e.encodeFaunaStruct(typeTagSet, vt)
 
 	case EventSource:
 		return map[typeTag]any{typeTagStream: vt}, nil
@@ -497,15 +1311,21 @@ func encode(v any, hint string) (any, error) {
 	case time.Time:
 		return encodeTime(vt, hint)
 
+	case *time.Time:
+		if vt == nil {
+			return nil, nil
+		}
+		return encodeTime(*vt, hint)
+
 	case queryRequest:
-		query, err := encode(vt.Query, hint)
+		query, err := e.encode(vt.Query, hint)
 		if err != nil {
 			return nil, err
 		}
 
 		out := map[string]any{"query": query}
 		if len(vt.Arguments) > 0 {
-			if args, err := encodeMap(reflect.ValueOf(vt.Arguments)); err != nil {
+			if args, err := e.encodeMap(reflect.ValueOf(vt.Arguments)); err != nil {
 				return nil, err
 			} else {
 				out["arguments"] = args
@@ -538,6 +1358,18 @@ func encode(v any, hint string) (any, error) {
 
 	case []byte:
 		return encodeBytes(vt)
+
+	case big.Int:
+		return e.encodeBigInt(&vt)
+
+	case *big.Int:
+		if vt == nil {
+			return nil, nil
+		}
+		return e.encodeBigInt(vt)
+
+	case encoding.TextMarshaler:
+		return encodeText(vt)
 	}
 
 	switch value := reflect.ValueOf(v); value.Kind() {
@@ -550,7 +1382,7 @@ func encode(v any, hint string) (any, error) {
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		if i := value.Uint(); i > maxLong {
-			return nil, fmt.Errorf("numeric value is outside Fauna's type constraints")
+			return e.encodeBigInt(new(big.Int).SetUint64(i))
 		} else {
 			return encodeInt(int64(i))
 		}
@@ -562,16 +1394,16 @@ func encode(v any, hint string) (any, error) {
 		if value.IsNil() {
 			return nil, nil
 		}
-		return encode(reflect.Indirect(value).Interface(), hint)
+		return e.encode(reflect.Indirect(value).Interface(), hint)
 
 	case reflect.Struct:
-		return encodeStruct(v)
+		return e.encodeStruct(v)
 
 	case reflect.Map:
-		return encodeMap(value)
+		return e.encodeMap(value)
 
 	case reflect.Slice:
-		return encodeSlice(value)
+		return e.encodeSlice(value)
 	}
 
 	return v, nil
@@ -591,6 +1423,30 @@ func encodeInt(i int64) (any, error) {
 	return map[typeTag]any{tag: strconv.FormatInt(i, 10)}, nil
 }
 
+func (e encoder) encodeBigInt(i *big.Int) (any, error) {
+	if i.IsInt64() {
+		return encodeInt(i.Int64())
+	}
+
+	switch e.numericOverflow {
+	case NumericOverflowClamp:
+		if i.Sign() < 0 {
+			return encodeInt(minLong)
+		}
+		return encodeInt(maxLong)
+
+	case NumericOverflowDouble:
+		f, _ := new(big.Float).SetInt(i).Float64()
+		return map[typeTag]any{typeTagDouble: strconv.FormatFloat(f, 'f', -1, 64)}, nil
+
+	case NumericOverflowString:
+		return i.String(), nil
+
+	default:
+		return nil, fmt.Errorf("numeric value is outside Fauna's type constraints")
+	}
+}
+
 func encodeTime(t time.Time, hint string) (any, error) {
 	out := make(map[typeTag]any)
 	if hint == "date" {
@@ -605,29 +1461,28 @@ func encodeMod(m Module) (any, error) {
 	return map[typeTag]string{typeTagMod: m.Name}, nil
 }
 
-func encodeFaunaStruct(tag typeTag, s any) (any, error) {
-	if doc, err := encodeStruct(s); err != nil {
+func (e encoder) encodeFaunaStruct(tag typeTag, s any) (any, error) {
+	if doc, err := e.encodeStruct(s); err != nil {
 		return nil, err
 	} else {
 		return map[typeTag]any{tag: doc}, nil
 	}
 }
 
-func encodeMap(mv reflect.Value) (any, error) {
+func (e encoder) encodeMap(mv reflect.Value) (any, error) {
 	hasConflictingKey := false
 	out := make(map[string]any)
 
 	mi := mv.MapRange()
 	for i := 0; mi.Next(); i++ {
-		if mi.Key().Kind() != reflect.String {
-			return mv.Interface(), nil
+		key, err := mapKey(mi.Key())
+		if err != nil {
+			return nil, err
 		}
 
-		if enc, err := encode(mi.Value().Interface(), ""); err != nil {
+		if enc, err := e.encode(mi.Value().Interface(), ""); err != nil {
 			return nil, err
 		} else {
-
-			key := mi.Key().String()
 			if keyConflicts(key) {
 				hasConflictingKey = true
 			}
@@ -642,11 +1497,54 @@ func encodeMap(mv reflect.Value) (any, error) {
 	}
 }
 
-func encodeSlice(sv reflect.Value) (any, error) {
+// encodeText encodes custom scalar types, such as UUIDs and enums, as the
+// string they marshal to.
+func encodeText(m encoding.TextMarshaler) (any, error) {
+	if v := reflect.ValueOf(m); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+
+	b, err := m.MarshalText()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %T: %w", m, err)
+	}
+	return string(b), nil
+}
+
+// mapKey returns the object key for k, following encoding/json: keys of
+// string kinds are used as is, then [encoding.TextMarshaler] keys are
+// marshaled, and integer keys are formatted in base 10.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+
+	if m, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		b, err := m.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal map key %v: %w", k, err)
+		}
+		return string(b), nil
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("unsupported map key type %s, keys must be strings, integers or implement encoding.TextMarshaler", k.Type())
+}
+
+func (e encoder) encodeSlice(sv reflect.Value) (any, error) {
 	sLen := sv.Len()
 	out := make([]any, sLen)
 	for i := 0; i < sLen; i++ {
-		if enc, err := encode(sv.Index(i).Interface(), ""); err != nil {
+		if enc, err := e.encode(sv.Index(i).Interface(), ""); err != nil {
 			return nil, err
 		} else {
 			out[i] = enc
@@ -656,10 +1554,61 @@ func encodeSlice(sv reflect.Value) (any, error) {
 	return out, nil
 }
 
-func encodeStruct(s any) (any, error) {
+// squashHint is the fauna tag hint flattening a struct field's fields into
+// the enclosing object. Untagged embedded structs are flattened without it.
+const squashHint = "squash"
+
+// remainHint is the fauna tag hint of a map field holding the fields of an
+// object that no other struct field is decoded from. The map's entries are
+// encoded as fields of the enclosing object, so they round-trip.
+const remainHint = "remain"
+
+// squash reports whether a struct field is flattened into the enclosing
+// object. Embedded document types keep being encoded under their own name.
+func squash(field reflect.StructField, name string, hint string) bool {
+	if hint == squashHint || hint == remainHint {
+		return true
+	}
+	if !field.Anonymous || name != "" {
+		return false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	switch reflect.Zero(t).Interface().(type) {
+	case Document, NamedDocument, NullDocument, NullNamedDocument, time.Time, Module, Ref, NamedRef, Page, Query:
+		return false
+	}
+	return true
+}
+
+// squashedFields returns the fields of an encoded struct, which are nil for a
+// nil pointer.
+func squashedFields(enc any) (map[string]any, bool) {
+	switch v := enc.(type) {
+	case nil:
+		return nil, true
+	case map[string]any:
+		return v, true
+	case map[typeTag]any:
+		if fields, ok := v[typeTagObject].(map[string]any); ok {
+			return fields, true
+		}
+	}
+	return nil, false
+}
+
+func (e encoder) encodeStruct(s any) (any, error) {
 	hasConflictingKey := false
 	isDoc := false
 	out := make(map[string]any)
+	var squashed []map[string]any
 
 	elem := reflect.ValueOf(s)
 	fields := reflect.TypeOf(s).NumField()
@@ -673,7 +1622,7 @@ func encodeStruct(s any) (any, error) {
 			if doc.Ref != nil {
 				out["cause"] = doc.Cause
 
-				if ref, err := encode(doc.Ref, ""); err != nil {
+				if ref, err := e.encode(doc.Ref, ""); err != nil {
 					return nil, err
 				} else {
 					out["ref"] = ref
@@ -689,7 +1638,7 @@ func encodeStruct(s any) (any, error) {
 			if doc.Ref != nil {
 				out["cause"] = doc.Cause
 
-				if ref, err := encode(doc.Ref, ""); err != nil {
+				if ref, err := e.encode(doc.Ref, ""); err != nil {
 					return nil, err
 				} else {
 					out["ref"] = ref
@@ -705,13 +1654,13 @@ func encodeStruct(s any) (any, error) {
 			if doc.ID != "" && doc.Coll != nil && doc.TS != nil {
 				out["id"] = doc.ID
 
-				if coll, err := encode(doc.Coll, ""); err != nil {
+				if coll, err := e.encode(doc.Coll, ""); err != nil {
 					return nil, err
 				} else {
 					out["coll"] = coll
 				}
 
-				if ts, err := encode(doc.TS, "time"); err != nil {
+				if ts, err := e.encode(doc.TS, "time"); err != nil {
 					return nil, err
 				} else {
 					out["ts"] = ts
@@ -728,13 +1677,13 @@ func encodeStruct(s any) (any, error) {
 			if doc.Name != "" && doc.Coll != nil && doc.TS != nil {
 				out["name"] = doc.Name
 
-				if coll, err := encode(doc.Coll, ""); err != nil {
+				if coll, err := e.encode(doc.Coll, ""); err != nil {
 					return nil, err
 				} else {
 					out["coll"] = coll
 				}
 
-				if ts, err := encode(doc.TS, "time"); err != nil {
+				if ts, err := e.encode(doc.TS, "time"); err != nil {
 					return nil, err
 				} else {
 					out["ts"] = ts
@@ -757,8 +1706,14 @@ func encodeStruct(s any) (any, error) {
 			typeHint = tags[1]
 		}
 
-		if enc, err := encode(elem.Field(i).Interface(), typeHint); err != nil {
+		if enc, err := e.encode(elem.Field(i).Interface(), typeHint); err != nil {
 			return nil, err
+		} else if squash(structField, tags[0], typeHint) {
+			fields, ok := squashedFields(enc)
+			if !ok {
+				return nil, fmt.Errorf("can't squash field %s of type %s", structField.Name, structField.Type)
+			}
+			squashed = append(squashed, fields)
 		} else {
 			name := tags[0]
 			if name == "" {
@@ -772,6 +1727,20 @@ func encodeStruct(s any) (any, error) {
 		}
 	}
 
+	// Like encoding/json, fields of the struct itself take precedence over
+	// those of squashed structs and remain maps.
+	for _, fields := range squashed {
+		for name, enc := range fields {
+			if _, exists := out[name]; exists {
+				continue
+			}
+			if keyConflicts(name) {
+				hasConflictingKey = true
+			}
+			out[name] = enc
+		}
+	}
+
 	if isDoc {
 		return map[typeTag]any{typeTagDoc: out}, nil
 	}
@@ -783,12 +1752,12 @@ func encodeStruct(s any) (any, error) {
 	return out, nil
 }
 
-func encodeQuery(q *Query) (any, error) {
+func (e encoder) encodeQuery(q *Query) (any, error) {
 	const fqlLabel = "fql"
 
 	rendered := make([]any, len(q.fragments))
 	for i, f := range q.fragments {
-		if r, err := encode(f, ""); err != nil {
+		if r, err := e.encode(f, ""); err != nil {
 			return nil, err
 		} else {
 			rendered[i] = r
@@ -798,12 +1767,12 @@ func encodeQuery(q *Query) (any, error) {
 	return map[string]any{fqlLabel: rendered}, nil
 }
 
-func encodeQueryFragment(f *queryFragment) (any, error) {
+func (e encoder) encodeQueryFragment(f *queryFragment) (any, error) {
 	if f.literal {
 		return f.value, nil
 	}
 
-	ret, err := encode(f.value, "")
+	ret, err := e.encode(f.value, "")
 	if err != nil {
 		return nil, err
 	}
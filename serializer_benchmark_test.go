@@ -1,6 +1,8 @@
 package fauna
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -222,3 +224,122 @@ func BenchmarkUnmarshalBytes(b *testing.B) {
 		assert.NoError(b, err)
 	}
 }
+
+func benchmarkPage(b *testing.B) *Page {
+	items := make([]string, 1000)
+	for i := range items {
+		items[i] = `{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2024-01-01T00:00:00Z"},"name":"cup","price":{"@double":"9.5"}}}`
+	}
+	var page Page
+	assert.NoError(b, unmarshal([]byte(`{"@set":{"data":[`+strings.Join(items, ",")+`]}}`), &page))
+	return &page
+}
+
+type benchmarkProduct struct {
+	Document
+	Name  string  `fauna:"name"`
+	Price float64 `fauna:"price"`
+}
+
+func BenchmarkPageUnmarshal(b *testing.B) {
+	page := benchmarkPage(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var products []benchmarkProduct
+		assert.NoError(b, page.Unmarshal(&products))
+	}
+}
+
+func BenchmarkPageAs(b *testing.B) {
+	page := benchmarkPage(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := PageAs[benchmarkProduct](page)
+		assert.NoError(b, err)
+	}
+}
+
+func benchmarkResult(b *testing.B) ([]byte, any) {
+	items := make([]string, 100)
+	for i := range items {
+		items[i] = `{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2024-01-01T00:00:00Z"},"name":"cup","price":{"@double":"9.5"}}}`
+	}
+	raw := []byte(`[` + strings.Join(items, ",") + `]`)
+	data, err := decode(raw)
+	assert.NoError(b, err)
+	return raw, data
+}
+
+// BenchmarkUnmarshalResult decodes a query result straight from its JSON, as
+// QuerySuccess.Unmarshal does.
+func BenchmarkUnmarshalResult(b *testing.B) {
+	raw, data := benchmarkResult(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var products []benchmarkProduct
+		assert.NoError(b, decoder{}.decodeResult(raw, data, &products))
+	}
+}
+
+// BenchmarkUnmarshalResultData decodes the result of BenchmarkUnmarshalResult
+// from its decoded value with mapstructure, to compare the two.
+func BenchmarkUnmarshalResultData(b *testing.B) {
+	_, data := benchmarkResult(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var products []benchmarkProduct
+		assert.NoError(b, decodeInto(data, &products))
+	}
+}
+
+func benchmarkRequest() queryRequest {
+	q, _ := FQL(`Product.byId(${id})!.update(${data})`, map[string]any{
+		"id": "123",
+		"data": map[string]any{
+			"name":    "cup",
+			"price":   9.5,
+			"stock":   42,
+			"active":  true,
+			"updated": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	})
+	return queryRequest{Query: q}
+}
+
+func BenchmarkMarshalRequest(b *testing.B) {
+	req := benchmarkRequest()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := encoder{}.marshal(req)
+		assert.NoError(b, err)
+	}
+}
+
+// BenchmarkMarshalRequestTree encodes the request of BenchmarkMarshalRequest
+// into a tree of maps for json.Marshal, as marshal did before writing
+// requests straight into a buffer, to compare the two.
+func BenchmarkMarshalRequestTree(b *testing.B) {
+	req := benchmarkRequest()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		enc, err := encoder{}.encode(req, "")
+		assert.NoError(b, err)
+		_, err = json.Marshal(enc)
+		assert.NoError(b, err)
+	}
+}
+
+// TestMarshalRequestAllocs gates the allocations of BenchmarkMarshalRequest:
+// flat arguments are written straight into a pooled buffer, leaving the copy
+// of the output and the request boxed into an interface.
+func TestMarshalRequestAllocs(t *testing.T) {
+	req := benchmarkRequest()
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = encoder{}.marshal(req)
+	})
+	assert.LessOrEqual(t, allocs, float64(2))
+}
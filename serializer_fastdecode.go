@@ -0,0 +1,705 @@
+package fauna
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// errNotDirect is returned by directDecoder for values it can't decode, which
+// are then decoded by mapstructure, so that it reports the error if any.
+var errNotDirect = errors.New("value can't be decoded directly")
+
+// decodeResult decodes a query result into `into`: straight from raw, the
+// result's JSON, when into's type allows, and from data, the result as
+// decoded into maps and slices, otherwise.
+func (d decoder) decodeResult(raw []byte, data any, into any) error {
+	if raw != nil && d.canDecodeDirect(into) {
+		if err := d.decodeDirect(raw, into); err == nil {
+			return nil
+		}
+	}
+	return d.decodeInto(data, into)
+}
+
+// canDecodeDirect reports whether into can be decoded by decodeDirect: it
+// must point to the zero value of a type directType accepts, and d must not
+// need the hooks only mapstructure runs, such as lenient decoding.
+func (d decoder) canDecodeDirect(into any) bool {
+	if d.opts.Lenient || d.opts.NamingConvention != nil || d.coercions != nil || hasDecodeHooks() {
+		return false
+	}
+
+	v := reflect.ValueOf(into)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return false
+	}
+	elem := v.Elem()
+	return elem.Kind() != reflect.Interface && elem.IsZero() && directType(elem.Type())
+}
+
+// decodeDirect decodes raw into `into` in a single pass, like decodeInto
+// would decode it once decoded into maps and slices. If it fails, `into` is
+// reset to its zero value.
+func (d decoder) decodeDirect(raw []byte, into any) error {
+	target := reflect.ValueOf(into).Elem()
+	dd := directDecoder{dec: d, data: raw}
+	if err := dd.decodeValue(target); err != nil || !dd.end() {
+		target.Set(reflect.Zero(target.Type()))
+		return errNotDirect
+	}
+
+	d.localizeTimes(reflect.ValueOf(into))
+	return nil
+}
+
+var directTypes sync.Map
+
+// directType reports whether values of type t can be decoded by
+// directDecoder. Types that mapstructure's hooks treat specially, such as
+// pages and text unmarshalers, and structs whose fields mapstructure would
+// match in ways directDecoder doesn't, such as remain fields, can't.
+func directType(t reflect.Type) bool {
+	if ok, found := directTypes.Load(t); found {
+		return ok.(bool)
+	}
+
+	ok := findDirectType(t, map[reflect.Type]bool{})
+	directTypes.Store(t, ok)
+	return ok
+}
+
+// findDirectType reports whether t can be decoded by directDecoder, assuming
+// the types in seen can so that recursive types terminate.
+func findDirectType(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+
+	if t != timeType && reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Pointer, reflect.Slice:
+		return findDirectType(t.Elem(), seen)
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && findDirectType(t.Key(), seen) && findDirectType(t.Elem(), seen)
+	case reflect.Struct:
+		switch {
+		case t == timeType || t == moduleType:
+			return true
+		case t == pageType || t == bigIntType || reflect.PointerTo(t).Implements(setCursorType):
+			return false
+		}
+
+		plan, ok := directStructOf(t)
+		if !ok {
+			return false
+		}
+		for _, f := range plan.fields {
+			if !findDirectType(f.typ, seen) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+var moduleType = reflect.TypeOf(Module{})
+
+// directStruct holds the fields of a struct type decoded by directDecoder,
+// flattened like mapstructure squashes them.
+type directStruct struct {
+	fields []directField
+	byName map[string]int
+}
+
+type directField struct {
+	name  string
+	index []int
+	typ   reflect.Type
+}
+
+// field returns the index of the field the object key named key is decoded
+// into, matched case-insensitively like mapstructure does.
+func (s *directStruct) field(key string) (int, bool) {
+	if i, ok := s.byName[key]; ok {
+		return i, true
+	}
+	for i, f := range s.fields {
+		if strings.EqualFold(f.name, key) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// maxDirectFields is the number of fields above which structs aren't decoded
+// by directDecoder, which tracks the fields it has set in a bit set.
+const maxDirectFields = 64
+
+var directStructs sync.Map
+
+// directStructOf returns the fields of struct type t, unless mapstructure
+// would match them in ways directDecoder doesn't: with remain fields,
+// embedded pointers, or names that are equal regardless of case, which
+// mapstructure would all decode the same value into.
+func directStructOf(t reflect.Type) (*directStruct, bool) {
+	if plan, found := directStructs.Load(t); found {
+		return plan.(*directStruct), plan.(*directStruct) != nil
+	}
+
+	plan := newDirectStruct(t)
+	directStructs.Store(t, plan)
+	return plan, plan != nil
+}
+
+func newDirectStruct(t reflect.Type) *directStruct {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	plan := &directStruct{byName: map[string]int{}}
+	structs := []embedded{{t, nil}}
+	for len(structs) > 0 {
+		s := structs[0]
+		structs = structs[1:]
+
+		for i := 0; i < s.typ.NumField(); i++ {
+			field := s.typ.Field(i)
+			index := append(append([]int(nil), s.index...), i)
+
+			tags := strings.Split(field.Tag.Get(fieldTag), ",")
+			squashed := field.Anonymous && field.Type.Kind() == reflect.Struct
+			for _, hint := range tags[1:] {
+				if hint == remainHint {
+					return nil
+				}
+				if hint == squashHint {
+					squashed = true
+					break
+				}
+			}
+
+			if squashed {
+				if field.Type.Kind() != reflect.Struct || !field.IsExported() {
+					return nil
+				}
+				structs = append(structs, embedded{field.Type, index})
+				continue
+			}
+			if field.Anonymous {
+				return nil
+			}
+			if !field.IsExported() {
+				continue
+			}
+
+			name := field.Name
+			if tags[0] != "" {
+				name = tags[0]
+			}
+			for _, other := range plan.fields {
+				if strings.EqualFold(other.name, name) {
+					return nil
+				}
+			}
+			plan.byName[name] = len(plan.fields)
+			plan.fields = append(plan.fields, directField{name, index, field.Type})
+		}
+	}
+
+	if len(plan.fields) > maxDirectFields {
+		return nil
+	}
+	return plan
+}
+
+// directDecoder decodes the JSON of Fauna values straight into Go values,
+// rather than into maps and slices for mapstructure to copy into them, which
+// is several times faster for structs. It only decodes the values it can
+// decode exactly as mapstructure would, and fails with errNotDirect
+// otherwise, including for values that mapstructure fails to decode.
+type directDecoder struct {
+	dec  decoder
+	data []byte
+	pos  int
+}
+
+func (dd *directDecoder) decodeValue(v reflect.Value) error {
+	dd.skipSpace()
+	if dd.peek() == 'n' {
+		// like mapstructure, null leaves the value as it is
+		return dd.literal("null")
+	}
+
+	t := v.Type()
+	switch t.Kind() {
+	case reflect.Pointer:
+		// mapstructure fails to decode documents into pointers
+		if dd.peekTag() == typeTagDoc {
+			return errNotDirect
+		}
+		elem := reflect.New(t.Elem())
+		if err := dd.decodeValue(elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+
+	case reflect.Interface:
+		// values decoded into interfaces are left to mapstructure, as its
+		// hooks convert some of them, such as documents
+		start := dd.pos
+		if err := dd.skipValue(); err != nil {
+			return err
+		}
+		data, err := dd.dec.decode(dd.data[start:dd.pos])
+		if err != nil {
+			return err
+		}
+		mapDec, err := dd.dec.mapDecoder(v.Addr().Interface())
+		if err != nil {
+			return err
+		}
+		return mapDec.Decode(data)
+
+	case reflect.Bool:
+		switch dd.peek() {
+		case 't':
+			v.SetBool(true)
+			return dd.literal("true")
+		case 'f':
+			v.SetBool(false)
+			return dd.literal("false")
+		}
+		return errNotDirect
+
+	case reflect.String:
+		s, err := dd.readString()
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := dd.readInt()
+		if err != nil || v.OverflowInt(i) {
+			return errNotDirect
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := dd.readInt()
+		if err != nil || i < 0 || v.OverflowUint(uint64(i)) {
+			return errNotDirect
+		}
+		v.SetUint(uint64(i))
+
+	case reflect.Float32, reflect.Float64:
+		tag, s, err := dd.readTagged()
+		if err != nil {
+			return err
+		}
+
+		var f float64
+		switch tag {
+		case typeTagDouble:
+			f, err = strconv.ParseFloat(s, 64)
+		case typeTagInt, typeTagLong:
+			var i int64
+			i, err = strconv.ParseInt(s, 10, 64)
+			f = float64(i)
+		default:
+			return errNotDirect
+		}
+		if err != nil {
+			return errNotDirect
+		}
+		v.SetFloat(f)
+
+	case reflect.Slice:
+		return dd.decodeSlice(v)
+
+	case reflect.Map:
+		m := reflect.MakeMap(t)
+		if err := dd.readObject(func(key string) error {
+			elem := reflect.New(t.Elem()).Elem()
+			if err := dd.decodeValue(elem); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem)
+			return nil
+		}); err != nil {
+			return err
+		}
+		v.Set(m)
+
+	case reflect.Struct:
+		return dd.decodeStruct(v)
+
+	default:
+		return errNotDirect
+	}
+	return nil
+}
+
+func (dd *directDecoder) decodeSlice(v reflect.Value) error {
+	if err := dd.expect('['); err != nil {
+		return err
+	}
+
+	s := reflect.MakeSlice(v.Type(), 0, 0)
+	for n := 0; ; n++ {
+		dd.skipSpace()
+		if dd.peek() == ']' {
+			dd.pos++
+			break
+		}
+		if n > 0 {
+			if err := dd.expect(','); err != nil {
+				return err
+			}
+		}
+
+		s = reflect.Append(s, reflect.Zero(v.Type().Elem()))
+		if err := dd.decodeValue(s.Index(n)); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+func (dd *directDecoder) decodeStruct(v reflect.Value) error {
+	switch v.Type() {
+	case timeType:
+		tag, s, err := dd.readTagged()
+		if err != nil {
+			return err
+		}
+
+		var ts *time.Time
+		switch tag {
+		case typeTagTime:
+			ts, err = unboxTime(s)
+		case typeTagDate:
+			ts, err = unboxDate(s)
+		default:
+			return errNotDirect
+		}
+		if err != nil {
+			return errNotDirect
+		}
+		if loc := dd.dec.opts.Location; loc != nil {
+			*ts = ts.In(loc)
+		}
+		v.Set(reflect.ValueOf(*ts))
+		return nil
+
+	case moduleType:
+		tag, s, err := dd.readTagged()
+		if err != nil || tag != typeTagMod {
+			return errNotDirect
+		}
+		v.Set(reflect.ValueOf(Module{s}))
+		return nil
+	}
+
+	plan, _ := directStructOf(v.Type())
+	var set uint64
+	return dd.readObject(func(key string) error {
+		i, ok := plan.field(key)
+		if !ok {
+			if dd.dec.opts.Strict {
+				return errNotDirect
+			}
+			return dd.skipValue()
+		}
+
+		// mapstructure decodes a single key into each field
+		if set&(1<<i) != 0 {
+			return errNotDirect
+		}
+		set |= 1 << i
+		return dd.decodeValue(v.FieldByIndex(plan.fields[i].index))
+	})
+}
+
+// readObject reads an object, calling field with each of its keys to read
+// their value. Objects escaped with @object, and documents, are read as the
+// object they hold; other tagged values fail.
+func (dd *directDecoder) readObject(field func(key string) error) error {
+	if err := dd.expect('{'); err != nil {
+		return err
+	}
+	dd.skipSpace()
+	if dd.peek() == '}' {
+		dd.pos++
+		return nil
+	}
+
+	key, err := dd.readKey()
+	if err != nil {
+		return err
+	}
+	if tag := typeTag(key); isTypeTag(tag) {
+		if tag != typeTagObject && tag != typeTagDoc {
+			return errNotDirect
+		}
+		// the fields of escaped objects and documents aren't tags
+		if err := dd.expect('{'); err != nil {
+			return err
+		}
+		if err := dd.readFields(field); err != nil {
+			return err
+		}
+		return dd.expect('}')
+	}
+
+	if err := field(key); err != nil {
+		return err
+	}
+	return dd.readMoreFields(field)
+}
+
+// readFields reads the fields of an object whose opening brace was read, up
+// to and including its closing brace.
+func (dd *directDecoder) readFields(field func(key string) error) error {
+	dd.skipSpace()
+	if dd.peek() == '}' {
+		dd.pos++
+		return nil
+	}
+
+	key, err := dd.readKey()
+	if err != nil {
+		return err
+	}
+	if err := field(key); err != nil {
+		return err
+	}
+	return dd.readMoreFields(field)
+}
+
+// readMoreFields reads the fields of an object after its first one.
+func (dd *directDecoder) readMoreFields(field func(key string) error) error {
+	for {
+		dd.skipSpace()
+		if dd.peek() == '}' {
+			dd.pos++
+			return nil
+		}
+		if err := dd.expect(','); err != nil {
+			return err
+		}
+
+		key, err := dd.readKey()
+		if err != nil {
+			return err
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+	}
+}
+
+func (dd *directDecoder) readKey() (string, error) {
+	key, err := dd.readString()
+	if err != nil {
+		return "", err
+	}
+	return key, dd.expect(':')
+}
+
+// readTagged reads a tagged value holding a string, such as {"@int":"1"}.
+func (dd *directDecoder) readTagged() (typeTag, string, error) {
+	if err := dd.expect('{'); err != nil {
+		return "", "", err
+	}
+	key, err := dd.readKey()
+	if err != nil {
+		return "", "", err
+	}
+	s, err := dd.readString()
+	if err != nil {
+		return "", "", err
+	}
+	return typeTag(key), s, dd.expect('}')
+}
+
+// peekTag returns the tag of the next value, if it is an object whose first
+// key is a tag, without reading it.
+func (dd *directDecoder) peekTag() typeTag {
+	pos := dd.pos
+	defer func() { dd.pos = pos }()
+
+	if dd.expect('{') != nil {
+		return ""
+	}
+	key, err := dd.readString()
+	if err != nil {
+		return ""
+	}
+	return typeTag(key)
+}
+
+func (dd *directDecoder) readInt() (int64, error) {
+	tag, s, err := dd.readTagged()
+	if err != nil {
+		return 0, err
+	}
+	if tag != typeTagInt && tag != typeTagLong {
+		return 0, errNotDirect
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// readString reads a string, using encoding/json for those with escapes or
+// invalid UTF-8, so that they are decoded the same.
+func (dd *directDecoder) readString() (string, error) {
+	dd.skipSpace()
+	if dd.peek() != '"' {
+		return "", errNotDirect
+	}
+
+	ascii := true
+	for i := dd.pos + 1; i < len(dd.data); i++ {
+		switch c := dd.data[i]; {
+		case c == '"':
+			s := dd.data[dd.pos+1 : i]
+			if ascii || utf8.Valid(s) {
+				dd.pos = i + 1
+				return string(s), nil
+			}
+			return dd.readEscapedString()
+		case c == '\\':
+			return dd.readEscapedString()
+		case c >= utf8.RuneSelf:
+			ascii = false
+		}
+	}
+	return "", errNotDirect
+}
+
+func (dd *directDecoder) readEscapedString() (string, error) {
+	start := dd.pos
+	if err := dd.skipString(); err != nil {
+		return "", err
+	}
+
+	var s string
+	if err := json.Unmarshal(dd.data[start:dd.pos], &s); err != nil {
+		return "", errNotDirect
+	}
+	return s, nil
+}
+
+func (dd *directDecoder) skipString() error {
+	for i := dd.pos + 1; i < len(dd.data); i++ {
+		switch dd.data[i] {
+		case '\\':
+			i++
+		case '"':
+			dd.pos = i + 1
+			return nil
+		}
+	}
+	return errNotDirect
+}
+
+// skipValue skips the next value, which is known to be valid JSON, as it was
+// decoded before.
+func (dd *directDecoder) skipValue() error {
+	dd.skipSpace()
+	switch dd.peek() {
+	case '"':
+		return dd.skipString()
+	case '{', '[':
+		depth := 0
+		for dd.pos < len(dd.data) {
+			switch dd.data[dd.pos] {
+			case '"':
+				if err := dd.skipString(); err != nil {
+					return err
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					dd.pos++
+					return nil
+				}
+			}
+			dd.pos++
+		}
+		return errNotDirect
+	default:
+		start := dd.pos
+		for dd.pos < len(dd.data) && !strings.ContainsRune(",}] \t\r\n", rune(dd.data[dd.pos])) {
+			dd.pos++
+		}
+		if dd.pos == start {
+			return errNotDirect
+		}
+		return nil
+	}
+}
+
+func (dd *directDecoder) literal(lit string) error {
+	if len(dd.data)-dd.pos < len(lit) || string(dd.data[dd.pos:dd.pos+len(lit)]) != lit {
+		return errNotDirect
+	}
+	dd.pos += len(lit)
+	return nil
+}
+
+func (dd *directDecoder) expect(c byte) error {
+	dd.skipSpace()
+	if dd.peek() != c {
+		return errNotDirect
+	}
+	dd.pos++
+	return nil
+}
+
+func (dd *directDecoder) peek() byte {
+	if dd.pos < len(dd.data) {
+		return dd.data[dd.pos]
+	}
+	return 0
+}
+
+func (dd *directDecoder) skipSpace() {
+	for dd.pos < len(dd.data) {
+		switch dd.data[dd.pos] {
+		case ' ', '\t', '\r', '\n':
+			dd.pos++
+		default:
+			return
+		}
+	}
+}
+
+// end reports whether only whitespace is left after the decoded value.
+func (dd *directDecoder) end() bool {
+	dd.skipSpace()
+	return dd.pos == len(dd.data)
+}
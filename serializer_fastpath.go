@@ -0,0 +1,324 @@
+package fauna
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// bufferPool holds the buffers requests are encoded into and responses are
+// read into.
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// maxPooledBuffer is the capacity above which buffers aren't returned to
+// bufferPool, so that a single large request or response doesn't stay in
+// memory.
+const maxPooledBuffer = 64 << 10
+
+// getBuffer returns an empty buffer from bufferPool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to bufferPool. Nothing may refer to its bytes
+// afterwards.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBuffer {
+		bufferPool.Put(buf)
+	}
+}
+
+// marshal encodes v as JSON. Queries, maps of arguments and slices, and
+// scalars such as strings, numbers and times are written straight into a
+// pooled buffer, rather than encoded into a tree of maps for [json.Marshal]
+// first, so that encoding the usual small, flat arguments barely allocates.
+// Other values, such as structs, are encoded with encode within the output.
+func (e encoder) marshal(v any) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if err := e.write(buf, v, ""); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf.Bytes()...), nil
+}
+
+// write writes the JSON encoding of v to buf, as encode and json.Marshal
+// would produce it.
+func (e encoder) write(buf *bytes.Buffer, v any, hint string) error {
+	switch vt := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeString(buf, vt)
+	case bool:
+		buf.WriteString(strconv.FormatBool(vt))
+	case int:
+		writeInt(buf, int64(vt))
+	case int8:
+		writeInt(buf, int64(vt))
+	case int16:
+		writeInt(buf, int64(vt))
+	case int32:
+		writeInt(buf, int64(vt))
+	case int64:
+		writeInt(buf, vt)
+	case float64:
+		writeDouble(buf, vt)
+	case float32:
+		writeDouble(buf, float64(vt))
+	case time.Time:
+		writeTime(buf, vt, hint)
+	case *time.Time:
+		if vt == nil {
+			buf.WriteString("null")
+		} else {
+			writeTime(buf, *vt, hint)
+		}
+	case Module:
+		writeTagged(buf, typeTagMod, vt.Name)
+	case *Module:
+		if vt == nil {
+			buf.WriteString("null")
+		} else {
+			writeTagged(buf, typeTagMod, vt.Name)
+		}
+	case *Query:
+		if vt == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return e.writeQuery(buf, vt)
+	case *queryRequest:
+		if vt == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return e.writeQueryRequest(buf, vt)
+	case queryRequest:
+		return e.writeQueryRequest(buf, &vt)
+	case map[string]any:
+		if vt == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return e.writeObject(buf, vt)
+	case []any:
+		if vt == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i, item := range vt {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := e.write(buf, item, ""); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		enc, err := e.encode(v, hint)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(enc)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
+
+func (e encoder) writeQueryRequest(buf *bytes.Buffer, req *queryRequest) error {
+	// keys are sorted, like json.Marshal sorts those of maps
+	buf.WriteByte('{')
+	if len(req.Arguments) > 0 {
+		buf.WriteString(`"arguments":`)
+		if err := e.writeObject(buf, req.Arguments); err != nil {
+			return err
+		}
+		buf.WriteByte(',')
+	}
+	buf.WriteString(`"query":`)
+	if err := e.write(buf, req.Query, ""); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func (e encoder) writeQuery(buf *bytes.Buffer, q *Query) error {
+	buf.WriteString(`{"fql":[`)
+	for i, f := range q.fragments {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if f.literal {
+			if err := e.write(buf, f.value, ""); err != nil {
+				return err
+			}
+			continue
+		}
+		if nested, ok := f.value.(*Query); ok {
+			if err := e.write(buf, nested, ""); err != nil {
+				return err
+			}
+			continue
+		}
+
+		buf.WriteString(`{"value":`)
+		if err := e.write(buf, f.value, ""); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteString(`]}`)
+	return nil
+}
+
+// writeObject writes m with its keys sorted, wrapped in @object if a key
+// conflicts with a type tag, like encodeMap.
+func (e encoder) writeObject(buf *bytes.Buffer, m map[string]any) error {
+	var small [8]string
+	keys := small[:0]
+	conflicts := false
+	for k := range m {
+		keys = append(keys, k)
+		conflicts = conflicts || keyConflicts(k)
+	}
+	sort.Strings(keys)
+
+	if conflicts {
+		buf.WriteString(`{"@object":`)
+	}
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, k)
+		buf.WriteByte(':')
+		if err := e.write(buf, m[k], ""); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	if conflicts {
+		buf.WriteByte('}')
+	}
+	return nil
+}
+
+func writeInt(buf *bytes.Buffer, i int64) {
+	tag := typeTagLong
+	if i <= maxInt && i >= minInt {
+		tag = typeTagInt
+	}
+
+	var num [20]byte
+	buf.WriteString(`{"`)
+	buf.WriteString(string(tag))
+	buf.WriteString(`":"`)
+	buf.Write(strconv.AppendInt(num[:0], i, 10))
+	buf.WriteString(`"}`)
+}
+
+func writeDouble(buf *bytes.Buffer, f float64) {
+	var num [32]byte
+	buf.WriteString(`{"`)
+	buf.WriteString(string(typeTagDouble))
+	buf.WriteString(`":"`)
+	buf.Write(strconv.AppendFloat(num[:0], f, 'f', -1, 64))
+	buf.WriteString(`"}`)
+}
+
+func writeTime(buf *bytes.Buffer, t time.Time, hint string) {
+	var ts [40]byte
+	buf.WriteString(`{"`)
+	if hint == "date" {
+		buf.WriteString(string(typeTagDate))
+		buf.WriteString(`":"`)
+		buf.Write(t.AppendFormat(ts[:0], dateFormat))
+	} else {
+		buf.WriteString(string(typeTagTime))
+		buf.WriteString(`":"`)
+		buf.Write(t.UTC().AppendFormat(ts[:0], timeFormat))
+	}
+	buf.WriteString(`"}`)
+}
+
+func writeTagged(buf *bytes.Buffer, tag typeTag, value string) {
+	buf.WriteString(`{"`)
+	buf.WriteString(string(tag))
+	buf.WriteString(`":`)
+	writeString(buf, value)
+	buf.WriteByte('}')
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeString writes s as a JSON string, escaped like json.Marshal escapes
+// it: control characters, quotes, backslashes, HTML characters and the
+// U+2028 and U+2029 separators are escaped, and invalid UTF-8 is replaced by
+// U+FFFD.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			buf.WriteString(s[start:i])
+			switch b {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(b)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[b>>4])
+				buf.WriteByte(hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(s[start:i])
+			buf.WriteString("\ufffd")
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			buf.WriteString(s[start:i])
+			buf.WriteString(`\u202`)
+			buf.WriteByte(hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
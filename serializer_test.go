@@ -3,7 +3,11 @@ package fauna
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -175,6 +179,55 @@ func TestEncodingPrimitives(t *testing.T) {
 		assert.Error(t, tooLargeErr)
 	})
 
+	t.Run("encode numbers that are too big per overflow strategy", func(t *testing.T) {
+		tooLarge := uint64(18446744073709551615)
+		huge, _ := new(big.Int).SetString("-100000000000000000000", 10)
+
+		tests := []struct {
+			strategy NumericOverflowStrategy
+			value    any
+			want     string
+		}{
+			{NumericOverflowClamp, tooLarge, `{"@long":"9223372036854775807"}`},
+			{NumericOverflowClamp, huge, `{"@long":"-9223372036854775808"}`},
+			{NumericOverflowDouble, tooLarge, `{"@double":"18446744073709552000"}`},
+			{NumericOverflowString, tooLarge, `"18446744073709551615"`},
+			{NumericOverflowString, *huge, `"-100000000000000000000"`},
+			{NumericOverflowError, big.NewInt(42), `{"@int":"42"}`},
+		}
+
+		for _, tt := range tests {
+			bs, err := encoder{numericOverflow: tt.strategy}.marshal(tt.value)
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, tt.want, string(bs))
+			}
+		}
+
+		_, err := encoder{}.marshal(huge)
+		assert.Error(t, err)
+	})
+
+	t.Run("decode into unsigned and big integers", func(t *testing.T) {
+		var u64 uint64
+		unmarshalAndCheck(t, []byte(`"18446744073709551615"`), &u64)
+		assert.Equal(t, uint64(18446744073709551615), u64)
+
+		var u8 uint8
+		assert.ErrorContains(t, unmarshal([]byte(`{"@int":"256"}`), &u8), "overflows uint8")
+		assert.ErrorContains(t, unmarshal([]byte(`{"@int":"-1"}`), &u64), "overflows uint64")
+
+		var i8 int8
+		assert.ErrorContains(t, unmarshal([]byte(`{"@int":"128"}`), &i8), "overflows int8")
+
+		var b big.Int
+		unmarshalAndCheck(t, []byte(`"-100000000000000000000"`), &b)
+		assert.Equal(t, "-100000000000000000000", b.String())
+
+		var pb *big.Int
+		unmarshalAndCheck(t, []byte(`{"@long":"9223372036854775807"}`), &pb)
+		assert.Equal(t, "9223372036854775807", pb.String())
+	})
+
 	t.Run("encode floats", func(t *testing.T) {
 		roundTripCheck(t, 100.0, `{"@double":"100"}`)
 		roundTripCheck(t, -100.1, `{"@double":"-100.1"}`)
@@ -262,6 +315,170 @@ func TestDecodingToInterface(t *testing.T) {
 	}
 }
 
+func TestDecodingNumberModes(t *testing.T) {
+	doc := []byte(`{"@object": {"big": 9007199254740993, "frac": 1.5, "tagged": {"@long": "9007199254740993"}}}`)
+
+	tests := []struct {
+		name   string
+		mode   NumberMode
+		big    any
+		frac   any
+		tagged any
+	}{
+		{"float64", NumberModeFloat64, float64(9007199254740992), 1.5, int64(9007199254740993)},
+		{"json.Number", NumberModeJSONNumber, json.Number("9007199254740993"), json.Number("1.5"), int64(9007199254740993)},
+		{"int64", NumberModeInt64, int64(9007199254740993), 1.5, int64(9007199254740993)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := decoder{opts: DecodeOptions{Numbers: tt.mode}}.decode(doc)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			obj := res.(map[string]any)
+			assert.Equal(t, tt.big, obj["big"])
+			assert.Equal(t, tt.frac, obj["frac"])
+			assert.Equal(t, tt.tagged, obj["tagged"])
+
+			var into struct {
+				Big int64 `fauna:"big"`
+			}
+			if assert.NoError(t, decodeInto(res, &into)) && tt.mode != NumberModeFloat64 {
+				assert.Equal(t, int64(9007199254740993), into.Big)
+			}
+		})
+	}
+}
+
+func TestDecodeOptions(t *testing.T) {
+	doc := []byte(`{"created_at": {"@time": "2023-02-28T18:10:10.00001Z"}, "user_name": "foo", "extra": true}`)
+
+	type user struct {
+		CreatedAt time.Time
+		UserName  string
+	}
+
+	t.Run("naming convention", func(t *testing.T) {
+		var into user
+		if assert.NoError(t, decoder{opts: DecodeOptions{NamingConvention: SnakeCase}}.unmarshal(doc, &into)) {
+			assert.Equal(t, "foo", into.UserName)
+			assert.Equal(t, 2023, into.CreatedAt.Year())
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		var into user
+		err := decoder{opts: DecodeOptions{Strict: true, NamingConvention: SnakeCase}}.unmarshal(doc, &into)
+		assert.ErrorContains(t, err, "extra")
+	})
+
+	t.Run("location", func(t *testing.T) {
+		loc := time.FixedZone("UTC-5", -5*60*60)
+
+		var into map[string]any
+		if assert.NoError(t, decoder{opts: DecodeOptions{Location: loc}}.unmarshal(doc, &into)) {
+			createdAt := into["created_at"].(*time.Time)
+			assert.Equal(t, loc, createdAt.Location())
+			assert.Equal(t, 13, createdAt.Hour())
+		}
+	})
+
+	t.Run("timelocal fields", func(t *testing.T) {
+		loc := time.FixedZone("UTC-5", -5*60*60)
+
+		type event struct {
+			At    time.Time  `fauna:"at,timelocal"`
+			Until *time.Time `fauna:"until,timelocal"`
+			Sent  time.Time  `fauna:"sent"`
+		}
+		type calendar struct {
+			Events []event `fauna:"events"`
+			Next   *event  `fauna:"next"`
+		}
+
+		body := []byte(`{"events": [{"at": {"@time": "2023-02-28T18:10:10Z"}, "until": {"@time": "2023-02-28T19:10:10Z"}, "sent": {"@time": "2023-02-28T18:10:10Z"}}], "next": {"at": {"@time": "2023-03-01T18:10:10Z"}}}`)
+
+		var into calendar
+		if assert.NoError(t, decoder{}.unmarshal(body, &into)) {
+			assert.Equal(t, time.Local, into.Events[0].At.Location())
+			assert.Equal(t, time.Local, into.Events[0].Until.Location())
+			assert.Equal(t, time.UTC, into.Events[0].Sent.Location())
+			assert.Equal(t, time.Local, into.Next.At.Location())
+		}
+
+		if assert.NoError(t, decoder{opts: DecodeOptions{Location: loc}}.unmarshal(body, &into)) {
+			assert.Equal(t, loc, into.Events[0].At.Location())
+			assert.Equal(t, 13, into.Events[0].At.Hour())
+		}
+	})
+
+	t.Run("keeps offsets", func(t *testing.T) {
+		var into time.Time
+		if assert.NoError(t, decoder{}.unmarshal([]byte(`{"@time": "2023-02-28T18:10:10+02:00"}`), &into)) {
+			_, offset := into.Zone()
+			assert.Equal(t, 2*60*60, offset)
+			assert.Equal(t, 18, into.Hour())
+		}
+	})
+
+	t.Run("applies to pages", func(t *testing.T) {
+		set := []byte(`{"@set": {"data": [{"user_name": "foo"}], "after": "next"}}`)
+
+		var page Page
+		if !assert.NoError(t, decoder{opts: DecodeOptions{NamingConvention: SnakeCase}}.unmarshal(set, &page)) {
+			return
+		}
+
+		var users []user
+		if assert.NoError(t, page.Unmarshal(&users)) {
+			assert.Equal(t, "foo", users[0].UserName)
+		}
+	})
+
+	t.Run("snake case", func(t *testing.T) {
+		for name, want := range map[string]string{
+			"ID":          "id",
+			"UserName":    "user_name",
+			"HTTPServer":  "http_server",
+			"already_set": "already_set",
+			"TTLDays":     "ttl_days",
+		} {
+			assert.Equal(t, want, SnakeCase(name))
+		}
+	})
+}
+
+func TestRegisterDecodeHook(t *testing.T) {
+	type entityID string
+
+	RegisterDecodeHook("@ref", func(raw any) (any, error) {
+		ref, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected ref %v", raw)
+		}
+		return entityID(fmt.Sprint(ref["id"])), nil
+	})
+	defer RegisterDecodeHook("@ref", nil)
+
+	var into struct {
+		Owner entityID `fauna:"owner"`
+	}
+	if assert.NoError(t, unmarshal([]byte(`{"owner": {"@ref": {"id": "123", "coll": {"@mod": "User"}}}}`), &into)) {
+		assert.Equal(t, entityID("123"), into.Owner)
+	}
+
+	err := unmarshal([]byte(`{"owner": {"@ref": "123"}}`), &into)
+	assert.ErrorContains(t, err, "decode hook for @ref failed: unexpected ref 123")
+
+	RegisterDecodeHook("@ref", nil)
+	var ref map[string]any
+	if assert.NoError(t, unmarshal([]byte(`{"owner": {"@ref": {"id": "123", "coll": {"@mod": "User"}}}}`), &ref)) {
+		assert.IsType(t, &Ref{}, ref["owner"])
+	}
+}
+
 func TestEncodingFaunaStructs(t *testing.T) {
 	t.Run("encodes Module", func(t *testing.T) {
 		obj := Module{"Foo"}
@@ -279,7 +496,7 @@ func TestEncodingFaunaStructs(t *testing.T) {
 	})
 
 	t.Run("encodes Page", func(t *testing.T) {
-		obj := Page{[]any{"0", "1", "2"}, "foobarbaz"}
+		obj := Page{Data: []any{"0", "1", "2"}, After: "foobarbaz"}
 		roundTripCheck(t, obj, `{"@set":{"data":["0","1","2"],"after":"foobarbaz"}}`)
 	})
 
@@ -362,6 +579,169 @@ func TestEncodingStructs(t *testing.T) {
 
 		roundTripCheck(t, obj, `{"GrandParent":{"Parent":{"Child":"foo","Sibling":"bar"}}}`)
 	})
+
+	type Audit struct {
+		CreatedBy string `fauna:"created_by"`
+		UpdatedBy string `fauna:"updated_by"`
+	}
+
+	t.Run("squashes embedded structs", func(t *testing.T) {
+		obj := struct {
+			Audit
+			Name string `fauna:"name"`
+		}{Audit{"ann", "bob"}, "limes"}
+		roundTripCheck(t, obj, `{"created_by":"ann","updated_by":"bob","name":"limes"}`)
+	})
+
+	t.Run("squashes fields with the squash hint", func(t *testing.T) {
+		obj := struct {
+			Changes Audit  `fauna:",squash"`
+			Name    string `fauna:"name"`
+		}{Audit{"ann", "bob"}, "limes"}
+		roundTripCheck(t, obj, `{"created_by":"ann","updated_by":"bob","name":"limes"}`)
+	})
+
+	t.Run("prefers the struct's own fields to squashed ones", func(t *testing.T) {
+		obj := struct {
+			*Audit
+			CreatedBy string `fauna:"created_by"`
+		}{&Audit{"ann", "bob"}, "cal"}
+		bs := marshalAndCheck(t, obj)
+		assert.JSONEq(t, `{"created_by":"cal","updated_by":"bob"}`, string(bs))
+
+		obj.Audit = nil
+		bs = marshalAndCheck(t, obj)
+		assert.JSONEq(t, `{"created_by":"cal"}`, string(bs))
+	})
+
+	t.Run("keeps embedded structs that are tagged with a name", func(t *testing.T) {
+		obj := struct {
+			Audit `fauna:"audit"`
+		}{Audit{"ann", "bob"}}
+		bs := marshalAndCheck(t, obj)
+		assert.JSONEq(t, `{"audit":{"created_by":"ann","updated_by":"bob"}}`, string(bs))
+	})
+
+	t.Run("rejects squashing other types", func(t *testing.T) {
+		obj := struct {
+			Name string `fauna:",squash"`
+		}{"limes"}
+		_, err := marshal(obj)
+		assert.ErrorContains(t, err, "can't squash field Name")
+	})
+
+	t.Run("keeps unmatched fields in remain maps", func(t *testing.T) {
+		type product struct {
+			Document
+			Name  string         `fauna:"name"`
+			Extra map[string]any `fauna:",remain"`
+		}
+
+		body := []byte(`{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2024-01-01T00:00:00Z"},"name":"limes","stock":{"@int":"3"},"tags":["fruit"]}}`)
+		for _, dec := range []decoder{{}, {opts: DecodeOptions{Strict: true}}} {
+			var p product
+			if assert.NoError(t, dec.unmarshal(body, &p)) {
+				assert.Equal(t, "1", p.ID)
+				assert.Equal(t, "limes", p.Name)
+				assert.Equal(t, map[string]any{"stock": int64(3), "tags": []any{"fruit"}}, p.Extra)
+			}
+		}
+
+		type item struct {
+			Name  string         `fauna:"name"`
+			Extra map[string]any `fauna:",remain"`
+		}
+		roundTripCheck(t, item{"limes", map[string]any{"stock": int64(3)}}, `{"name":"limes","stock":{"@int":"3"}}`)
+
+		bs := marshalAndCheck(t, item{"limes", map[string]any{"name": "lemons"}})
+		assert.JSONEq(t, `{"name":"limes"}`, string(bs))
+	})
+}
+
+type colour struct{ name string }
+
+func (c colour) MarshalText() ([]byte, error) { return []byte("colour:" + c.name), nil }
+
+func (c *colour) UnmarshalText(text []byte) error {
+	if !strings.HasPrefix(string(text), "colour:") {
+		return fmt.Errorf("not a colour: %s", text)
+	}
+	c.name = strings.TrimPrefix(string(text), "colour:")
+	return nil
+}
+
+func TestEncodingMapKeys(t *testing.T) {
+	t.Run("integer keys", func(t *testing.T) {
+		roundTripCheck(t, map[int]string{1: "a", -2: "b"}, `{"1":"a","-2":"b"}`)
+		roundTripCheck(t, map[uint8]bool{255: true}, `{"255":true}`)
+	})
+
+	t.Run("text marshaler keys", func(t *testing.T) {
+		roundTripCheck(t, map[colour]int{{"red"}: 1}, `{"colour:red":{"@int":"1"}}`)
+	})
+
+	t.Run("nested maps", func(t *testing.T) {
+		obj := struct {
+			Counts map[int64]int `fauna:"counts"`
+		}{map[int64]int{7: 3}}
+		roundTripCheck(t, obj, `{"counts":{"7":{"@int":"3"}}}`)
+	})
+
+	t.Run("unsupported keys", func(t *testing.T) {
+		_, err := marshal(map[float64]string{1.5: "a"})
+		assert.ErrorContains(t, err, "unsupported map key type float64")
+
+		var into map[int8]string
+		assert.ErrorContains(t, unmarshal([]byte(`{"300":"a"}`), &into), `invalid map key "300" for int8`)
+
+		var colours map[colour]int
+		assert.ErrorContains(t, unmarshal([]byte(`{"red":{"@int":"1"}}`), &colours), "not a colour")
+	})
+}
+
+type level string
+
+func (l level) MarshalText() ([]byte, error) { return []byte(strings.ToUpper(string(l))), nil }
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch s := strings.ToLower(string(text)); s {
+	case "debug", "info":
+		*l = level(s)
+		return nil
+	}
+	return fmt.Errorf("unknown level %s", text)
+}
+
+type uuid [4]byte
+
+func (u uuid) MarshalText() ([]byte, error) { return []byte(fmt.Sprintf("%x", u[:])), nil }
+
+func (u *uuid) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%02x%02x%02x%02x", &u[0], &u[1], &u[2], &u[3])
+	return err
+}
+
+func TestEncodingTextMarshalers(t *testing.T) {
+	t.Run("scalar types", func(t *testing.T) {
+		roundTripCheck(t, colour{"red"}, `"colour:red"`)
+		roundTripCheck(t, level("info"), `"INFO"`)
+		roundTripCheck(t, uuid{0xde, 0xad, 0xbe, 0xef}, `"deadbeef"`)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		type event struct {
+			ID     uuid    `fauna:"id"`
+			Level  *level  `fauna:"level"`
+			Colour *colour `fauna:"colour"`
+		}
+		info := level("info")
+		roundTripCheck(t, event{uuid{1, 2, 3, 4}, &info, nil}, `{"id":"01020304","level":"INFO","colour":null}`)
+	})
+
+	t.Run("invalid text", func(t *testing.T) {
+		var l level
+		assert.ErrorContains(t, unmarshal([]byte(`"trace"`), &l), "unknown level trace")
+	})
 }
 
 func TestEncodingPointers(t *testing.T) {
@@ -662,3 +1042,229 @@ func TestMarshalEventSourceStructs(t *testing.T) {
 		})
 	})
 }
+
+func TestUnmarshalLenient(t *testing.T) {
+	type Address struct {
+		City string `fauna:"city"`
+	}
+
+	type Profile struct {
+		Age      int       `fauna:"age"`
+		Zip      string    `fauna:"zip"`
+		Score    float64   `fauna:"score"`
+		Active   bool      `fauna:"active"`
+		Joined   time.Time `fauna:"joined"`
+		Address  Address   `fauna:"address"`
+		Verified bool      `fauna:"verified"`
+	}
+
+	decoded, err := decode([]byte(`{
+		"age": "42",
+		"zip": {"@int":"90210"},
+		"score": "9.5",
+		"active": "true",
+		"joined": "2023-02-28T18:10:10Z",
+		"address": "",
+		"verified": true
+	}`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var strict Profile
+	assert.Error(t, decodeInto(decoded, &strict))
+
+	var profile Profile
+	coercions, err := UnmarshalLenient(decoded, &profile)
+	if assert.NoError(t, err) {
+		assert.Equal(t, Profile{
+			Age:      42,
+			Zip:      "90210",
+			Score:    9.5,
+			Active:   true,
+			Joined:   time.Date(2023, 02, 28, 18, 10, 10, 0, time.UTC),
+			Verified: true,
+		}, profile)
+		assert.Len(t, coercions, 6)
+		assert.Contains(t, coercions, Coercion{From: "int64", To: "string", Value: int64(90210)})
+	}
+}
+
+func TestDecodingMalformedValues(t *testing.T) {
+	tests := []string{
+		`{"@int":1}`,
+		`{"@time":{}}`,
+		`{"@ref":"foo"}`,
+		`{"@set":1}`,
+		`{"@doc":[]}`,
+	}
+
+	for _, tt := range tests {
+		var v any
+		assert.Error(t, unmarshal([]byte(tt), &v), tt)
+	}
+
+	t.Run("tolerates a malformed exists flag", func(t *testing.T) {
+		var v any
+		assert.NoError(t, unmarshal([]byte(`{"@ref":{"id":"1","coll":{"@mod":"Foo"},"exists":"no"}}`), &v))
+	})
+}
+
+type directObj struct {
+	Document
+	Name     string             `fauna:"name"`
+	Price    float64            `fauna:"price"`
+	Stock    uint8              `fauna:"stock"`
+	Tags     []string           `fauna:"tags"`
+	Born     *time.Time         `fauna:"born"`
+	Owner    *Module            `fauna:"owner"`
+	Counts   map[string]int     `fauna:"counts"`
+	Extra    any                `fauna:"extra"`
+	Children []directObj        `fauna:"children"`
+	Nested   map[string]*Module `fauna:"nested"`
+}
+
+func TestDecodeResult(t *testing.T) {
+	doc := `{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2024-01-02T03:04:05Z"},` +
+		`"name":"cup\u00e9 \"x\"","price":{"@double":"9.5"},"stock":{"@int":"42"},"tags":["a","b"],` +
+		`"born":{"@date":"2020-01-02"},"owner":{"@mod":"User"},"counts":{"@object":{"@int":{"@int":"1"}}},` +
+		`"extra":{"@doc":{"id":"2","coll":{"@mod":"Foo"},"ts":{"@time":"2024-01-02T03:04:05Z"},"x":{"@long":"7"}}},` +
+		`"children":[{"name":"child","price":{"@int":"3"}},null],"nested":{"a":{"@mod":"A"},"b":null}}}`
+
+	tests := []struct {
+		name   string
+		opts   DecodeOptions
+		body   string
+		into   func() any
+		direct bool
+	}{
+		{"document", DecodeOptions{}, doc, func() any { return &directObj{} }, true},
+		{"pointer to document", DecodeOptions{}, doc, func() any { return new(*directObj) }, false},
+		{"pointer to object", DecodeOptions{}, `{"name":"a"}`, func() any { return new(*directObj) }, true},
+		{"map", DecodeOptions{}, doc, func() any { return &map[string]any{} }, false},
+		{"nil map", DecodeOptions{}, doc, func() any { return new(map[string]any) }, true},
+		{"slice", DecodeOptions{}, `[{"@int":"1"},{"@long":"2"},null]`, func() any { return new([]int64) }, true},
+		{"location", DecodeOptions{Location: time.FixedZone("X", 3600)}, doc, func() any { return &directObj{} }, true},
+		{"strict", DecodeOptions{Strict: true}, doc, func() any { return &directObj{} }, true},
+		{"strict with unknown field", DecodeOptions{Strict: true}, `{"name":"a","other":1}`, func() any { return &directObj{} }, false},
+		{"mismatched type", DecodeOptions{}, `{"name":{"@int":"1"}}`, func() any { return &directObj{} }, false},
+		{"overflow", DecodeOptions{}, `{"stock":{"@int":"300"}}`, func() any { return &directObj{} }, false},
+		{"negative unsigned", DecodeOptions{}, `{"stock":{"@int":"-1"}}`, func() any { return &directObj{} }, false},
+		{"case-insensitive", DecodeOptions{}, `{"NAME":"a"}`, func() any { return &directObj{} }, true},
+		{"repeated field", DecodeOptions{}, `{"NAME":"a","name":"b"}`, func() any { return &directObj{} }, false},
+		{"ref", DecodeOptions{}, `{"@ref":{"id":"1","coll":{"@mod":"Foo"}}}`, func() any { return &directObj{} }, false},
+		{"unsupported type", DecodeOptions{}, `{"set_field":{"@set":"abc"}}`, func() any { return &BusinessObj{} }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := decoder{opts: tt.opts}
+			data, err := d.decode([]byte(tt.body))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			want := tt.into()
+			wantErr := d.decodeInto(data, want)
+
+			got := tt.into()
+			gotErr := d.decodeResult([]byte(tt.body), data, got)
+			assert.Equal(t, wantErr, gotErr)
+			assert.Equal(t, want, got)
+
+			direct := tt.into()
+			assert.Equal(t, tt.direct, d.canDecodeDirect(direct) && d.decodeDirect([]byte(tt.body), direct) == nil)
+		})
+	}
+
+	t.Run("decodes with hooks when registered", func(t *testing.T) {
+		RegisterDecodeHook("@mod", func(raw any) (any, error) { return Module{Name: "hooked"}, nil })
+		defer RegisterDecodeHook("@mod", nil)
+
+		assert.False(t, decoder{}.canDecodeDirect(&directObj{}))
+	})
+}
+
+func FuzzDecode(f *testing.F) {
+	f.Add([]byte(`{"@int":"1234"}`))
+	f.Add([]byte(`{"@set":{"data":[{"@long":"1"}],"after":"abc"}}`))
+	f.Add([]byte(`{"@doc":{"id":"1","coll":{"@mod":"Foo"},"ts":{"@time":"2023-02-28T18:10:10.00001Z"}}}`))
+	f.Add([]byte(`{"@ref":{"name":"Foo","coll":{"@mod":"Foo"},"exists":false,"cause":"gone"}}`))
+	f.Add([]byte(`{"@object":{"@int":{"@double":"1.5"}}}`))
+	f.Add([]byte(`{"name":"a\u00e9","NAME":"b","stock":{"@int":"42"},"tags":["a",null],"born":{"@date":"2020-01-02"},"counts":{"a":{"@long":"1"}},"children":[{"price":{"@double":"1"}}]}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var (
+			anyValue any
+			obj      BusinessObj
+		)
+		_ = unmarshal(body, &anyValue)
+		_ = unmarshal(body, &obj)
+
+		// decoding straight from the JSON must match decoding the value
+		data, err := decode(body)
+		if err != nil {
+			return
+		}
+		var want, got directObj
+		wantErr := decodeInto(data, &want)
+		if gotErr := (decoder{}).decodeResult(body, data, &got); wantErr != nil {
+			assert.Equal(t, wantErr.Error(), gotErr.Error())
+		} else {
+			assert.NoError(t, gotErr)
+			assert.Equal(t, want, got)
+		}
+	})
+}
+
+func TestMarshalMatchesEncode(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 600, time.FixedZone("CET", 3600))
+	nested, _ := FQL(`Product.byId(${id})`, map[string]any{"id": "1"})
+	q, _ := FQL(`let p = ${nested}
+${p}.update(${data})`, map[string]any{
+		"nested": nested,
+		"p":      &Module{"Product"},
+		"data": map[string]any{
+			"name":     "cup \"<&>\"    \x01\t\n\xff é",
+			"price":    9.5,
+			"small":    float32(0.1),
+			"count":    42,
+			"big":      int64(1 << 40),
+			"neg":      int8(-3),
+			"in_stock": true,
+			"none":     nil,
+			"created":  ts,
+			"updated":  &ts,
+			"unset":    (*time.Time)(nil),
+			"tags":     []any{"a", 1, map[string]any{"@int": "conflict"}},
+			"ref":      Ref{ID: "1", Coll: &Module{"Product"}},
+			"struct":   SubBusinessObj{StringField: "s"},
+			"bytes":    []byte("hi"),
+			"uint":     uint64(1 << 63),
+			"strs":     []string{"x"},
+		},
+	})
+
+	for _, v := range []any{
+		queryRequest{Query: q},
+		&queryRequest{Query: q, Arguments: map[string]any{"a": 1, "b": "two"}},
+		streamRequest{Stream: "token", Cursor: "c"},
+		map[string]any{},
+		[]any{},
+	} {
+		e := encoder{numericOverflow: NumericOverflowString}
+		enc, err := e.encode(v, "")
+		if !assert.NoError(t, err) {
+			continue
+		}
+		want, err := json.Marshal(enc)
+		assert.NoError(t, err)
+
+		got, err := e.marshal(v)
+		assert.NoError(t, err)
+		assert.Equal(t, string(want), string(got))
+	}
+
+	_, err := encoder{}.marshal(map[string]any{"uint": uint64(1 << 63)})
+	assert.EqualError(t, err, "numeric value is outside Fauna's type constraints")
+}
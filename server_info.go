@@ -0,0 +1,116 @@
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerInfo describes the Fauna backend a [fauna.Client] talked to, as far as
+// it can be told from the endpoint and response headers.
+type ServerInfo struct {
+	// Endpoint is the URL the client sends requests to.
+	Endpoint string `json:"endpoint"`
+
+	// Region is the region group implied by the endpoint, such as "us" for
+	// db.us.fauna.com, "global" for db.fauna.com, or "local" for a local
+	// container. It is empty for other endpoints.
+	Region string `json:"region,omitempty"`
+
+	// Server is the Server response header, if any.
+	Server string `json:"server,omitempty"`
+
+	// Headers holds the response headers describing the backend: Server, and
+	// any header whose name starts with X-Fauna or Fauna-.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// SchemaVersion is the latest schema version seen by the client.
+	SchemaVersion int64 `json:"schema_version,omitempty"`
+
+	// ObservedAt is when the response the info was taken from was received.
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// ServerInfo runs a trivial query and returns what it tells about the Fauna
+// backend, such as its region and build headers. Use
+// [fauna.Client.LastServerInfo] to avoid the query.
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	q, err := FQL(`null`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.Query(q, QueryContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	return c.LastServerInfo(), nil
+}
+
+// LastServerInfo returns the backend info taken from the last response the
+// client received, or nil if it hasn't received any.
+func (c *Client) LastServerInfo() *ServerInfo {
+	info := c.serverInfo.get()
+	if info != nil {
+		info.SchemaVersion = c.lastSchemaVersion.get()
+	}
+	return info
+}
+
+type lastServerInfo struct {
+	mu   sync.Mutex
+	info *ServerInfo
+}
+
+func (l *lastServerInfo) observe(endpoint string, header http.Header) {
+	info := &ServerInfo{
+		Endpoint:   endpoint,
+		Region:     regionOf(endpoint),
+		Server:     header.Get("Server"),
+		Headers:    map[string]string{},
+		ObservedAt: time.Now().UTC(),
+	}
+
+	for k, v := range header {
+		if k == "Server" || strings.HasPrefix(k, "X-Fauna") || strings.HasPrefix(k, "Fauna-") {
+			info.Headers[k] = strings.Join(v, ", ")
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.info = info
+}
+
+func (l *lastServerInfo) get() *ServerInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.info == nil {
+		return nil
+	}
+
+	info := *l.info
+	return &info
+}
+
+func regionOf(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+
+	switch host := u.Hostname(); {
+	case host == "localhost" || host == "127.0.0.1":
+		return "local"
+	case host == "db.fauna.com":
+		return "global"
+	case strings.HasPrefix(host, "db.") && strings.HasSuffix(host, ".fauna.com"):
+		return strings.TrimSuffix(strings.TrimPrefix(host, "db."), ".fauna.com")
+	default:
+		return ""
+	}
+}
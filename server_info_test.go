@@ -0,0 +1,56 @@
+package fauna_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerInfo(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Server", "fauna")
+		w.Header().Set("X-Fauna-Build", "1234")
+		w.Header().Set("X-Other", "ignored")
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"schema_version":42,"stats":{}}`))
+	})
+	require.Nil(t, client.LastServerInfo())
+	require.Nil(t, client.DebugBundle().Server)
+
+	info, err := client.ServerInfo(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "fauna", info.Server)
+	require.Equal(t, map[string]string{"Server": "fauna", "X-Fauna-Build": "1234"}, info.Headers)
+	require.Equal(t, "local", info.Region)
+	require.Equal(t, int64(42), info.SchemaVersion)
+
+	require.Equal(t, info.Headers, client.DebugBundle().Server.Headers)
+}
+
+func TestServerInfoRegion(t *testing.T) {
+	respond := func(http.RoundTripper) http.RoundTripper {
+		return fauna.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"data":null,"txn_ts":1,"stats":{}}`)),
+			}, nil
+		})
+	}
+
+	for endpoint, region := range map[string]string{
+		fauna.EndpointDefault:     "global",
+		"https://db.us.fauna.com": "us",
+		"https://db.eu.fauna.com": "eu",
+		"https://proxy.internal":  "",
+	} {
+		client := fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(endpoint), fauna.WithMiddleware(respond))
+		info, err := client.ServerInfo(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, region, info.Region, endpoint)
+	}
+}
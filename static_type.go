@@ -0,0 +1,583 @@
+package fauna
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TypeKind is the kind of an FQL type described by a [TypeDescriptor].
+type TypeKind string
+
+const (
+	// TypeNamed is a named type, such as String or a collection, along with
+	// its type arguments, as in Array<String> or Ref<Product>.
+	TypeNamed TypeKind = "named"
+	// TypeObject is an object type, such as { name: String, age?: Number }.
+	TypeObject TypeKind = "object"
+	// TypeTuple is a tuple type, such as [String, Number].
+	TypeTuple TypeKind = "tuple"
+	// TypeUnion is a union type, such as String | Null.
+	TypeUnion TypeKind = "union"
+	// TypeIntersection is an intersection type, such as A & B.
+	TypeIntersection TypeKind = "intersection"
+	// TypeFunction is a function type, such as (x: Number) => String.
+	TypeFunction TypeKind = "function"
+	// TypeLiteral is a literal type, such as "draft", 1 or true.
+	TypeLiteral TypeKind = "literal"
+)
+
+// TypeDescriptor is a parsed FQL type, such as [QuerySuccess.StaticType].
+type TypeDescriptor struct {
+	Kind TypeKind
+
+	// Name is the name of a named type.
+	Name string
+
+	// Args holds the type arguments of a named type, the elements of a tuple
+	// and the members of a union or intersection.
+	Args []*TypeDescriptor
+
+	// Fields holds the fields of an object and the parameters of a function,
+	// in order. Function parameters may be unnamed.
+	Fields []TypeField
+
+	// Wildcard is the type of an object's other fields, as in { *: Any }.
+	Wildcard *TypeDescriptor
+
+	// Returns is the return type of a function.
+	Returns *TypeDescriptor
+
+	// Literal is the FQL of a literal type, such as "draft" with its quotes.
+	Literal string
+}
+
+// TypeField is a field of an object type or a parameter of a function type.
+type TypeField struct {
+	Name     string
+	Type     *TypeDescriptor
+	Optional bool
+
+	// Variadic is set for a rest parameter, as in (...args: Number) => Number.
+	Variadic bool
+}
+
+// ParseStaticType parses an FQL type, such as [QuerySuccess.StaticType]. The
+// shorthand T? is parsed as the union T | Null.
+func ParseStaticType(s string) (*TypeDescriptor, error) {
+	p := &typeParser{src: s}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	t, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok != "" {
+		return nil, p.errorf("unexpected %q", p.tok)
+	}
+	return t, nil
+}
+
+// ParseStaticType parses [QuerySuccess.StaticType]. It returns nil if the
+// query wasn't typechecked.
+func (r *QuerySuccess) ParseStaticType() (*TypeDescriptor, error) {
+	if r.StaticType == "" {
+		return nil, nil
+	}
+	return ParseStaticType(r.StaticType)
+}
+
+// IsNullable reports whether t is Null, or a union with a nullable member.
+func (t *TypeDescriptor) IsNullable() bool {
+	switch t.Kind {
+	case TypeNamed:
+		return t.Name == "Null"
+	case TypeLiteral:
+		return t.Literal == "null"
+	case TypeUnion:
+		for _, arg := range t.Args {
+			if arg.IsNullable() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String formats t as FQL.
+func (t *TypeDescriptor) String() string {
+	var sb strings.Builder
+	t.format(&sb, false)
+	return sb.String()
+}
+
+// format writes t to sb, in parentheses if nested in a union, intersection or
+// function type would change its meaning.
+func (t *TypeDescriptor) format(sb *strings.Builder, nested bool) {
+	switch t.Kind {
+	case TypeNamed:
+		sb.WriteString(t.Name)
+		if len(t.Args) > 0 {
+			sb.WriteByte('<')
+			formatList(sb, t.Args, ", ", false)
+			sb.WriteByte('>')
+		}
+
+	case TypeObject:
+		if len(t.Fields) == 0 && t.Wildcard == nil {
+			sb.WriteString("{}")
+			break
+		}
+		sb.WriteString("{ ")
+		for i, field := range t.Fields {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			field.format(sb)
+		}
+		if t.Wildcard != nil {
+			if len(t.Fields) > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("*: ")
+			t.Wildcard.format(sb, false)
+		}
+		sb.WriteString(" }")
+
+	case TypeTuple:
+		sb.WriteByte('[')
+		formatList(sb, t.Args, ", ", false)
+		sb.WriteByte(']')
+
+	case TypeUnion, TypeIntersection:
+		sep := " | "
+		if t.Kind == TypeIntersection {
+			sep = " & "
+		}
+		if nested {
+			sb.WriteByte('(')
+		}
+		formatList(sb, t.Args, sep, true)
+		if nested {
+			sb.WriteByte(')')
+		}
+
+	case TypeFunction:
+		if nested {
+			sb.WriteByte('(')
+		}
+		sb.WriteByte('(')
+		for i, param := range t.Fields {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			param.format(sb)
+		}
+		sb.WriteString(") => ")
+		t.Returns.format(sb, false)
+		if nested {
+			sb.WriteByte(')')
+		}
+
+	case TypeLiteral:
+		sb.WriteString(t.Literal)
+	}
+}
+
+func formatList(sb *strings.Builder, types []*TypeDescriptor, sep string, nested bool) {
+	for i, t := range types {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		t.format(sb, nested)
+	}
+}
+
+func (f TypeField) format(sb *strings.Builder) {
+	if f.Variadic {
+		sb.WriteString("...")
+	}
+	if f.Name != "" {
+		if isTypeIdent(f.Name) {
+			sb.WriteString(f.Name)
+		} else {
+			sb.WriteString(strconv.Quote(f.Name))
+		}
+		if f.Optional {
+			sb.WriteByte('?')
+		}
+		sb.WriteString(": ")
+	}
+	f.Type.format(sb, false)
+}
+
+func isTypeIdent(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// typeParser is a recursive descent parser of FQL types. From the loosest to
+// the tightest binding, types are functions, unions, intersections, optional
+// types and the rest.
+type typeParser struct {
+	src string
+	pos int
+	// tok is the current token, and is empty at the end of src.
+	tok string
+	// tokPos is the position of tok in src.
+	tokPos int
+}
+
+func (p *typeParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid static type %q at position %d: %s", p.src, p.tokPos, fmt.Sprintf(format, args...))
+}
+
+func (p *typeParser) next() error {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	p.tokPos = p.pos
+	if p.pos == len(p.src) {
+		p.tok = ""
+		return nil
+	}
+
+	rest := p.src[p.pos:]
+	n := 1
+	switch c := rest[0]; {
+	case strings.HasPrefix(rest, "=>"):
+		n = 2
+	case strings.HasPrefix(rest, "..."):
+		n = 3
+	case c == '"' || c == '\'':
+		n = 1
+		for n < len(rest) && rest[n] != c {
+			if rest[n] == '\\' {
+				n++
+			}
+			n++
+		}
+		if n >= len(rest) {
+			return p.errorf("unterminated string")
+		}
+		n++
+	case c == '-' || (c >= '0' && c <= '9'):
+		for n < len(rest) && (rest[n] == '.' || (rest[n] >= '0' && rest[n] <= '9')) {
+			n++
+		}
+	case c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)):
+		n = len(rest)
+		for i, r := range rest {
+			if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				n = i
+				break
+			}
+		}
+		if n == 0 {
+			return p.errorf("unexpected %q", []rune(rest)[0])
+		}
+	case strings.IndexByte("<>{}[](),:;|&?*", c) < 0:
+		return p.errorf("unexpected %q", c)
+	}
+
+	p.tok = rest[:n]
+	p.pos += n
+	return nil
+}
+
+func (p *typeParser) expect(tok string) error {
+	if p.tok != tok {
+		if p.tok == "" {
+			return p.errorf("expected %q but found the end", tok)
+		}
+		return p.errorf("expected %q but found %q", tok, p.tok)
+	}
+	return p.next()
+}
+
+func (p *typeParser) parseType() (*TypeDescriptor, error) {
+	t, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok != "=>" {
+		return t, nil
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	returns, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return &TypeDescriptor{Kind: TypeFunction, Fields: []TypeField{{Type: t}}, Returns: returns}, nil
+}
+
+func (p *typeParser) parseUnion() (*TypeDescriptor, error) {
+	return p.parseMembers(TypeUnion, "|", p.parseIntersection)
+}
+
+func (p *typeParser) parseIntersection() (*TypeDescriptor, error) {
+	return p.parseMembers(TypeIntersection, "&", p.parseOptional)
+}
+
+// parseMembers parses the members of a union or intersection, flattening
+// nested ones of the same kind.
+func (p *typeParser) parseMembers(kind TypeKind, sep string, parse func() (*TypeDescriptor, error)) (*TypeDescriptor, error) {
+	var members []*TypeDescriptor
+	for {
+		t, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == kind {
+			members = append(members, t.Args...)
+		} else {
+			members = append(members, t)
+		}
+
+		if p.tok != sep {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(members) == 1 {
+		return members[0], nil
+	}
+	return &TypeDescriptor{Kind: kind, Args: members}, nil
+}
+
+func (p *typeParser) parseOptional() (*TypeDescriptor, error) {
+	t, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok == "?" {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		members := []*TypeDescriptor{t, {Kind: TypeNamed, Name: "Null"}}
+		if t.Kind == TypeUnion {
+			members = append(t.Args, members[1])
+		}
+		t = &TypeDescriptor{Kind: TypeUnion, Args: members}
+	}
+	return t, nil
+}
+
+func (p *typeParser) parsePrimary() (*TypeDescriptor, error) {
+	tok := p.tok
+	switch {
+	case tok == "":
+		return nil, p.errorf("unexpected end")
+
+	case tok == "{":
+		return p.parseObject()
+
+	case tok == "[":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		elems, err := p.parseList("]")
+		if err != nil {
+			return nil, err
+		}
+		return &TypeDescriptor{Kind: TypeTuple, Args: elems}, nil
+
+	case tok == "(":
+		return p.parseParens()
+
+	case tok[0] == '"' || tok[0] == '\'' || tok[0] == '-' || (tok[0] >= '0' && tok[0] <= '9'),
+		tok == "true", tok == "false", tok == "null":
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		return &TypeDescriptor{Kind: TypeLiteral, Literal: tok}, nil
+
+	case isTypeIdent(tok):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		t := &TypeDescriptor{Kind: TypeNamed, Name: tok}
+		if p.tok == "<" {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			args, err := p.parseList(">")
+			if err != nil {
+				return nil, err
+			}
+			t.Args = args
+		}
+		return t, nil
+	}
+
+	return nil, p.errorf("unexpected %q", tok)
+}
+
+// parseList parses comma separated types up to end.
+func (p *typeParser) parseList(end string) ([]*TypeDescriptor, error) {
+	var types []*TypeDescriptor
+	for p.tok != end {
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+
+		if p.tok != "," {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return types, p.expect(end)
+}
+
+func (p *typeParser) parseObject() (*TypeDescriptor, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	t := &TypeDescriptor{Kind: TypeObject}
+	for p.tok != "}" {
+		if p.tok == "*" {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			wildcard, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			t.Wildcard = wildcard
+		} else {
+			field, err := p.parseField()
+			if err != nil {
+				return nil, err
+			}
+			if field.Name == "" || field.Variadic {
+				return nil, p.errorf("expected a field name")
+			}
+			t.Fields = append(t.Fields, field)
+		}
+
+		if p.tok != "," && p.tok != ";" {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return t, p.expect("}")
+}
+
+// parseField parses an object field or a function parameter, which may have
+// no name.
+func (p *typeParser) parseField() (TypeField, error) {
+	var field TypeField
+	if p.tok == "..." {
+		field.Variadic = true
+		if err := p.next(); err != nil {
+			return field, err
+		}
+	}
+
+	// A name is followed by a colon, or by ?: for optional fields, which
+	// needs looking ahead to tell from an optional type.
+	if name, ok := p.fieldName(); ok {
+		rest := strings.TrimLeftFunc(p.src[p.pos:], unicode.IsSpace)
+		if strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "?") && strings.HasPrefix(strings.TrimLeftFunc(rest[1:], unicode.IsSpace), ":") {
+			field.Name = name
+			if err := p.next(); err != nil {
+				return field, err
+			}
+			if p.tok == "?" {
+				field.Optional = true
+				if err := p.next(); err != nil {
+					return field, err
+				}
+			}
+			if err := p.expect(":"); err != nil {
+				return field, err
+			}
+		}
+	}
+
+	t, err := p.parseType()
+	field.Type = t
+	return field, err
+}
+
+func (p *typeParser) fieldName() (string, bool) {
+	if isTypeIdent(p.tok) {
+		return p.tok, true
+	}
+	if p.tok != "" && (p.tok[0] == '"' || p.tok[0] == '\'') {
+		if p.tok[0] == '\'' {
+			return strings.ReplaceAll(p.tok[1:len(p.tok)-1], `\'`, `'`), true
+		}
+		if name, err := strconv.Unquote(p.tok); err == nil {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// parseParens parses a parenthesized type, or the parameters of a function
+// type, which are told apart by the => following them.
+func (p *typeParser) parseParens() (*TypeDescriptor, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var params []TypeField
+	for p.tok != ")" {
+		param, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+
+		if p.tok != "," {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if p.tok != "=>" {
+		if len(params) != 1 || params[0].Name != "" || params[0].Variadic {
+			return nil, p.errorf("expected \"=>\" after function parameters")
+		}
+		return params[0].Type, nil
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	returns, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return &TypeDescriptor{Kind: TypeFunction, Fields: params, Returns: returns}, nil
+}
@@ -0,0 +1,126 @@
+package fauna_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStaticType(t *testing.T) {
+	named := func(name string, args ...*fauna.TypeDescriptor) *fauna.TypeDescriptor {
+		return &fauna.TypeDescriptor{Kind: fauna.TypeNamed, Name: name, Args: args}
+	}
+
+	t.Run("parses types", func(t *testing.T) {
+		tests := []struct {
+			name string
+			in   string
+			want *fauna.TypeDescriptor
+		}{
+			{"named", "String", named("String")},
+			{"type arguments", "Set<Ref<Product>>", named("Set", named("Ref", named("Product")))},
+			{"union", "String | Number | Null", &fauna.TypeDescriptor{
+				Kind: fauna.TypeUnion,
+				Args: []*fauna.TypeDescriptor{named("String"), named("Number"), named("Null")},
+			}},
+			{"optional", "Product?", &fauna.TypeDescriptor{
+				Kind: fauna.TypeUnion,
+				Args: []*fauna.TypeDescriptor{named("Product"), named("Null")},
+			}},
+			{"intersection binds tighter than union", "A & B | C", &fauna.TypeDescriptor{
+				Kind: fauna.TypeUnion,
+				Args: []*fauna.TypeDescriptor{
+					{Kind: fauna.TypeIntersection, Args: []*fauna.TypeDescriptor{named("A"), named("B")}},
+					named("C"),
+				},
+			}},
+			{"object", `{ name: String, price?: Number, "in stock": Boolean, *: Any }`, &fauna.TypeDescriptor{
+				Kind: fauna.TypeObject,
+				Fields: []fauna.TypeField{
+					{Name: "name", Type: named("String")},
+					{Name: "price", Type: named("Number"), Optional: true},
+					{Name: "in stock", Type: named("Boolean")},
+				},
+				Wildcard: named("Any"),
+			}},
+			{"tuple", `[String, 1, "a", true]`, &fauna.TypeDescriptor{
+				Kind: fauna.TypeTuple,
+				Args: []*fauna.TypeDescriptor{
+					named("String"),
+					{Kind: fauna.TypeLiteral, Literal: "1"},
+					{Kind: fauna.TypeLiteral, Literal: `"a"`},
+					{Kind: fauna.TypeLiteral, Literal: "true"},
+				},
+			}},
+			{"function", "(x: Number, ...rest: String) => Null", &fauna.TypeDescriptor{
+				Kind: fauna.TypeFunction,
+				Fields: []fauna.TypeField{
+					{Name: "x", Type: named("Number")},
+					{Name: "rest", Type: named("String"), Variadic: true},
+				},
+				Returns: named("Null"),
+			}},
+			{"short function", "Number => Number | Null", &fauna.TypeDescriptor{
+				Kind:    fauna.TypeFunction,
+				Fields:  []fauna.TypeField{{Type: named("Number")}},
+				Returns: &fauna.TypeDescriptor{Kind: fauna.TypeUnion, Args: []*fauna.TypeDescriptor{named("Number"), named("Null")}},
+			}},
+			{"parentheses", "(String)", named("String")},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := fauna.ParseStaticType(tt.in)
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("formats types", func(t *testing.T) {
+		for _, in := range []string{
+			"Array<String>",
+			"{ id: ID, items: Array<{ sku: String, qty?: Number }>, *: Any }",
+			"(String | Null) & Named",
+			"((a: Number) => String) | Null",
+			`{ "first name": String }`,
+			"{}",
+			"[]",
+		} {
+			got, err := fauna.ParseStaticType(in)
+			require.NoError(t, err, in)
+			require.Equal(t, in, got.String())
+		}
+	})
+
+	t.Run("reports nullable types", func(t *testing.T) {
+		for in, want := range map[string]bool{"Null": true, "String?": true, "String | null": true, "String": false, "Array<Null>": false} {
+			got, err := fauna.ParseStaticType(in)
+			require.NoError(t, err)
+			require.Equal(t, want, got.IsNullable(), in)
+		}
+	})
+
+	t.Run("rejects invalid types", func(t *testing.T) {
+		for _, in := range []string{"", "Array<String", "{ name String }", "(a: Number)", "String Number", `"open`, "#"} {
+			_, err := fauna.ParseStaticType(in)
+			require.Error(t, err, in)
+		}
+	})
+
+	t.Run("parses the static type of results", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"data":[],"static_type":"Array<Product>","txn_ts":1,"stats":{}}`))
+		})
+
+		q, _ := fauna.FQL(`[]`, nil)
+		res, err := client.Query(q)
+		require.NoError(t, err)
+
+		typ, err := res.ParseStaticType()
+		require.NoError(t, err)
+		require.Equal(t, named("Array", named("Product")), typ)
+	})
+}
@@ -1,10 +1,14 @@
 package fauna
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net"
+	"sync"
+	"time"
 )
 
 // EventType represents a Fauna's event type.
@@ -38,11 +42,24 @@ type Event struct {
 	Data any
 	// Stats contains the ops acquired to process the event.
 	Stats Stats
+	// Error is set for error events returned in a [fauna.FeedPage]. Streams
+	// return error events from [fauna.EventStream.Next] instead.
+	Error *ErrEvent
+
+	decoder *decoder
+}
+
+// Time returns the event's [fauna.Event.TxnTime] as a [time.Time] in UTC.
+func (e *Event) Time() time.Time {
+	return time.UnixMicro(e.TxnTime).UTC()
 }
 
 // Unmarshal will unmarshal the raw [fauna.Event.Data] (if present) into the
 // known type provided as `into`. `into` must be a pointer to a map or struct.
 func (e *Event) Unmarshal(into any) error {
+	if e.decoder != nil {
+		return e.decoder.decodeInto(e.Data, into)
+	}
 	return decodeInto(e.Data, into)
 }
 
@@ -74,6 +91,31 @@ func (e *ErrEvent) Unmarshal(into any) error {
 	return decodeInto(e.Abort, into)
 }
 
+// ErrStreamIdle is returned by [fauna.EventStream.Next] when no event, not
+// even a [fauna.StatusEvent], arrived within the [fauna.StreamIdleTimeout].
+// The stream reconnects from the last event received before returning it, so
+// Next can be called again unless Err is set.
+type ErrStreamIdle struct {
+	// Timeout is the idle timeout that expired.
+	Timeout time.Duration
+
+	// Err is the error reconnecting the stream, if it failed.
+	Err error
+}
+
+// Error provides the underlying error message.
+func (e *ErrStreamIdle) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("no event received for %s, reconnecting failed: %s", e.Timeout, e.Err)
+	}
+	return fmt.Sprintf("no event received for %s, reconnected", e.Timeout)
+}
+
+// Unwrap returns the reconnection error, if any.
+func (e *ErrStreamIdle) Unwrap() error {
+	return e.Err
+}
+
 // EventStream is an iterator of Fauna events.
 //
 // The next available event can be obtained by calling the
@@ -89,15 +131,31 @@ func (e *ErrEvent) Unmarshal(into any) error {
 // to Fauna via an HTTP/1.x proxy, be aware of the events iterator closing time.
 type EventStream struct {
 	client     *Client
+	ctx        context.Context
 	stream     EventSource
-	byteStream io.ReadCloser
 	decoder    *json.Decoder
 	lastCursor string
+	eventTypes []EventType
+
+	// mu guards byteStream and closed, as Close may be called from another
+	// goroutine than Next.
+	mu         sync.Mutex
+	byteStream io.ReadCloser
 	closed     bool
+
+	idleTimeout time.Duration
+	idle        *idleReader
+
+	// projection selects the fields of event data, if set with StreamFields.
+	projection *fieldTree
+
+	// values decodes event data, with the client's or the stream's own
+	// DecodeOptions.
+	values decoder
 }
 
-func subscribe(client *Client, stream EventSource, opts ...StreamOptFn) (*EventStream, error) {
-	events := &EventStream{client: client, stream: stream}
+func subscribe(ctx context.Context, client *Client, stream EventSource, opts ...StreamOptFn) (*EventStream, error) {
+	events := &EventStream{client: client, ctx: ctx, stream: stream, values: client.decoder}
 	if err := events.reconnect(opts...); err != nil {
 		return nil, err
 	}
@@ -107,8 +165,9 @@ func subscribe(client *Client, stream EventSource, opts ...StreamOptFn) (*EventS
 func (es *EventStream) reconnect(opts ...StreamOptFn) error {
 	req := streamRequest{
 		apiRequest: apiRequest{
-			es.client.ctx,
-			es.client.headers,
+			Context:    es.ctx,
+			Headers:    es.client.headers,
+			idempotent: true,
 		},
 		Stream: es.stream,
 		Cursor: es.lastCursor,
@@ -118,18 +177,54 @@ func (es *EventStream) reconnect(opts ...StreamOptFn) error {
 		streamOptionFn(&req)
 	}
 
+	if req.eventTypes != nil {
+		es.eventTypes = req.eventTypes
+	}
+	if req.idleTimeout > 0 {
+		es.idleTimeout = req.idleTimeout
+	}
+	if req.decoder != nil {
+		es.values = *req.decoder
+	}
+	if req.fields != nil {
+		projection, err := newFieldTree(req.fields)
+		if err != nil {
+			return err
+		}
+		es.projection = projection
+	}
+
 	byteStream, err := req.do(es.client)
 	if err != nil {
 		return err
 	}
 
+	var idle *idleReader
+	if es.idleTimeout > 0 {
+		idle = newIdleReader(byteStream, es.idleTimeout)
+		byteStream = idle
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.closed {
+		_ = byteStream.Close()
+		return errors.New("stream is closed")
+	}
+
+	es.idle = idle
 	es.byteStream = byteStream
-	es.decoder = json.NewDecoder(byteStream)
+	es.decoder = es.values.jsonDecoder(byteStream)
 	return nil
 }
 
-// Close gracefully closes the events iterator. See [fauna.EventStream] for details.
+// Close gracefully closes the events iterator. See [fauna.EventStream] for
+// details. It is safe to call while another goroutine is blocked in
+// [fauna.EventStream.Next], which then returns an error.
 func (es *EventStream) Close() (err error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
 	if !es.closed {
 		es.closed = true
 		err = es.byteStream.Close()
@@ -137,6 +232,12 @@ func (es *EventStream) Close() (err error) {
 	return
 }
 
+func (es *EventStream) isClosed() bool {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.closed
+}
+
 type rawEvent = struct {
 	Type    EventType `json:"type"`
 	TxnTime int64     `json:"txn_ts"`
@@ -146,21 +247,48 @@ type rawEvent = struct {
 	Stats   Stats     `json:"stats"`
 }
 
-// Next blocks until the next event is available.
+// Next blocks until the next event is available. If the stream was opened with
+// [fauna.StreamEvents], events of other types are skipped.
 //
 // Note that network errors of type [fauna.ErrEvent] are considered fatal and
 // close the underlying stream. Calling next after an error event occurs will
 // return an error.
 func (es *EventStream) Next(event *Event) (err error) {
+	for {
+		if err = es.next(event); err != nil || es.wants(event.Type) {
+			return
+		}
+	}
+}
+
+func (es *EventStream) wants(eventType EventType) bool {
+	if len(es.eventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range es.eventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (es *EventStream) next(event *Event) (err error) {
 	raw := rawEvent{}
 	if err = es.decoder.Decode(&raw); err == nil {
 		es.onNextEvent(&raw)
-		err = convertRawEvent(&raw, event)
+		err = es.values.convertRawEvent(&raw, event)
 		var errEvent *ErrEvent
 		if errors.As(err, &errEvent) {
 			_ = es.Close() // no more events are coming
+		} else if err == nil && es.projection != nil && event.Data != nil {
+			event.Data = es.projection.apply(event.Data)
 		}
-	} else if !es.closed {
+	} else if !es.isClosed() && es.idle != nil && es.idle.expired() {
+		_ = es.byteStream.Close()
+		err = &ErrStreamIdle{Timeout: es.idleTimeout, Err: es.reconnect()}
+	} else if !es.isClosed() {
 		// NOTE: This code tries to resume streams on network and IO errors. It
 		// presumes that if the service is unavailable, the reconnect call will
 		// fail. Automatic retries and backoff mechanisms are implemented at the
@@ -168,29 +296,70 @@ func (es *EventStream) Next(event *Event) (err error) {
 		var netError net.Error
 		if errors.As(err, &netError) || err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
 			if err = es.reconnect(); err == nil {
-				err = es.Next(event)
+				err = es.next(event)
 			}
 		}
 	}
 	return
 }
 
+// idleReader closes the underlying reader if a read from it blocks for longer
+// than the timeout. The timer only runs while a read is pending, so time spent
+// by the application between calls to Next doesn't count as idle.
+type idleReader struct {
+	rc      io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+
+	mu       sync.Mutex
+	timedOut bool
+}
+
+func newIdleReader(rc io.ReadCloser, timeout time.Duration) *idleReader {
+	r := &idleReader{rc: rc, timeout: timeout}
+	r.timer = time.AfterFunc(timeout, func() {
+		r.mu.Lock()
+		r.timedOut = true
+		r.mu.Unlock()
+		_ = rc.Close()
+	})
+	r.timer.Stop()
+	return r
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	r.timer.Reset(r.timeout)
+	defer r.timer.Stop()
+	return r.rc.Read(p)
+}
+
+func (r *idleReader) Close() error {
+	r.timer.Stop()
+	return r.rc.Close()
+}
+
+func (r *idleReader) expired() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.timedOut
+}
+
 func (es *EventStream) onNextEvent(event *rawEvent) {
 	es.client.lastTxnTime.sync(event.TxnTime)
 	es.lastCursor = event.Cursor
 }
 
-func convertRawEvent(raw *rawEvent, event *Event) (err error) {
+func (d decoder) convertRawEvent(raw *rawEvent, event *Event) (err error) {
 	if raw.Error != nil {
 		if raw.Error.Abort != nil {
-			if raw.Error.Abort, err = convert(false, raw.Error.Abort); err != nil {
+			if raw.Error.Abort, err = d.convert(raw.Error.Abort); err != nil {
 				return
 			}
 		}
 		err = raw.Error
 	} else {
 		if raw.Data != nil {
-			if raw.Data, err = convert(false, raw.Data); err != nil {
+			if raw.Data, err = d.convert(raw.Data); err != nil {
 				return
 			}
 		}
@@ -198,6 +367,7 @@ func convertRawEvent(raw *rawEvent, event *Event) (err error) {
 		event.TxnTime = raw.TxnTime
 		event.Cursor = raw.Cursor
 		event.Data = raw.Data
+		event.decoder = &d
 		event.Stats = raw.Stats
 	}
 	return
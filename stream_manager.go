@@ -0,0 +1,297 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SubscriptionEvent is an event or error delivered by a [StreamManager],
+// along with the name of the subscription it came from.
+type SubscriptionEvent struct {
+	// Name is the name the subscription was added with.
+	Name string
+
+	// Event is the event received, or nil if Err is set.
+	Event *Event
+
+	// Err is an error from the subscription. If it is an [ErrEvent], the
+	// subscription has ended. Other errors are followed by attempts to
+	// reconnect.
+	Err error
+}
+
+// StreamManager runs many event stream subscriptions, each in its own
+// goroutine, and delivers their events on a single channel. Subscriptions are
+// reconnected from their last event when their stream fails.
+type StreamManager struct {
+	client *Client
+	events chan SubscriptionEvent
+	done   chan struct{}
+
+	mu     sync.Mutex
+	subs   map[string]*subscription
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type subscription struct {
+	name   string
+	source EventSource
+	opts   []StreamOptFn
+	stop   chan struct{}
+
+	mu     sync.Mutex
+	stream *EventStream
+}
+
+// NewStreamManager returns a [StreamManager] subscribing with the client.
+// Call [StreamManager.Close] to stop all subscriptions.
+func (c *Client) NewStreamManager() *StreamManager {
+	return &StreamManager{
+		client: c,
+		events: make(chan SubscriptionEvent),
+		done:   make(chan struct{}),
+		subs:   map[string]*subscription{},
+	}
+}
+
+// Events returns the channel events of all subscriptions are delivered on.
+// It is closed by [StreamManager.Close].
+func (m *StreamManager) Events() <-chan SubscriptionEvent {
+	return m.events
+}
+
+// Subscribe opens a stream on source and delivers its events under name. It
+// returns an error if the stream can't be opened, or if name is already in
+// use.
+func (m *StreamManager) Subscribe(name string, source EventSource, opts ...StreamOptFn) error {
+	sub := &subscription{name: name, source: source, opts: opts, stop: make(chan struct{})}
+
+	// reserve the name, so the stream can be opened without holding the lock
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return errors.New("stream manager is closed")
+	}
+	if _, exists := m.subs[name]; exists {
+		m.mu.Unlock()
+		return fmt.Errorf("subscription %q already exists", name)
+	}
+	m.subs[name] = sub
+	m.mu.Unlock()
+
+	stream, err := m.client.Stream(source, opts...)
+	if err != nil {
+		m.remove(sub)
+		return err
+	}
+
+	sub.mu.Lock()
+	sub.stream = stream
+	sub.mu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.subs[name] != sub {
+		_ = sub.close()
+		return fmt.Errorf("subscription %q was removed while subscribing", name)
+	}
+
+	m.wg.Add(1)
+	go m.run(sub)
+	return nil
+}
+
+// Unsubscribe stops the subscription with the given name.
+func (m *StreamManager) Unsubscribe(name string) error {
+	m.mu.Lock()
+	sub, ok := m.subs[name]
+	delete(m.subs, name)
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("subscription %q doesn't exist", name)
+	}
+	return sub.close()
+}
+
+// Close stops all subscriptions, waits for their goroutines to exit, and
+// closes the events channel.
+func (m *StreamManager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+	subs := m.subs
+	m.subs = map[string]*subscription{}
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, sub := range subs {
+		if err := sub.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	m.wg.Wait()
+	close(m.events)
+	return firstErr
+}
+
+func (m *StreamManager) run(sub *subscription) {
+	defer m.wg.Done()
+
+	for attempt := 0; ; {
+		event := &Event{}
+		err := sub.current().Next(event)
+		if sub.stopped() {
+			return
+		}
+
+		if err == nil {
+			attempt = 0
+			if !m.deliver(sub, SubscriptionEvent{Name: sub.name, Event: event}) {
+				return
+			}
+			continue
+		}
+
+		// an error event ends the subscription, so its name is free again by
+		// the time the error is delivered
+		var errEvent *ErrEvent
+		if errors.As(err, &errEvent) {
+			m.forget(sub)
+			_ = m.deliver(sub, SubscriptionEvent{Name: sub.name, Err: err})
+			_ = sub.close()
+			return
+		}
+
+		if !m.deliver(sub, SubscriptionEvent{Name: sub.name, Err: err}) {
+			return
+		}
+
+		// the stream reconnected by itself
+		var idleErr *ErrStreamIdle
+		if errors.As(err, &idleErr) && idleErr.Err == nil {
+			continue
+		}
+
+		for {
+			attempt++
+			if !sub.sleep(m.client.backoff(attempt)) {
+				return
+			}
+
+			if reconnectErr := sub.reconnect(m.client); reconnectErr == nil {
+				break
+			} else if !m.deliver(sub, SubscriptionEvent{Name: sub.name, Err: reconnectErr}) {
+				return
+			}
+		}
+	}
+}
+
+func (m *StreamManager) deliver(sub *subscription, event SubscriptionEvent) bool {
+	select {
+	case m.events <- event:
+		return true
+	case <-sub.stop:
+		return false
+	case <-m.done:
+		return false
+	}
+}
+
+// forget removes sub from the manager without stopping it.
+func (m *StreamManager) forget(sub *subscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.subs[sub.name] == sub {
+		delete(m.subs, sub.name)
+	}
+}
+
+func (m *StreamManager) remove(sub *subscription) {
+	m.forget(sub)
+	_ = sub.close()
+}
+
+func (s *subscription) current() *EventStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream
+}
+
+// reconnect replaces the subscription's stream with one resuming after its
+// last event.
+func (s *subscription) reconnect(client *Client) error {
+	s.mu.Lock()
+	cursor := s.stream.lastCursor
+	s.mu.Unlock()
+
+	opts := s.opts
+	if cursor != "" {
+		opts = append(append([]StreamOptFn{}, s.opts...), func(req *streamRequest) {
+			req.StartTS = 0
+			req.Cursor = cursor
+		})
+	}
+
+	stream, err := client.Stream(s.source, opts...)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_ = s.stream.Close()
+	s.stream = stream
+	if s.stopped() {
+		return stream.Close()
+	}
+	return nil
+}
+
+func (s *subscription) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+func (s *subscription) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *subscription) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped() {
+		return nil
+	}
+	close(s.stop)
+
+	if s.stream == nil {
+		return nil
+	}
+	return s.stream.Close()
+}
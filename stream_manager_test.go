@@ -0,0 +1,70 @@
+package fauna_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamManager(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		switch {
+		case strings.Contains(string(body), `"token":"products"`):
+			_, _ = w.Write([]byte(`{"type":"add","txn_ts":1,"cursor":"p1","data":{"@int":"1"}}` + "\n"))
+		case strings.Contains(string(body), `"token":"orders"`):
+			_, _ = w.Write([]byte(`{"type":"add","txn_ts":1,"cursor":"o1","data":{"@int":"2"}}` + "\n" +
+				`{"type":"error","txn_ts":2,"cursor":"o2","error":{"code":"permission_denied","message":"denied"}}` + "\n"))
+		}
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+
+	manager := client.NewStreamManager()
+	require.NoError(t, manager.Subscribe("products", "products"))
+	require.NoError(t, manager.Subscribe("orders", "orders"))
+	require.Error(t, manager.Subscribe("orders", "orders"))
+
+	cursors := map[string]string{}
+	var failed []string
+	deadline := time.After(5 * time.Second)
+	for len(cursors) < 2 || len(failed) < 1 {
+		var event fauna.SubscriptionEvent
+		select {
+		case event = <-manager.Events():
+		case <-deadline:
+			t.Fatalf("timed out waiting for events, got %v and errors from %v", cursors, failed)
+		}
+
+		if event.Err != nil {
+			var errEvent *fauna.ErrEvent
+			require.ErrorAs(t, event.Err, &errEvent)
+			failed = append(failed, event.Name)
+			continue
+		}
+		cursors[event.Name] = event.Event.Cursor
+	}
+
+	require.Equal(t, map[string]string{"products": "p1", "orders": "o1"}, cursors)
+	require.Equal(t, []string{"orders"}, failed)
+	require.Error(t, manager.Unsubscribe("orders"), "ended subscriptions are removed")
+
+	require.NoError(t, manager.Unsubscribe("products"))
+	require.NoError(t, manager.Close())
+
+	_, open := <-manager.Events()
+	require.False(t, open)
+	require.Error(t, manager.Subscribe("products", "products"))
+}
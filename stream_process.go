@@ -0,0 +1,108 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	processAttemptsDefault = 3
+	processBackoffDefault  = 100 * time.Millisecond
+)
+
+type processOptions struct {
+	attempts   int
+	backoff    time.Duration
+	deadLetter func(event Event, err error)
+	checkpoint func(cursor string) error
+}
+
+// Process reads the stream's events and calls handler with each of them,
+// except [fauna.StatusEvent]s, until ctx is done or the stream fails. An
+// event the handler returns an error for is retried with backoff, and after
+// the last attempt is handed to the [fauna.ProcessDeadLetter] function, if
+// set. Otherwise Process returns the handler's error. See [fauna.ProcessOptFn]
+// for the options, such as [fauna.ProcessCheckpoint] to keep track of the
+// processed events.
+//
+// Process closes the stream when ctx is done, and then returns ctx.Err().
+// An [fauna.ErrStreamIdle] the stream recovered from doesn't stop it.
+func (es *EventStream) Process(ctx context.Context, handler func(event Event) error, opts ...ProcessOptFn) error {
+	options := processOptions{attempts: processAttemptsDefault, backoff: processBackoffDefault}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.attempts <= 0 || options.backoff < 0 {
+		return fmt.Errorf("process needs positive attempts and a non-negative backoff, got %d and %s", options.attempts, options.backoff)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = es.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var event Event
+		if err := es.Next(&event); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var idleErr *ErrStreamIdle
+			if errors.As(err, &idleErr) && idleErr.Err == nil {
+				continue
+			}
+			return err
+		}
+
+		if event.Type == StatusEvent {
+			continue
+		}
+
+		if err := options.handle(ctx, event, handler); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if options.deadLetter == nil {
+				return err
+			}
+			options.deadLetter(event, err)
+		}
+
+		if options.checkpoint != nil {
+			if err := options.checkpoint(event.Cursor); err != nil {
+				return fmt.Errorf("checkpoint failed: %w", err)
+			}
+		}
+	}
+}
+
+// handle calls handler with event until it succeeds or runs out of attempts,
+// returning the last error.
+func (o *processOptions) handle(ctx context.Context, event Event, handler func(event Event) error) (err error) {
+	backoff := o.backoff
+	for attempt := 1; ; attempt++ {
+		if err = handler(event); err == nil || attempt >= o.attempts {
+			return
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
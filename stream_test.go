@@ -1,8 +1,13 @@
 package fauna_test
 
 import (
+	"context"
 	"errors"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fauna/fauna-go/v3"
 	"github.com/stretchr/testify/require"
@@ -215,3 +220,227 @@ func TestStreaming(t *testing.T) {
 		})
 	})
 }
+
+func TestStreamEventFilter(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"type":"status","txn_ts":1,"cursor":"a"}
+{"type":"add","txn_ts":2,"cursor":"b","data":{"@int":"1"}}
+{"type":"update","txn_ts":3,"cursor":"c","data":{"@int":"2"}}
+{"type":"remove","txn_ts":4,"cursor":"d","data":{"@int":"3"}}
+{"type":"add","txn_ts":5,"cursor":"e","data":{"@int":"4"}}
+`))
+	})
+
+	events, err := client.Stream("token", fauna.StreamEvents(fauna.AddEvent, fauna.RemoveEvent))
+	require.NoError(t, err)
+	defer func() { _ = events.Close() }()
+
+	var cursors []string
+	for i := 0; i < 3; i++ {
+		var event fauna.Event
+		require.NoError(t, events.Next(&event))
+		cursors = append(cursors, event.Cursor)
+	}
+	require.Equal(t, []string{"b", "d", "e"}, cursors)
+}
+
+func TestStreamIdleTimeout(t *testing.T) {
+	var cursors []string
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		cursors = append(cursors, string(body))
+
+		_, _ = w.Write([]byte(`{"type":"status","txn_ts":1,"cursor":"a"}` + "\n"))
+		w.(http.Flusher).Flush()
+
+		// go quiet without closing the connection
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	events, err := client.Stream("token", fauna.StreamIdleTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	defer func() { _ = events.Close() }()
+
+	var event fauna.Event
+	require.NoError(t, events.Next(&event))
+	require.Equal(t, "a", event.Cursor)
+
+	var idleErr *fauna.ErrStreamIdle
+	require.ErrorAs(t, events.Next(&event), &idleErr)
+	require.NoError(t, idleErr.Err)
+	require.Equal(t, 50*time.Millisecond, idleErr.Timeout)
+
+	require.Len(t, cursors, 2)
+	require.Contains(t, cursors[1], `"cursor":"a"`)
+
+	require.NoError(t, events.Next(&event))
+	require.Equal(t, "a", event.Cursor)
+}
+
+func TestStreamIdleTimeoutIgnoresTimeBetweenReads(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"type":"status","txn_ts":1,"cursor":"a"}` + "\n"))
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"type":"status","txn_ts":2,"cursor":"b"}` + "\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	events, err := client.Stream("token", fauna.StreamIdleTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	defer func() { _ = events.Close() }()
+
+	var event fauna.Event
+	require.NoError(t, events.Next(&event))
+	require.Equal(t, "a", event.Cursor)
+
+	// the application is busy, not the connection
+	time.Sleep(200 * time.Millisecond)
+	close(release)
+
+	require.NoError(t, events.Next(&event))
+	require.Equal(t, "b", event.Cursor)
+}
+
+func TestStreamFields(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.URL.Path, "/query/") {
+			queries = append(queries, string(body))
+			if strings.Contains(string(body), "result {") {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":{"code":"invalid_query","message":"projection not supported"},"stats":{}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"@stream":"token"},"stats":{}}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"type":"status","txn_ts":1,"cursor":"a"}
+{"type":"add","txn_ts":2,"cursor":"b","data":{"@doc":{"id":"1","coll":{"@mod":"Product"},"ts":{"@time":"2024-01-01T00:00:00Z"},"name":"limes","price":{"@int":"2"},"stock":{"warehouse":{"@int":"10"},"shelf":{"@int":"3"}}}}}
+`))
+	})
+
+	q, err := fauna.FQL(`Product.all().eventSource()`, nil)
+	require.NoError(t, err)
+
+	events, err := client.StreamFromQuery(q, []fauna.StreamOptFn{fauna.StreamFields("id", "name", "stock.shelf", "missing")})
+	require.NoError(t, err)
+	defer func() { _ = events.Close() }()
+
+	// the projected query is rejected, so it is run as is
+	require.Len(t, queries, 2)
+	require.Contains(t, queries[0], `result { id, name, stock { shelf }, missing }`)
+	require.NotContains(t, queries[1], "result")
+
+	var event fauna.Event
+	require.NoError(t, events.Next(&event))
+	require.Nil(t, event.Data)
+
+	require.NoError(t, events.Next(&event))
+	require.Equal(t, map[string]any{
+		"id":      "1",
+		"name":    "limes",
+		"stock":   map[string]any{"shelf": int64(3)},
+		"missing": nil,
+	}, event.Data)
+
+	var product struct {
+		ID    string `fauna:"id"`
+		Name  string `fauna:"name"`
+		Price int    `fauna:"price"`
+	}
+	require.NoError(t, event.Unmarshal(&product))
+	require.Equal(t, "limes", product.Name)
+	require.Zero(t, product.Price)
+
+	_, err = client.Stream("token", fauna.StreamFields("bad field"))
+	require.EqualError(t, err, `invalid field "bad field"`)
+}
+
+func TestStreamProcess(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"type":"status","txn_ts":1,"cursor":"a"}
+{"type":"add","txn_ts":2,"cursor":"b","data":{"@int":"1"}}
+{"type":"add","txn_ts":3,"cursor":"c","data":{"@int":"2"}}
+{"type":"add","txn_ts":4,"cursor":"d","data":{"@int":"3"}}
+`))
+	})
+
+	t.Run("retries, dead-letters and checkpoints", func(t *testing.T) {
+		events, err := client.Stream("token")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		calls := map[int64]int{}
+		var deadLetters, checkpoints []string
+		err = events.Process(ctx, func(event fauna.Event) error {
+			n := event.Data.(int64)
+			calls[n]++
+			switch {
+			case n == 2:
+				return errors.New("bad event")
+			case n == 3 && calls[n] == 1:
+				return errors.New("try again")
+			case n == 3:
+				cancel()
+			}
+			return nil
+		},
+			fauna.ProcessBackoff(time.Millisecond),
+			fauna.ProcessDeadLetter(func(event fauna.Event, err error) {
+				deadLetters = append(deadLetters, event.Cursor+": "+err.Error())
+			}),
+			fauna.ProcessCheckpoint(func(cursor string) error {
+				checkpoints = append(checkpoints, cursor)
+				return nil
+			}),
+		)
+		require.ErrorIs(t, err, context.Canceled)
+
+		require.Equal(t, map[int64]int{1: 1, 2: 3, 3: 2}, calls)
+		require.Equal(t, []string{"c: bad event"}, deadLetters)
+		require.Equal(t, []string{"b", "c", "d"}, checkpoints)
+	})
+
+	t.Run("returns the error without a dead letter", func(t *testing.T) {
+		events, err := client.Stream("token")
+		require.NoError(t, err)
+		defer func() { _ = events.Close() }()
+
+		var calls int
+		err = events.Process(context.Background(), func(fauna.Event) error {
+			calls++
+			return errors.New("bad event")
+		}, fauna.ProcessAttempts(2), fauna.ProcessBackoff(0))
+		require.EqualError(t, err, "bad event")
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("stops on a failed checkpoint", func(t *testing.T) {
+		events, err := client.Stream("token")
+		require.NoError(t, err)
+		defer func() { _ = events.Close() }()
+
+		err = events.Process(context.Background(), func(fauna.Event) error { return nil },
+			fauna.ProcessCheckpoint(func(string) error { return errors.New("disk full") }))
+		require.EqualError(t, err, "checkpoint failed: disk full")
+	})
+}
@@ -1,8 +1,9 @@
 package fauna
 
 import (
-	"fmt"
 	"regexp"
+	"strings"
+	"unicode/utf8"
 )
 
 type templateCategory string
@@ -22,6 +23,8 @@ var (
 type templatePart struct {
 	Text     string
 	Category templateCategory
+	// Position is the byte offset of the part in the template.
+	Position int
 }
 
 // Parse parses Text and returns a slice of template parts.
@@ -35,13 +38,11 @@ func parseTemplate(text string) ([]templatePart, error) {
 
 	for i, m := range matches {
 		matchIndex := matchIndexes[i]
-		invalidStartPos := matchIndex[invalidIndex*2]
-		if invalidStartPos >= 0 {
-			// TODO: Improve with line/column num
-			return nil, fmt.Errorf("invalid placeholder in template: position %d", invalidStartPos)
+		matchStartPos := matchIndex[0]
+		if matchIndex[invalidIndex*2] >= 0 {
+			return nil, newErrTemplate(text, matchStartPos, "invalid placeholder in template")
 		}
 
-		matchStartPos := matchIndex[0]
 		matchEndPos := matchIndex[1]
 		escaped := m[escapedIndex]
 		variable := m[bracedIndex]
@@ -50,6 +51,7 @@ func parseTemplate(text string) ([]templatePart, error) {
 			parts = append(parts, templatePart{
 				Text:     text[currentPosition:matchStartPos] + escaped,
 				Category: templateLiteral,
+				Position: currentPosition,
 			})
 		}
 
@@ -57,6 +59,7 @@ func parseTemplate(text string) ([]templatePart, error) {
 			parts = append(parts, templatePart{
 				Text:     variable,
 				Category: templateVariable,
+				Position: matchStartPos,
 			})
 		}
 
@@ -64,8 +67,29 @@ func parseTemplate(text string) ([]templatePart, error) {
 	}
 
 	if currentPosition < end {
-		parts = append(parts, templatePart{Text: text[currentPosition:], Category: templateLiteral})
+		parts = append(parts, templatePart{Text: text[currentPosition:], Category: templateLiteral, Position: currentPosition})
 	}
 
 	return parts, nil
 }
+
+// newErrTemplate returns an [ErrTemplate] for a problem at offset in text.
+func newErrTemplate(text string, offset int, message string) *ErrTemplate {
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	lineEnd := strings.IndexByte(text[offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(text)
+	} else {
+		lineEnd += offset
+	}
+
+	return &ErrTemplate{
+		Message: message,
+		Position: TemplatePosition{
+			Offset: offset,
+			Line:   strings.Count(text[:lineStart], "\n") + 1,
+			Column: utf8.RuneCountInString(text[lineStart:offset]) + 1,
+		},
+		Line: strings.TrimSuffix(text[lineStart:lineEnd], "\r"),
+	}
+}
@@ -24,10 +24,12 @@ func TestTemplate_ParseSuccess(t *testing.T) {
 				{
 					"let x = ",
 					templateLiteral,
+					0,
 				},
 				{
 					"my_var",
 					templateVariable,
+					8,
 				},
 			},
 		},
@@ -37,22 +39,27 @@ func TestTemplate_ParseSuccess(t *testing.T) {
 				{
 					"let x = ",
 					templateLiteral,
+					0,
 				},
 				{
 					"my_var",
 					templateVariable,
+					8,
 				},
 				{
 					"\nlet y = ",
 					templateLiteral,
+					17,
 				},
 				{
 					"my_var",
 					templateVariable,
+					26,
 				},
 				{
 					"\nx * y",
 					templateLiteral,
+					35,
 				},
 			},
 		},
@@ -62,10 +69,12 @@ func TestTemplate_ParseSuccess(t *testing.T) {
 				{
 					"my_var",
 					templateVariable,
+					0,
 				},
 				{
 					" { .name }",
 					templateLiteral,
+					9,
 				},
 			},
 		},
@@ -75,10 +84,12 @@ func TestTemplate_ParseSuccess(t *testing.T) {
 				{
 					"let x = '$",
 					templateLiteral,
+					0,
 				},
 				{
 					"{not_a_var}'",
 					templateLiteral,
+					11,
 				},
 			},
 		},
@@ -96,6 +107,7 @@ func TestTemplate_ParseSuccess(t *testing.T) {
 			expected := (*tc.wants)[i]
 			assert.Equal(t, expected.Text, tp.Text)
 			assert.Equal(t, expected.Category, tp.Category)
+			assert.Equal(t, expected.Position, tp.Position)
 		}
 	}
 }
@@ -104,7 +116,11 @@ func TestTemplate_ParseFail(t *testing.T) {
 	testCases := []TemplateErrorCase{
 		{
 			"let x = ${かわいい}",
-			"invalid placeholder in template: position 9",
+			"invalid placeholder in template at line 1, column 9:\nlet x = ${かわいい}\n        ^",
+		},
+		{
+			"let x = 1\n\tlet y = 'ü' + $y\r\nx",
+			"invalid placeholder in template at line 2, column 16:\n\tlet y = 'ü' + $y\n\t              ^",
 		},
 	}
 
@@ -115,3 +131,14 @@ func TestTemplate_ParseFail(t *testing.T) {
 		}
 	}
 }
+
+func TestTemplate_ErrTemplate(t *testing.T) {
+	_, err := parseTemplate("let x = 1\nlet y = 'ü' + $y")
+
+	var errTemplate *ErrTemplate
+	if assert.ErrorAs(t, err, &errTemplate) {
+		assert.Equal(t, "invalid placeholder in template", errTemplate.Message)
+		assert.Equal(t, TemplatePosition{Offset: 25, Line: 2, Column: 15}, errTemplate.Position)
+		assert.Equal(t, "let y = 'ü' + $y", errTemplate.Line)
+	}
+}
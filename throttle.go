@@ -0,0 +1,123 @@
+package fauna
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// throttleMinRate is the lowest rate adaptive throttling slows down to, in
+	// requests per second.
+	throttleMinRate = 1
+	// throttleDecreaseInterval is how long adaptive throttling waits after
+	// slowing down before doing so again, so that the responses to requests
+	// sent at the previous rate don't slow it down further.
+	throttleDecreaseInterval = time.Second
+)
+
+// WithAdaptiveThrottling makes the [fauna.Client] shape the rate it sends
+// requests at to what Fauna accepts, so that bulk jobs settle at a sustainable
+// rate rather than alternating between bursts and throttled requests.
+//
+// Requests are sent freely until one is throttled with a 429 response. The
+// client then halves the rate it was sending requests at, and raises it again
+// by about one request per second every second while requests succeed,
+// halving it whenever they are throttled. Requests wait for their turn at
+// that rate, including retries.
+func WithAdaptiveThrottling() ClientConfigFn {
+	return func(c *Client) { c.throttle = &adaptiveThrottle{} }
+}
+
+// adaptiveThrottle paces requests at a rate adjusted with additive increase
+// and multiplicative decrease.
+type adaptiveThrottle struct {
+	mu sync.Mutex
+	// rate is in requests per second, and zero until a request is throttled.
+	rate         float64
+	next         time.Time
+	lastDecrease time.Time
+
+	// the number of requests sent in the current and previous second, to
+	// estimate the rate before the first throttled request
+	windowStart      time.Time
+	sent, sentBefore int
+}
+
+// wait waits for the turn of a request to be sent, until ctx is done.
+func (t *adaptiveThrottle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	now := time.Now()
+	t.count(now)
+
+	var delay time.Duration
+	if t.rate > 0 {
+		if t.next.Before(now) {
+			t.next = now
+		}
+		delay = t.next.Sub(now)
+		t.next = t.next.Add(time.Duration(float64(time.Second) / t.rate))
+	}
+	t.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// count counts a request sent at now.
+func (t *adaptiveThrottle) count(now time.Time) {
+	switch elapsed := now.Sub(t.windowStart); {
+	case elapsed >= 2*time.Second:
+		t.windowStart, t.sent, t.sentBefore = now, 0, 0
+	case elapsed >= time.Second:
+		t.windowStart, t.sent, t.sentBefore = t.windowStart.Add(time.Second), 0, t.sent
+	}
+	t.sent++
+}
+
+// observe adjusts the rate after a response, which was throttled or not.
+func (t *adaptiveThrottle) observe(throttled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !throttled {
+		if t.rate > 0 {
+			t.rate += 1 / t.rate
+		}
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(t.lastDecrease) < throttleDecreaseInterval {
+		return
+	}
+	t.lastDecrease = now
+
+	rate := t.rate
+	if rate == 0 {
+		rate = float64(t.sent)
+		if t.sentBefore > t.sent {
+			rate = float64(t.sentBefore)
+		}
+	}
+	if t.rate = rate / 2; t.rate < throttleMinRate {
+		t.rate = throttleMinRate
+	}
+}
+
+// currentRate returns the rate requests are sent at, or zero if unlimited.
+func (t *adaptiveThrottle) currentRate() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rate
+}
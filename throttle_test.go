@@ -0,0 +1,48 @@
+package fauna_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveThrottling(t *testing.T) {
+	throttled := false
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		if throttled {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":"limit_exceeded","message":"Rate limit exceeded"},"stats":{}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxAttempts(1), fauna.WithAdaptiveThrottling())
+	q, _ := fauna.FQL(`null`, nil)
+
+	for i := 0; i < 9; i++ {
+		_, err := client.Query(q)
+		require.NoError(t, err)
+	}
+	require.Zero(t, client.Stats().ThrottledRate, "requests are sent freely until throttled")
+
+	throttled = true
+	_, err := client.Query(q)
+	require.ErrorAs(t, err, new(*fauna.ErrThrottling))
+	require.Equal(t, 5.0, client.Stats().ThrottledRate, "the rate is halved")
+
+	// a burst of throttled requests halves the rate once
+	_, err = client.Query(q)
+	require.ErrorAs(t, err, new(*fauna.ErrThrottling))
+	require.Equal(t, 5.0, client.Stats().ThrottledRate)
+
+	throttled = false
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := client.Query(q)
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 350*time.Millisecond, "requests are paced at 5 per second")
+	require.InDelta(t, 5.4, client.Stats().ThrottledRate, 0.01, "successes raise the rate")
+}
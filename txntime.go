@@ -14,11 +14,19 @@ func (t *txnTime) get() int64 {
 }
 
 func (t *txnTime) sync(newTxnTime int64) {
+	t.advance(newTxnTime)
+}
+
+// advance moves t forward to newTxnTime, returning the value it replaced and
+// whether it did.
+func (t *txnTime) advance(newTxnTime int64) (oldTxnTime int64, advanced bool) {
 	for {
-		oldTxnTime := t.value.Load()
-		if oldTxnTime >= newTxnTime ||
-			t.value.CompareAndSwap(oldTxnTime, newTxnTime) {
-			break
+		oldTxnTime = t.value.Load()
+		if oldTxnTime >= newTxnTime {
+			return oldTxnTime, false
+		}
+		if t.value.CompareAndSwap(oldTxnTime, newTxnTime) {
+			return oldTxnTime, true
 		}
 	}
 }
@@ -21,6 +21,16 @@ func TestTxnTime(t *testing.T) {
 	require.Equal(t, "42", txnTime.string())
 }
 
+func TestTxnTimeAccessors(t *testing.T) {
+	ts := time.Date(2023, 2, 28, 18, 10, 10, 123456000, time.UTC)
+
+	event := Event{TxnTime: ts.UnixMicro()}
+	require.Equal(t, ts, event.Time())
+
+	info := QueryInfo{TxnTime: ts.UnixMicro()}
+	require.Equal(t, ts, info.Time())
+}
+
 func BenchmarkTxnTime(b *testing.B) {
 	txnTime := txnTime{}
 	b.RunParallel(func(pb *testing.PB) {
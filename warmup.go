@@ -0,0 +1,97 @@
+package fauna
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type warmupOptions struct {
+	pingInterval time.Duration
+}
+
+// Warmup opens up to n connections to Fauna ahead of the first queries, such
+// as when a serverless function starts, so that they don't wait for TCP and
+// TLS handshakes. It runs n trivial queries at once, and returns the error of
+// the first that failed, if any.
+//
+// Connections are only kept while idle if the client allows it, see
+// [MaxIdleConnsPerHost]. Over HTTP/2, which serves concurrent requests on a
+// single connection, a single connection is opened per endpoint.
+func (c *Client) Warmup(ctx context.Context, n int, opts ...WarmupOptFn) error {
+	if n <= 0 {
+		return fmt.Errorf("warmup connections must be positive, got %d", n)
+	}
+
+	options := warmupOptions{}
+	for _, optFn := range opts {
+		optFn(&options)
+	}
+
+	// resolve the query URL before querying concurrently
+	if _, err := c.parseQueryURL(); err != nil {
+		return err
+	}
+
+	err := c.warm(ctx, n)
+
+	if options.pingInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(options.pingInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					c.ping(n, options.pingInterval)
+				}
+			}
+		}()
+	}
+
+	return err
+}
+
+// warm runs n trivial queries at once.
+func (c *Client) warm(ctx context.Context, n int) error {
+	q, err := FQL(`null`, nil)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := c.Query(q, QueryContext(ctx)); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// ping warms the connections up again, with a context of its own, as
+// canceling a request closes its connection. A failed ping is retried on the
+// next tick.
+func (c *Client) ping(n int, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_ = c.warm(ctx, n)
+}
@@ -0,0 +1,36 @@
+package fauna_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmup(t *testing.T) {
+	var requests atomic.Int64
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"data":null,"txn_ts":1,"stats":{}}`))
+	}, fauna.MaxIdleConnsPerHost(4))
+
+	require.EqualError(t, client.Warmup(context.Background(), 0), "warmup connections must be positive, got 0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, client.Warmup(ctx, 3, fauna.WarmupPing(30*time.Millisecond)))
+	require.Equal(t, int64(3), requests.Load())
+	require.Equal(t, int64(3), client.Stats().OpenConnections)
+
+	require.Eventually(t, func() bool { return requests.Load() >= 6 }, time.Second, 5*time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	pinged := requests.Load()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, pinged, requests.Load())
+	require.Equal(t, int64(3), client.Stats().OpenConnections, "pings reuse the warm connections")
+}